@@ -4,6 +4,8 @@ import (
 	"flag"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -12,6 +14,7 @@ import (
 
 	"github.com/Admiral-Piett/goaws/app/conf"
 	"github.com/Admiral-Piett/goaws/app/gosqs"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/router"
 )
 
@@ -61,6 +64,22 @@ func main() {
 	quit := make(chan struct{}, 0)
 	go gosqs.PeriodicTasks(1*time.Second, quit)
 
+	if persistence.Enabled() {
+		interval := time.Duration(app.CurrentEnvironment.Persistence.SnapshotInterval) * time.Second
+		go persistence.PeriodicSnapshots(interval, quit)
+
+		// Write a last snapshot on the way out so nothing is left in the journal only.
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			if err := persistence.Close(); err != nil {
+				log.Errorf("Failed to close persistence: %s", err)
+			}
+			os.Exit(0)
+		}()
+	}
+
 	if len(portNumbers) == 1 {
 		log.Warnf("GoAws listening on: 0.0.0.0:%s", portNumbers[0])
 		err := http.ListenAndServe("0.0.0.0:"+portNumbers[0], r)
@@ -22,6 +22,9 @@ type EnvQueue struct {
 	MaximumMessageSize            int
 	VisibilityTimeout             int
 	MessageRetentionPeriod        int
+	Tags                          map[string]string
+	Policy                        string
+	RedriveAllowPolicy            string
 }
 
 type EnvQueueAttributes struct {
@@ -31,6 +34,45 @@ type EnvQueueAttributes struct {
 	MessageRetentionPeriod        int // seconds
 }
 
+// EnvPersistence configures the optional on-disk store.  When enabled, queues, topics and messages are
+// snapshotted into Directory every SnapshotInterval seconds, with an append-only journal of the writes
+// made in between, and restored from there on startup.
+type EnvPersistence struct {
+	Enabled          bool
+	Directory        string
+	SnapshotInterval int // seconds
+}
+
+// EnvAuthentication turns on AWS Signature Version 4 verification.  When enabled, every request has to
+// be signed by one of the Credentials, for the configured Region, or it is rejected.
+type EnvAuthentication struct {
+	Enabled     bool
+	Credentials []EnvCredential
+}
+
+type EnvCredential struct {
+	AccessKeyId     string
+	SecretAccessKey string
+	AccountId       string // the account signing with it is a caller of, the configured AccountID if empty
+}
+
+// EnvPolicyEvaluation turns on the evaluation of queue policies.  Requests to a queue are then rejected
+// with AccessDenied unless they come from the queue's own account or its Policy allows them.  Callers are
+// of the account of the credential their request is signed with, or of CallerAccountId without
+// Authentication.  SNS only delivers to queues whose Policy allows `sns.amazonaws.com` to `sqs:SendMessage`.
+type EnvPolicyEvaluation struct {
+	Enabled         bool
+	CallerAccountId string // the configured AccountID if empty
+}
+
+// EnvFifoThrottling simulates the throughput limit of FIFO queues.  When enabled, sends beyond
+// TransactionsPerSecond to a FIFO queue - or to a single message group of a queue whose FifoThroughputLimit
+// is perMessageGroupId - are rejected with a ThrottlingException.  A batch counts as one transaction.
+type EnvFifoThrottling struct {
+	Enabled               bool
+	TransactionsPerSecond int
+}
+
 type Environment struct {
 	Host                   string
 	Port                   string
@@ -40,11 +82,15 @@ type Environment struct {
 	AccountID              string
 	LogToFile              bool
 	LogFile                string
-	EnableDuplicates       bool
+	EnableDuplicates       bool // Deprecated: FIFO queues always deduplicate, it is ignored
 	Topics                 []EnvTopic
 	Queues                 []EnvQueue
 	QueueAttributeDefaults EnvQueueAttributes
 	RandomLatency          RandomLatency
+	Persistence            EnvPersistence
+	Authentication         EnvAuthentication
+	FifoThrottling         EnvFifoThrottling
+	PolicyEvaluation       EnvPolicyEvaluation
 }
 
 // CurrentEnvironment should get overwritten when the app starts up and loads the config.  For the
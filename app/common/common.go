@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"hash"
 	"io"
+	"regexp"
 	"sort"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -37,6 +38,16 @@ func GetMD5Hash(text string) string {
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
+// The transport types of the attribute values, as they go into the MD5 of the message attributes.
+const (
+	stringTransportType     byte = 1
+	binaryTransportType     byte = 2
+	stringListTransportType byte = 3
+	binaryListTransportType byte = 4
+)
+
+// HashAttributes is the MD5 of message attributes the way the SDKs compute it to check them: every attribute
+// by name, with its name, DataType, transport type and value(s) - binary ones decoded.
 func HashAttributes(attributes map[string]app.MessageAttributeValue) string {
 	hasher := md5.New()
 
@@ -46,13 +57,24 @@ func HashAttributes(attributes map[string]app.MessageAttributeValue) string {
 
 		addStringToHash(hasher, key)
 		addStringToHash(hasher, attributeValue.DataType)
-		if attributeValue.ValueKey == "StringValue" {
-			hasher.Write([]byte{1})
-			addStringToHash(hasher, attributeValue.Value)
-		} else if attributeValue.ValueKey == "BinaryValue" {
-			hasher.Write([]byte{2})
-			bytes, _ := base64.StdEncoding.DecodeString(attributeValue.Value)
-			addBytesToHash(hasher, bytes)
+		isBinary := attributeValue.BaseType() == "Binary"
+		switch {
+		case isBinary && attributeValue.BinaryValue != "":
+			hasher.Write([]byte{binaryTransportType})
+			addBase64ToHash(hasher, attributeValue.BinaryValue)
+		case isBinary && len(attributeValue.BinaryListValues) > 0:
+			hasher.Write([]byte{binaryListTransportType})
+			for _, value := range attributeValue.BinaryListValues {
+				addBase64ToHash(hasher, value)
+			}
+		case !isBinary && attributeValue.StringValue != "":
+			hasher.Write([]byte{stringTransportType})
+			addStringToHash(hasher, attributeValue.StringValue)
+		case !isBinary && len(attributeValue.StringListValues) > 0:
+			hasher.Write([]byte{stringListTransportType})
+			for _, value := range attributeValue.StringListValues {
+				addStringToHash(hasher, value)
+			}
 		}
 	}
 
@@ -73,9 +95,65 @@ func addStringToHash(hasher hash.Hash, str string) {
 	addBytesToHash(hasher, bytes)
 }
 
+func addBase64ToHash(hasher hash.Hash, value string) {
+	bytes, _ := base64.StdEncoding.DecodeString(value)
+	addBytesToHash(hasher, bytes)
+}
+
 func addBytesToHash(hasher hash.Hash, arr []byte) {
 	bs := make([]byte, 4)
 	binary.BigEndian.PutUint32(bs, uint32(len(arr)))
 	hasher.Write(bs)
 	hasher.Write(arr)
 }
+
+// MessageSize is the size AWS counts against the limits, the body plus the name, data type and value of each attribute.
+func MessageSize(body string, attributes map[string]app.MessageAttributeValue) int {
+	size := len(body)
+	for name, attr := range attributes {
+		size += len(name) + len(attr.DataType) + len(attr.StringValue) + binarySize(attr.BinaryValue)
+		for _, v := range attr.StringListValues {
+			size += len(v)
+		}
+		for _, v := range attr.BinaryListValues {
+			size += binarySize(v)
+		}
+	}
+	return size
+}
+
+// The most entries AWS takes in one batch request, of SQS and SNS alike.
+const MaxBatchEntries = 10
+
+var batchEntryIdRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,80}$`)
+
+// ValidateBatchEntryIds returns the key of the error for the entry IDs of a batch request, if there is one.
+// The keys are the same in `models.SqsErrors` and `models.SnsErrors`.
+func ValidateBatchEntryIds(ids []string) string {
+	if len(ids) == 0 {
+		return "EmptyBatchRequest"
+	}
+	if len(ids) > MaxBatchEntries {
+		return "TooManyEntriesInBatchRequest"
+	}
+	seen := map[string]struct{}{}
+	for _, id := range ids {
+		if !batchEntryIdRegexp.MatchString(id) {
+			return "InvalidBatchEntryId"
+		}
+		if _, ok := seen[id]; ok {
+			return "BatchEntryIdsNotDistinct"
+		}
+		seen[id] = struct{}{}
+	}
+	return ""
+}
+
+// binarySize is the size of the bytes of a base64 encoded binary value.
+func binarySize(value string) int {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return len(value)
+	}
+	return len(decoded)
+}
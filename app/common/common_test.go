@@ -1,6 +1,7 @@
 package common
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -38,3 +39,34 @@ func TestSortedKeys(t *testing.T) {
 	assert.Equal(t, "a", keys[0])
 	assert.Equal(t, "b", keys[1])
 }
+
+func TestHashAttributes(t *testing.T) {
+	cases := map[string]app.MessageAttributeValue{
+		"bef8bf215374a0a228d67533b1a488be": {DataType: "String", StringValue: "b"},
+		"e83a46a57d9e43f5b76c6ba879241db0": {DataType: "Binary", BinaryValue: "AQI="},
+		"8e6b7a41a2b85f31e4c373d18b446a1d": {DataType: "String", StringListValues: []string{"b", "c"}},
+		"c4a4a29428ec6c47780627233be7651d": {DataType: "Binary.gif", BinaryListValues: []string{"AQI=", "Aw=="}},
+	}
+
+	for expected, value := range cases {
+		assert.Equal(t, expected, HashAttributes(map[string]app.MessageAttributeValue{"a": value}))
+	}
+}
+
+func TestValidateBatchEntryIds(t *testing.T) {
+	assert.Equal(t, "", ValidateBatchEntryIds([]string{"id-1", "id_2"}))
+	assert.Equal(t, "EmptyBatchRequest", ValidateBatchEntryIds([]string{}))
+	assert.Equal(t, "TooManyEntriesInBatchRequest", ValidateBatchEntryIds(make([]string, 11)))
+	assert.Equal(t, "InvalidBatchEntryId", ValidateBatchEntryIds([]string{"id 1"}))
+	assert.Equal(t, "InvalidBatchEntryId", ValidateBatchEntryIds([]string{strings.Repeat("i", 81)}))
+	assert.Equal(t, "BatchEntryIdsNotDistinct", ValidateBatchEntryIds([]string{"id-1", "id-1"}))
+}
+
+func TestMessageSize(t *testing.T) {
+	size := MessageSize("body", map[string]app.MessageAttributeValue{
+		"a": {DataType: "String", StringValue: "value", StringListValues: []string{"v1", "v2"}},
+		"b": {DataType: "Binary", BinaryValue: "AQI=", BinaryListValues: []string{"Aw=="}},
+	})
+
+	assert.Equal(t, 4+(1+6+5+4)+(1+6+2+1), size)
+}
@@ -0,0 +1,29 @@
+package common
+
+import (
+	"encoding/base64"
+	"sort"
+)
+
+// Paginate finds the page of the sorted keys that a list request asked for. The next token is opaque to the
+// clients, it's the key the previous page ended on, so pages stay stable while items come and go in between.
+// It returns the bounds of the page, the token of the following one - empty on the last page - and false if
+// the given token isn't one of ours.
+func Paginate(keys []string, nextToken string, pageSize int) (start int, end int, next string, ok bool) {
+	if nextToken != "" {
+		lastKey, err := base64.RawURLEncoding.DecodeString(nextToken)
+		if err != nil || len(lastKey) == 0 {
+			return 0, 0, "", false
+		}
+		start = sort.Search(len(keys), func(i int) bool {
+			return keys[i] > string(lastKey)
+		})
+	}
+
+	end = len(keys)
+	if pageSize > 0 && start+pageSize < end {
+		end = start + pageSize
+		next = base64.RawURLEncoding.EncodeToString([]byte(keys[end-1]))
+	}
+	return start, end, next, true
+}
@@ -0,0 +1,60 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaginate_walks_every_page(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+
+	start, end, next, ok := Paginate(keys, "", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"a", "b"}, keys[start:end])
+	assert.NotEmpty(t, next)
+
+	start, end, next, ok = Paginate(keys, next, 2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"c", "d"}, keys[start:end])
+	assert.NotEmpty(t, next)
+
+	start, end, next, ok = Paginate(keys, next, 2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"e"}, keys[start:end])
+	assert.Empty(t, next)
+}
+
+func TestPaginate_no_page_size_returns_everything(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+
+	start, end, next, ok := Paginate(keys, "", 0)
+	assert.True(t, ok)
+	assert.Equal(t, keys, keys[start:end])
+	assert.Empty(t, next)
+}
+
+func TestPaginate_exact_last_page_has_no_token(t *testing.T) {
+	keys := []string{"a", "b"}
+
+	start, end, next, ok := Paginate(keys, "", 2)
+	assert.True(t, ok)
+	assert.Equal(t, keys, keys[start:end])
+	assert.Empty(t, next)
+}
+
+func TestPaginate_resumes_after_a_removed_key(t *testing.T) {
+	keys := []string{"a", "b", "c", "d"}
+
+	_, _, next, _ := Paginate(keys, "", 2)
+
+	remaining := []string{"a", "c", "d"}
+	start, end, _, ok := Paginate(remaining, next, 2)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"c", "d"}, remaining[start:end])
+}
+
+func TestPaginate_invalid_token(t *testing.T) {
+	_, _, _, ok := Paginate([]string{"a"}, "not a token!", 2)
+	assert.False(t, ok)
+}
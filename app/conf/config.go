@@ -14,6 +14,7 @@ import (
 
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/common"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/ghodss/yaml"
 )
 
@@ -70,6 +71,10 @@ func LoadYamlConfig(filename string, env string) []string {
 		app.CurrentEnvironment.Port = envs[env].SqsPort
 	}
 
+	if app.CurrentEnvironment.EnableDuplicates {
+		log.Warn("EnableDuplicates is deprecated and ignored, FIFO queues always deduplicate messages")
+	}
+
 	common.LogMessages = false
 	common.LogFile = "./goaws_messages.log"
 
@@ -105,8 +110,22 @@ func LoadYamlConfig(filename string, env string) []string {
 		app.CurrentEnvironment.Port = "4100"
 	}
 
+	if app.CurrentEnvironment.Persistence.Directory == "" {
+		app.CurrentEnvironment.Persistence.Directory = "./.goaws"
+	}
+
+	if app.CurrentEnvironment.Persistence.SnapshotInterval <= 0 {
+		app.CurrentEnvironment.Persistence.SnapshotInterval = 60
+	}
+
+	// AWS' limit for FIFO queues without batching.
+	if app.CurrentEnvironment.FifoThrottling.TransactionsPerSecond <= 0 {
+		app.CurrentEnvironment.FifoThrottling.TransactionsPerSecond = 300
+	}
+
 	app.SyncQueues.Lock()
 	app.SyncTopics.Lock()
+	now := time.Now()
 	for _, queue := range envs[env].Queues {
 		queueUrl := "http://" + app.CurrentEnvironment.Host + ":" + app.CurrentEnvironment.Port +
 			"/" + app.CurrentEnvironment.AccountID + "/" + queue.Name
@@ -132,6 +151,27 @@ func LoadYamlConfig(filename string, env string) []string {
 			queue.MessageRetentionPeriod = app.CurrentEnvironment.QueueAttributeDefaults.MessageRetentionPeriod
 		}
 
+		var tags map[string]string
+		if len(queue.Tags) > 0 {
+			tags = make(map[string]string, len(queue.Tags))
+			for key, value := range queue.Tags {
+				tags[key] = value
+			}
+		}
+
+		if queue.Policy != "" {
+			if _, err := app.ParsePolicy(queue.Policy); err != nil {
+				log.Errorf("Ignoring the invalid Policy of queue %s: %s", queue.Name, err)
+				queue.Policy = ""
+			}
+		}
+		if queue.RedriveAllowPolicy != "" {
+			if _, err := app.ParseRedriveAllowPolicy(queue.RedriveAllowPolicy); err != nil {
+				log.Errorf("Ignoring the invalid RedriveAllowPolicy of queue %s: %s", queue.Name, err)
+				queue.RedriveAllowPolicy = ""
+			}
+		}
+
 		app.SyncQueues.Queues[queue.Name] = &app.Queue{
 			Name:                          queue.Name,
 			VisibilityTimeout:             queue.VisibilityTimeout,
@@ -141,8 +181,12 @@ func LoadYamlConfig(filename string, env string) []string {
 			MaximumMessageSize:            queue.MaximumMessageSize,
 			MessageRetentionPeriod:        queue.MessageRetentionPeriod,
 			IsFIFO:                        app.HasFIFOQueueName(queue.Name),
-			EnableDuplicates:              app.CurrentEnvironment.EnableDuplicates,
-			Duplicates:                    make(map[string]time.Time),
+			Duplicates:                    make(map[string]app.Deduplicated),
+			Tags:                          tags,
+			Policy:                        queue.Policy,
+			RedriveAllowPolicy:            queue.RedriveAllowPolicy,
+			Created:                       now,
+			LastModified:                  now,
 		}
 	}
 
@@ -162,7 +206,7 @@ func LoadYamlConfig(filename string, env string) []string {
 	for _, topic := range envs[env].Topics {
 		topicArn := "arn:aws:sns:" + app.CurrentEnvironment.Region + ":" + app.CurrentEnvironment.AccountID + ":" + topic.Name
 
-		newTopic := &app.Topic{Name: topic.Name, Arn: topicArn}
+		newTopic := &app.Topic{Name: topic.Name, Arn: topicArn, FifoTopic: app.HasFIFOTopicName(topic.Name)}
 		newTopic.Subscriptions = make([]*app.Subscription, 0, 0)
 
 		for _, subs := range topic.Subscriptions {
@@ -191,6 +235,14 @@ func LoadYamlConfig(filename string, env string) []string {
 	app.SyncQueues.Unlock()
 	app.SyncTopics.Unlock()
 
+	// Anything saved by a previous run goes on top of the configured resources.
+	if app.CurrentEnvironment.Persistence.Enabled {
+		err = persistence.Open(app.CurrentEnvironment.Persistence)
+		if err != nil {
+			log.Errorf("err: %s", err)
+		}
+	}
+
 	return ports
 }
 
@@ -211,6 +263,7 @@ func createSqsSubscription(configSubscription app.EnvSubsciption, topicArn strin
 				app.CurrentEnvironment.Port + "/" + app.CurrentEnvironment.AccountID + "/" + configSubscription.QueueName
 		}
 		queueArn := "arn:aws:sqs:" + app.CurrentEnvironment.Region + ":" + app.CurrentEnvironment.AccountID + ":" + configSubscription.QueueName
+		now := time.Now()
 		app.SyncQueues.Queues[configSubscription.QueueName] = &app.Queue{
 			Name:                          configSubscription.QueueName,
 			VisibilityTimeout:             app.CurrentEnvironment.QueueAttributeDefaults.VisibilityTimeout,
@@ -219,8 +272,9 @@ func createSqsSubscription(configSubscription app.EnvSubsciption, topicArn strin
 			ReceiveMessageWaitTimeSeconds: app.CurrentEnvironment.QueueAttributeDefaults.ReceiveMessageWaitTimeSeconds,
 			MaximumMessageSize:            app.CurrentEnvironment.QueueAttributeDefaults.MaximumMessageSize,
 			IsFIFO:                        app.HasFIFOQueueName(configSubscription.QueueName),
-			EnableDuplicates:              app.CurrentEnvironment.EnableDuplicates,
-			Duplicates:                    make(map[string]time.Time),
+			Duplicates:                    make(map[string]app.Deduplicated),
+			Created:                       now,
+			LastModified:                  now,
 		}
 	}
 	qArn := app.SyncQueues.Queues[configSubscription.QueueName].Arn
@@ -262,6 +316,12 @@ func setQueueRedrivePolicy(queues map[string]*app.Queue, q *app.Queue, strRedriv
 	if !ok {
 		return fmt.Errorf("deadletter queue not found")
 	}
+	if deadLetterQueue.IsFIFO != q.IsFIFO {
+		return fmt.Errorf("deadletter queue %s is not the same type of queue as %s", deadLetterQueueName, q.Name)
+	}
+	if !deadLetterQueue.AllowsRedriveFrom(q.Arn) {
+		return fmt.Errorf("deadletter queue %s doesn't allow redrive from %s", deadLetterQueueName, q.Name)
+	}
 	q.DeadLetterQueue = deadLetterQueue
 	q.MaxReceiveCount = maxReceiveCount
 
@@ -80,6 +80,8 @@ func TestConfig_QueueAttributes(t *testing.T) {
 	assert.Equal(t, 128, app.SyncQueues.Queues["local-queue2"].MaximumMessageSize)
 	assert.Equal(t, 150, app.SyncQueues.Queues["local-queue2"].VisibilityTimeout)
 	assert.Equal(t, 245600, app.SyncQueues.Queues["local-queue2"].MessageRetentionPeriod)
+	assert.Equal(t, map[string]string{"team": "platform"}, app.SyncQueues.Queues["local-queue2"].Tags)
+	assert.Nil(t, app.SyncQueues.Queues["local-queue1"].Tags)
 }
 
 func TestConfig_NoQueueAttributeDefaults(t *testing.T) {
@@ -164,3 +166,26 @@ func TestConfig_LoadYamlConfig_invalid_config_loads_nothing(t *testing.T) {
 	assert.Equal(t, []string{"4100"}, ports)
 	assert.Equal(t, app.CurrentEnvironment, app.Environment{})
 }
+
+func TestConfig_setQueueRedrivePolicy_checks_dead_letter_queue(t *testing.T) {
+	source := &app.Queue{Name: "source", Arn: "arn:aws:sqs:us-east-1:100010001000:source"}
+	queues := map[string]*app.Queue{
+		"source":   source,
+		"dlq":      {Name: "dlq", RedriveAllowPolicy: `{"redrivePermission":"byQueue","sourceQueueArns":["arn:aws:sqs:us-east-1:100010001000:other"]}`},
+		"dlq.fifo": {Name: "dlq.fifo", IsFIFO: true},
+		"open-dlq": {Name: "open-dlq"},
+	}
+
+	err := setQueueRedrivePolicy(queues, source, `{"maxReceiveCount": 3, "deadLetterTargetArn":"arn:aws:sqs:us-east-1:100010001000:dlq"}`)
+	assert.NotNil(t, err)
+
+	err = setQueueRedrivePolicy(queues, source, `{"maxReceiveCount": 3, "deadLetterTargetArn":"arn:aws:sqs:us-east-1:100010001000:dlq.fifo"}`)
+	assert.NotNil(t, err)
+
+	var emptyQueue *app.Queue
+	assert.Equal(t, emptyQueue, source.DeadLetterQueue)
+
+	err = setQueueRedrivePolicy(queues, source, `{"maxReceiveCount": 3, "deadLetterTargetArn":"arn:aws:sqs:us-east-1:100010001000:open-dlq"}`)
+	assert.Nil(t, err)
+	assert.Equal(t, "open-dlq", source.DeadLetterQueue.Name)
+}
@@ -95,15 +95,14 @@ var NO_QUEUES_NO_TOPICS_ENVIRONEMENT = app.Environment{
 }
 
 var LOCAL_ENVIRONMENT = app.Environment{
-	Host:             "localhost",
-	Port:             "4200",
-	SqsPort:          "",
-	SnsPort:          "",
-	Region:           "us-east-1",
-	AccountID:        "100010001000",
-	LogToFile:        false,
-	LogFile:          "./goaws_messages.log",
-	EnableDuplicates: false,
+	Host:      "localhost",
+	Port:      "4200",
+	SqsPort:   "",
+	SnsPort:   "",
+	Region:    "us-east-1",
+	AccountID: "100010001000",
+	LogToFile: false,
+	LogFile:   "./goaws_messages.log",
 	Topics: []app.EnvTopic{
 		LOCAL_ENV_TOPIC_1,
 		LOCAL_ENV_TOPIC_2,
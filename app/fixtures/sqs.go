@@ -2,7 +2,6 @@ package fixtures
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/Admiral-Piett/goaws/app"
 
@@ -12,6 +11,8 @@ import (
 var QueueName = "new-queue-1"
 var QueueUrl = fmt.Sprintf("%s/%s", BASE_URL, QueueName)
 var DeadLetterQueueName = "dead-letter-queue-1"
+var QueuePolicy = `{"Statement":[{"Action":"sqs:SendMessage","Effect":"Allow","Principal":"*"}],"Version":"2012-10-17"}`
+var QueueRedriveAllowPolicy = `{"redrivePermission":"allowAll"}`
 
 var FullyPopulatedQueue = &app.Queue{
 	Name: QueueName,
@@ -31,8 +32,11 @@ var FullyPopulatedQueue = &app.Queue{
 	ReceiveMessageWaitTimeSeconds: 4,
 	DelaySeconds:                  1,
 	MaximumMessageSize:            2,
-	MessageRetentionPeriod:        3,
-	Duplicates:                    make(map[string]time.Time),
+	MessageRetentionPeriod:        60,
+	Duplicates:                    make(map[string]app.Deduplicated),
+	Tags:                          map[string]string{"my": "tag"},
+	Policy:                        QueuePolicy,
+	RedriveAllowPolicy:            QueueRedriveAllowPolicy,
 }
 
 var CreateQueueRequest = models.CreateQueueRequest{
@@ -42,17 +46,22 @@ var CreateQueueRequest = models.CreateQueueRequest{
 }
 
 var QueueAttributes = models.QueueAttributes{
-	DelaySeconds:                  1,
-	MaximumMessageSize:            2,
-	MessageRetentionPeriod:        3,
-	Policy:                        map[string]interface{}{"this-is": "the-policy"}, //IAM Policy
+	DelaySeconds:           1,
+	MaximumMessageSize:     2,
+	MessageRetentionPeriod: 60,
+	Policy: map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []interface{}{
+			map[string]interface{}{"Effect": "Allow", "Principal": "*", "Action": "sqs:SendMessage"},
+		},
+	},
 	ReceiveMessageWaitTimeSeconds: 4,
 	VisibilityTimeout:             5,
 	//RedrivePolicy: models.RedrivePolicy{
 	//	MaxReceiveCount:     100,
 	//	DeadLetterTargetArn: fmt.Sprintf("arn:aws:sqs:us-east-1:100010001000:%s", DeadLetterQueueName),
 	//},
-	RedriveAllowPolicy: map[string]interface{}{"this-is": "the-redrive-allow-policy"},
+	RedriveAllowPolicy: map[string]interface{}{"redrivePermission": "allowAll"},
 }
 
 var CreateQueueResult = models.CreateQueueResult{
@@ -103,11 +112,11 @@ var GetQueueAttributesResponse = models.GetQueueAttributesResponse{
 		},
 		models.Attribute{
 			Name:  "CreatedTimestamp",
-			Value: "0000000000",
+			Value: "",
 		},
 		models.Attribute{
 			Name:  "LastModifiedTimestamp",
-			Value: "0000000000",
+			Value: "",
 		},
 		models.Attribute{
 			Name:  "QueueArn",
@@ -31,6 +31,9 @@ func ConfirmSubscriptionV1(req *http.Request) (int, interfaces.AbstractResponseB
 	if pendingConfirm.token != confirmToken {
 		return utils.CreateErrorResponseV1("SubscriptionNotFound", false)
 	}
+	app.SyncTopics.Lock()
+	TOPIC_DATA[topicArn].confirmed = true
+	app.SyncTopics.Unlock()
 	respStruct := models.ConfirmSubscriptionResponse{
 		Xmlns:    models.BASE_XMLNS,
 		Result:   models.ConfirmSubscriptionResult{SubscriptionArn: pendingConfirm.subArn},
@@ -1,13 +1,16 @@
 package gosns
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -27,11 +30,46 @@ func CreateTopicV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	} else {
 		topicArn = fmt.Sprintf("arn:aws:sns:%s:%s:%s", app.CurrentEnvironment.Region, app.CurrentEnvironment.AccountID, topicName)
 
+		attributes := requestBody.Attributes
+		topic := &app.Topic{
+			Name:                      topicName,
+			Arn:                       topicArn,
+			DisplayName:               attributes.DisplayName,
+			Policy:                    jsonAttribute(attributes.Policy),
+			DeliveryPolicy:            jsonAttribute(attributes.DeliveryPolicy),
+			FifoTopic:                 attributes.FifoTopic,
+			ContentBasedDeduplication: attributes.ContentBasedDeduplication,
+			TracingConfig:             attributes.TracingConfig,
+			KmsMasterKeyId:            attributes.KmsMasterKeyId,
+			ArchivePolicy:             jsonAttribute(attributes.ArchivePolicy),
+		}
+		if attributes.SignatureVersion != 0 {
+			topic.SignatureVersion = strconv.Itoa(int(attributes.SignatureVersion))
+		}
+		// Like AWS, a topic is FIFO exactly when its name ends with .fifo, which has to be asked for.
+		if topic.FifoTopic != app.HasFIFOTopicName(topicName) {
+			log.Errorf("FifoTopic %t doesn't match the name of topic [%s]", topic.FifoTopic, topicName)
+			if topic.FifoTopic {
+				return utils.CreateErrorResponseV1("InvalidFifoTopicName", false)
+			}
+			return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+		}
+		if topic.ContentBasedDeduplication && !topic.FifoTopic {
+			log.Errorf("ContentBasedDeduplication is only for FIFO topics, not [%s]", topicName)
+			return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+		}
+		if topic.Policy != "" {
+			if _, err := app.ParsePolicy(topic.Policy); err != nil {
+				log.Errorf("Invalid Policy for topic [%s]: %s", topicName, err)
+				return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+			}
+		}
+
 		log.Info("Creating Topic:", topicName)
-		topic := &app.Topic{Name: topicName, Arn: topicArn}
 		topic.Subscriptions = make([]*app.Subscription, 0)
 		app.SyncTopics.Lock()
 		app.SyncTopics.Topics[topicName] = topic
+		persistence.TopicUpdated(topic)
 		app.SyncTopics.Unlock()
 	}
 
@@ -46,3 +84,12 @@ func CreateTopicV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 
 	return http.StatusOK, respStruct
 }
+
+// jsonAttribute is the text of a JSON attribute, empty when it wasn't given.
+func jsonAttribute(value map[string]interface{}) string {
+	if value == nil {
+		return ""
+	}
+	text, _ := json.Marshal(value)
+	return string(text)
+}
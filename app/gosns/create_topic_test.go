@@ -104,3 +104,88 @@ func TestCreateTopicV1_request_transformer_error(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, code)
 }
+
+func TestCreateTopicV1_stores_attributes(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.CreateTopicRequest)
+		v.Name = "new-topic-1"
+		v.Attributes.DisplayName = "New Topic"
+		v.Attributes.DeliveryPolicy = map[string]interface{}{"http": map[string]interface{}{}}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ := CreateTopicV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	topic := app.SyncTopics.Topics["new-topic-1"]
+	assert.Equal(t, "New Topic", topic.DisplayName)
+	assert.Equal(t, `{"http":{}}`, topic.DeliveryPolicy)
+	assert.Equal(t, "1", topic.SignatureVersion)
+	assert.Equal(t, "Active", topic.TracingConfig)
+	assert.Equal(t, "", topic.Policy)
+}
+
+func TestCreateTopicV1_invalid_policy(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.CreateTopicRequest)
+		v.Name = "new-topic-1"
+		v.Attributes.Policy = map[string]interface{}{"i-am": "not-a-policy"}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ := CreateTopicV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, 0, len(app.SyncTopics.Topics))
+}
+
+func TestCreateTopicV1_fifo_topic(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	var tests = []struct {
+		name                      string
+		fifoTopic                 bool
+		contentBasedDeduplication bool
+		status                    int
+	}{
+		{"new-topic.fifo", true, true, http.StatusOK},
+		{"new-topic-1", true, false, http.StatusBadRequest},
+		{"new-topic-2.fifo", false, false, http.StatusBadRequest},
+		{"new-topic-3", false, true, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+			v := resultingStruct.(*models.CreateTopicRequest)
+			v.Name = tt.name
+			v.Attributes.FifoTopic = tt.fifoTopic
+			v.Attributes.ContentBasedDeduplication = tt.contentBasedDeduplication
+			return true
+		}
+
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		status, _ := CreateTopicV1(r)
+
+		assert.Equal(t, tt.status, status, tt.name)
+	}
+	assert.Len(t, app.SyncTopics.Topics, 1)
+	assert.True(t, app.SyncTopics.Topics["new-topic.fifo"].FifoTopic)
+	assert.True(t, app.SyncTopics.Topics["new-topic.fifo"].ContentBasedDeduplication)
+}
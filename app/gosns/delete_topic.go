@@ -8,6 +8,7 @@ import (
 	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	log "github.com/sirupsen/logrus"
@@ -35,6 +36,7 @@ func DeleteTopicV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 
 	app.SyncTopics.Lock()
 	delete(app.SyncTopics.Topics, topicName)
+	persistence.TopicDeleted(topicName)
 	app.SyncTopics.Unlock()
 	uuid, _ := common.NewUUID()
 	respStruct := models.DeleteTopicResponse{
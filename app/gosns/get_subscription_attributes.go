@@ -37,7 +37,7 @@ func GetSubscriptionAttributesV1(req *http.Request) (int, interfaces.AbstractRes
 	entries = append(entries, entry)
 	entry = models.SubscriptionAttributeEntry{Key: "Endpoint", Value: sub.EndPoint}
 	entries = append(entries, entry)
-	entry = models.SubscriptionAttributeEntry{Key: "PendingConfirmation", Value: "false"}
+	entry = models.SubscriptionAttributeEntry{Key: "PendingConfirmation", Value: strconv.FormatBool(subscriptionPending(sub))}
 	entries = append(entries, entry)
 	entry = models.SubscriptionAttributeEntry{Key: "ConfirmationWasAuthenticated", Value: "true"}
 	entries = append(entries, entry)
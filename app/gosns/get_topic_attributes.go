@@ -0,0 +1,96 @@
+package gosns
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// The delivery policy AWS gives topics that don't set their own.
+const defaultDeliveryPolicy = `{"http":{"defaultHealthyRetryPolicy":{"minDelayTarget":20,"maxDelayTarget":20,"numRetries":3,"numMaxDelayRetries":0,"numNoDelayRetries":0,"numMinDelayRetries":0,"backoffFunction":"linear"},"disableSubscriptionOverrides":false,"defaultRequestPolicy":{"headerContentType":"text/plain; charset=UTF-8"}}}`
+
+func GetTopicAttributesV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewGetTopicAttributesRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - GetTopicAttributesV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+	}
+
+	uriSegments := strings.Split(requestBody.TopicArn, ":")
+	topicName := uriSegments[len(uriSegments)-1]
+
+	app.SyncTopics.RLock()
+	defer app.SyncTopics.RUnlock()
+	topic, ok := app.SyncTopics.Topics[topicName]
+	if !ok {
+		return utils.CreateErrorResponseV1("TopicNotFound", false)
+	}
+
+	confirmed, pending := 0, 0
+	for _, sub := range topic.Subscriptions {
+		if subscriptionPending(sub) {
+			pending++
+		} else {
+			confirmed++
+		}
+	}
+	policy := topic.Policy
+	if policy == "" {
+		policy = defaultTopicPolicy(topic)
+	}
+	effectiveDeliveryPolicy := topic.DeliveryPolicy
+	if effectiveDeliveryPolicy == "" {
+		effectiveDeliveryPolicy = defaultDeliveryPolicy
+	}
+
+	entries := []models.TopicAttributeEntry{
+		{Key: "TopicArn", Value: topic.Arn},
+		{Key: "Owner", Value: app.CurrentEnvironment.AccountID},
+		{Key: "Policy", Value: policy},
+		{Key: "DisplayName", Value: topic.DisplayName},
+		{Key: "SubscriptionsConfirmed", Value: strconv.Itoa(confirmed)},
+		{Key: "SubscriptionsPending", Value: strconv.Itoa(pending)},
+		{Key: "SubscriptionsDeleted", Value: strconv.Itoa(topic.SubscriptionsDeleted)},
+		{Key: "EffectiveDeliveryPolicy", Value: effectiveDeliveryPolicy},
+	}
+	optional := []models.TopicAttributeEntry{
+		{Key: "DeliveryPolicy", Value: topic.DeliveryPolicy},
+		{Key: "SignatureVersion", Value: topic.SignatureVersion},
+		{Key: "TracingConfig", Value: topic.TracingConfig},
+		{Key: "KmsMasterKeyId", Value: topic.KmsMasterKeyId},
+	}
+	if topic.FifoTopic {
+		optional = append(optional,
+			models.TopicAttributeEntry{Key: "FifoTopic", Value: "true"},
+			models.TopicAttributeEntry{Key: "ContentBasedDeduplication", Value: strconv.FormatBool(topic.ContentBasedDeduplication)},
+			models.TopicAttributeEntry{Key: "ArchivePolicy", Value: topic.ArchivePolicy},
+		)
+	}
+	for _, entry := range optional {
+		if entry.Value != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	respStruct := models.GetTopicAttributesResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Result:   models.GetTopicAttributesResult{Attributes: models.GetTopicAttributes{Entries: entries}},
+		Metadata: app.ResponseMetadata{RequestId: uuid.NewString()},
+	}
+	return http.StatusOK, respStruct
+}
+
+// defaultTopicPolicy is the policy AWS gives a topic created without one: its owner may do anything with it.
+func defaultTopicPolicy(topic *app.Topic) string {
+	return fmt.Sprintf(`{"Version":"2008-10-17","Id":"__default_policy_ID","Statement":[{"Sid":"__default_statement_ID","Effect":"Allow","Principal":{"AWS":"*"},"Action":["SNS:GetTopicAttributes","SNS:SetTopicAttributes","SNS:AddPermission","SNS:RemovePermission","SNS:DeleteTopic","SNS:Subscribe","SNS:ListSubscriptionsByTopic","SNS:Publish"],"Resource":"%s","Condition":{"StringEquals":{"AWS:SourceOwner":"%s"}}}]}`,
+		topic.Arn, app.CurrentEnvironment.AccountID)
+}
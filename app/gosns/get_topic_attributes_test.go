@@ -0,0 +1,143 @@
+package gosns
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const attributesTopicArn = "arn:aws:sns:us-east-1:100010001000:attributes-topic"
+
+func setTopicAttributesRequestTopic(topicArn string) {
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.GetTopicAttributesRequest)
+		*v = models.GetTopicAttributesRequest{TopicArn: topicArn}
+		return true
+	}
+}
+
+func topicAttributes(response interfaces.AbstractResponseBody) map[string]string {
+	attributes := map[string]string{}
+	for _, entry := range response.(models.GetTopicAttributesResponse).Result.Attributes.Entries {
+		attributes[entry.Key] = entry.Value
+	}
+	return attributes
+}
+
+func TestGetTopicAttributesV1_success(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		TOPIC_DATA = make(map[string]*pendingConfirm)
+	}()
+
+	app.SyncTopics.Topics["attributes-topic"] = &app.Topic{
+		Name: "attributes-topic",
+		Arn:  attributesTopicArn,
+		Subscriptions: []*app.Subscription{
+			{TopicArn: attributesTopicArn, Protocol: "sqs", SubscriptionArn: attributesTopicArn + ":sqs"},
+			{TopicArn: attributesTopicArn, Protocol: "http", SubscriptionArn: attributesTopicArn + ":http"},
+		},
+		SubscriptionsDeleted: 2,
+		DisplayName:          "Attributes",
+		SignatureVersion:     "2",
+	}
+	TOPIC_DATA[attributesTopicArn] = &pendingConfirm{subArn: attributesTopicArn + ":http", token: "token"}
+	setTopicAttributesRequestTopic(attributesTopicArn)
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := GetTopicAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	attributes := topicAttributes(response)
+	assert.Equal(t, attributesTopicArn, attributes["TopicArn"])
+	assert.Equal(t, fixtures.LOCAL_ENVIRONMENT.AccountID, attributes["Owner"])
+	assert.Equal(t, "Attributes", attributes["DisplayName"])
+	assert.Equal(t, "1", attributes["SubscriptionsConfirmed"])
+	assert.Equal(t, "1", attributes["SubscriptionsPending"])
+	assert.Equal(t, "2", attributes["SubscriptionsDeleted"])
+	assert.Equal(t, defaultDeliveryPolicy, attributes["EffectiveDeliveryPolicy"])
+	assert.Equal(t, "2", attributes["SignatureVersion"])
+	assert.NotContains(t, attributes, "DeliveryPolicy")
+	assert.NotContains(t, attributes, "FifoTopic")
+
+	policy, err := app.ParsePolicy(attributes["Policy"])
+	assert.Nil(t, err)
+	assert.Equal(t, app.PolicyValues{attributesTopicArn}, policy.Statement[0].Resource)
+
+	TOPIC_DATA[attributesTopicArn].confirmed = true
+	_, response = GetTopicAttributesV1(r)
+
+	attributes = topicAttributes(response)
+	assert.Equal(t, "2", attributes["SubscriptionsConfirmed"])
+	assert.Equal(t, "0", attributes["SubscriptionsPending"])
+}
+
+func TestGetTopicAttributesV1_set_policies(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	app.SyncTopics.Topics["attributes-topic.fifo"] = &app.Topic{
+		Name:                      "attributes-topic.fifo",
+		Arn:                       attributesTopicArn + ".fifo",
+		Policy:                    `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"sns:Publish"}]}`,
+		DeliveryPolicy:            `{"http":{}}`,
+		FifoTopic:                 true,
+		ContentBasedDeduplication: true,
+	}
+	setTopicAttributesRequestTopic(attributesTopicArn + ".fifo")
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := GetTopicAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	attributes := topicAttributes(response)
+	assert.Equal(t, `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"sns:Publish"}]}`, attributes["Policy"])
+	assert.Equal(t, `{"http":{}}`, attributes["DeliveryPolicy"])
+	assert.Equal(t, `{"http":{}}`, attributes["EffectiveDeliveryPolicy"])
+	assert.Equal(t, "true", attributes["FifoTopic"])
+	assert.Equal(t, "true", attributes["ContentBasedDeduplication"])
+	assert.NotContains(t, attributes, "ArchivePolicy")
+}
+
+func TestGetTopicAttributesV1_topic_not_found(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	setTopicAttributesRequestTopic(attributesTopicArn)
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := GetTopicAttributesV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, models.SnsErrors["TopicNotFound"].Response(), response.(models.ErrorResponse).Result)
+}
+
+func TestGetTopicAttributesV1_request_transformer_error(t *testing.T) {
+	defer func() {
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		return false
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ := GetTopicAttributesV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 
 	"bytes"
 	"crypto"
@@ -27,14 +28,18 @@ import (
 )
 
 type pendingConfirm struct {
-	subArn string
-	token  string
+	subArn    string
+	token     string
+	confirmed bool
 }
 
 var PemKEY []byte
 var PrivateKEY *rsa.PrivateKey
 var TOPIC_DATA map[string]*pendingConfirm
 
+// AWS lists topics and subscriptions 100 at a time.
+const listPageSize = 100
+
 func init() {
 	app.SyncTopics.Topics = make(map[string]*app.Topic)
 	TOPIC_DATA = make(map[string]*pendingConfirm)
@@ -211,6 +216,12 @@ func extractMessageFromJSON(msg string, protocol string) (string, error) {
 	return defaultMsg, nil
 }
 
+// subscriptionPending reports whether `sub` still waits for its endpoint to confirm it.
+func subscriptionPending(sub *app.Subscription) bool {
+	pending, ok := TOPIC_DATA[sub.TopicArn]
+	return ok && pending.subArn == sub.SubscriptionArn && !pending.confirmed
+}
+
 func getSubscription(subsArn string) *app.Subscription {
 	for _, topic := range app.SyncTopics.Topics {
 		for _, sub := range topic.Subscriptions {
@@ -222,6 +233,17 @@ func getSubscription(subsArn string) *app.Subscription {
 	return nil
 }
 
+// subscriptionUpdated journals the topic that `sub` belongs to.
+// NOTE: the caller must hold `app.SyncTopics`.
+func subscriptionUpdated(sub *app.Subscription) {
+	for _, topic := range app.SyncTopics.Topics {
+		if topic.Arn == sub.TopicArn {
+			persistence.TopicUpdated(topic)
+			return
+		}
+	}
+}
+
 func createErrorResponse(w http.ResponseWriter, req *http.Request, err string) {
 	er := models.SnsErrors[err]
 	respStruct := models.ErrorResponse{
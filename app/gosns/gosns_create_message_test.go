@@ -29,7 +29,7 @@ func TestCreateMessageBody_NonJson(t *testing.T) {
 		Raw:             false,
 	}
 
-	snsMessage, err := createMessageBody(subs, message, subject, messageStructureEmpty, make(map[string]app.MessageAttributeValue))
+	snsMessage, err := createMessageBody(subs, message, subject, messageStructureEmpty, make(map[string]app.MessageAttributeValue), "message-id", "")
 	if err != nil {
 		t.Fatalf(`error creating SNS message: %s`, err)
 	}
@@ -71,7 +71,7 @@ func TestCreateMessageBody_OnlyDefaultValueInJson(t *testing.T) {
 	message := `{"default": "default message text", "http": "HTTP message text"}`
 	subject := "subject"
 
-	snsMessage, err := createMessageBody(subs, message, subject, messageStructureJSON, nil)
+	snsMessage, err := createMessageBody(subs, message, subject, messageStructureJSON, nil, "message-id", "")
 	if err != nil {
 		t.Fatalf(`error creating SNS message: %s`, err)
 	}
@@ -114,7 +114,7 @@ func TestCreateMessageBody_OnlySqsValueInJson(t *testing.T) {
 	message := `{"sqs": "message text"}`
 	subject := "subject"
 
-	snsMessage, err := createMessageBody(subs, message, subject, messageStructureJSON, nil)
+	snsMessage, err := createMessageBody(subs, message, subject, messageStructureJSON, nil, "message-id", "")
 	if err == nil {
 		t.Fatalf(`error expected but instead SNS message was returned: %s`, snsMessage)
 	}
@@ -132,7 +132,7 @@ func TestCreateMessageBody_BothDefaultAndSqsValuesInJson(t *testing.T) {
 	message := `{"default": "default message text", "sqs": "sqs message text"}`
 	subject := "subject"
 
-	snsMessage, err := createMessageBody(subs, message, subject, messageStructureJSON, nil)
+	snsMessage, err := createMessageBody(subs, message, subject, messageStructureJSON, nil, "message-id", "")
 	if err != nil {
 		t.Fatalf(`error creating SNS message: %s`, err)
 	}
@@ -175,7 +175,7 @@ func TestCreateMessageBody_NonJsonContainingJson(t *testing.T) {
 	message := `{"default": "default message text", "sqs": "sqs message text"}`
 	subject := "subject"
 
-	snsMessage, err := createMessageBody(subs, message, subject, "", nil)
+	snsMessage, err := createMessageBody(subs, message, subject, "", nil, "message-id", "")
 	if err != nil {
 		t.Fatalf(`error creating SNS message: %s`, err)
 	}
@@ -217,11 +217,11 @@ func TestCreateMessageBody_WithMessageAttributes(t *testing.T) {
 		SubscriptionArn: "subs-arn",
 		Raw:             false,
 	}
-	stringMessageAttributeValue := app.MessageAttributeValue{ValueKey: "StringValue", Value: "test", DataType: "String"}
+	stringMessageAttributeValue := app.MessageAttributeValue{StringValue: "test", DataType: "String"}
 	attributes := map[string]app.MessageAttributeValue{
 		stringMessageAttributeValue.DataType: stringMessageAttributeValue,
 	}
-	snsMessage, err := createMessageBody(subs, message, subject, messageStructureEmpty, attributes)
+	snsMessage, err := createMessageBody(subs, message, subject, messageStructureEmpty, attributes, "message-id", "")
 	if err != nil {
 		t.Fatalf(`error creating SNS message: %s`, err)
 	}
@@ -259,7 +259,7 @@ func TestCreateMessageBody_WithMessageAttributes(t *testing.T) {
 	}
 
 	attributeValue, _ := attributeMap["Value"]
-	if attributeValue != stringMessageAttributeValue.Value {
-		t.Fatalf(`expected Value "%s" but received %s`, stringMessageAttributeValue.Value, attributeValue)
+	if attributeValue != stringMessageAttributeValue.StringValue {
+		t.Fatalf(`expected Value "%s" but received %s`, stringMessageAttributeValue.StringValue, attributeValue)
 	}
 }
@@ -2,10 +2,12 @@ package gosns
 
 import (
 	"net/http"
+	"sort"
 
 	"github.com/google/uuid"
 
 	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/models"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
@@ -26,15 +28,31 @@ func ListSubscriptionsV1(req *http.Request) (int, interfaces.AbstractResponseBod
 	respStruct := models.ListSubscriptionsResponse{}
 	respStruct.Xmlns = models.BASE_XMLNS
 	respStruct.Metadata.RequestId = requestId
-	respStruct.Result.Subscriptions.Member = make([]models.TopicMemberResult, 0)
 
+	subscriptions := map[string]models.TopicMemberResult{}
+	arns := make([]string, 0)
+	app.SyncTopics.RLock()
 	for _, topic := range app.SyncTopics.Topics {
 		for _, sub := range topic.Subscriptions {
 			tar := models.TopicMemberResult{TopicArn: topic.Arn, Protocol: sub.Protocol,
 				SubscriptionArn: sub.SubscriptionArn, Endpoint: sub.EndPoint, Owner: app.CurrentEnvironment.AccountID}
-			respStruct.Result.Subscriptions.Member = append(respStruct.Result.Subscriptions.Member, tar)
+			subscriptions[sub.SubscriptionArn] = tar
+			arns = append(arns, sub.SubscriptionArn)
 		}
 	}
+	app.SyncTopics.RUnlock()
+	sort.Strings(arns)
+
+	start, end, nextToken, ok := common.Paginate(arns, requestBody.NextToken, listPageSize)
+	if !ok {
+		return utils.CreateErrorResponseV1("InvalidNextToken", false)
+	}
+
+	respStruct.Result.Subscriptions.Member = make([]models.TopicMemberResult, 0, end-start)
+	for _, arn := range arns[start:end] {
+		respStruct.Result.Subscriptions.Member = append(respStruct.Result.Subscriptions.Member, subscriptions[arn])
+	}
+	respStruct.Result.NextToken = nextToken
 
 	return http.StatusOK, respStruct
 }
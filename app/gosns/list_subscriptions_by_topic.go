@@ -2,9 +2,11 @@ package gosns
 
 import (
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
 	"github.com/Admiral-Piett/goaws/app/utils"
@@ -23,25 +25,39 @@ func ListSubscriptionsByTopicV1(req *http.Request) (int, interfaces.AbstractResp
 	topicArn := requestBody.TopicArn
 	uriSegments := strings.Split(topicArn, ":")
 	topicName := uriSegments[len(uriSegments)-1]
-	var topic app.Topic
 
-	if value, ok := app.SyncTopics.Topics[topicName]; ok {
-		topic = *value
-	} else {
+	subscriptions := map[string]models.TopicMemberResult{}
+	arns := make([]string, 0)
+	app.SyncTopics.RLock()
+	topic, ok := app.SyncTopics.Topics[topicName]
+	if ok {
+		for _, sub := range topic.Subscriptions {
+			tar := models.TopicMemberResult{TopicArn: topic.Arn, Protocol: sub.Protocol,
+				SubscriptionArn: sub.SubscriptionArn, Endpoint: sub.EndPoint, Owner: app.CurrentEnvironment.AccountID}
+			subscriptions[sub.SubscriptionArn] = tar
+			arns = append(arns, sub.SubscriptionArn)
+		}
+	}
+	app.SyncTopics.RUnlock()
+	if !ok {
 		return utils.CreateErrorResponseV1("TopicNotFound", false)
 	}
+	sort.Strings(arns)
 
-	resultMember := make([]models.TopicMemberResult, 0)
+	start, end, nextToken, ok := common.Paginate(arns, requestBody.NextToken, listPageSize)
+	if !ok {
+		return utils.CreateErrorResponseV1("InvalidNextToken", false)
+	}
 
-	for _, sub := range topic.Subscriptions {
-		tar := models.TopicMemberResult{TopicArn: topic.Arn, Protocol: sub.Protocol,
-			SubscriptionArn: sub.SubscriptionArn, Endpoint: sub.EndPoint, Owner: app.CurrentEnvironment.AccountID}
-		resultMember = append(resultMember, tar)
+	resultMember := make([]models.TopicMemberResult, 0, end-start)
+	for _, arn := range arns[start:end] {
+		resultMember = append(resultMember, subscriptions[arn])
 	}
 
 	respStruct := models.ListSubscriptionsByTopicResponse{
 		Xmlns: models.BASE_XMLNS,
 		Result: models.ListSubscriptionsByTopicResult{
+			NextToken: nextToken,
 			Subscriptions: models.TopicSubscriptions{
 				Member: resultMember,
 			},
@@ -1,6 +1,7 @@
 package gosns
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -97,3 +98,46 @@ func TestListSubscriptionsByTopicV1_Success_Multiple_Subscription(t *testing.T)
 
 	assert.ElementsMatch(t, expectedMember, response.Result.Subscriptions.Member)
 }
+
+func TestListSubscriptionsByTopicV1_paginates_by_subscription_arn(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	topic := &app.Topic{Name: "paged-topic", Arn: "arn:aws:sns:region:accountID:paged-topic"}
+	for i := 0; i < 120; i++ {
+		topic.Subscriptions = append(topic.Subscriptions, &app.Subscription{
+			TopicArn:        topic.Arn,
+			Protocol:        "sqs",
+			SubscriptionArn: fmt.Sprintf("%s:%03d", topic.Arn, 119-i),
+		})
+	}
+	app.SyncTopics.Topics[topic.Name] = topic
+
+	request := models.ListSubscriptionsByTopicRequest{TopicArn: topic.Arn}
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListSubscriptionsByTopicRequest)
+		*v = request
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res := ListSubscriptionsByTopicV1(r)
+	response, _ := res.(models.ListSubscriptionsByTopicResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, response.Result.Subscriptions.Member, 100)
+	assert.Equal(t, "arn:aws:sns:region:accountID:paged-topic:000", response.Result.Subscriptions.Member[0].SubscriptionArn)
+	assert.NotEmpty(t, response.Result.NextToken)
+
+	request.NextToken = response.Result.NextToken
+	_, r = test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res = ListSubscriptionsByTopicV1(r)
+	response, _ = res.(models.ListSubscriptionsByTopicResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, response.Result.Subscriptions.Member, 20)
+	assert.Equal(t, "arn:aws:sns:region:accountID:paged-topic:100", response.Result.Subscriptions.Member[0].SubscriptionArn)
+	assert.Empty(t, response.Result.NextToken)
+}
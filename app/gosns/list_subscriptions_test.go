@@ -1,9 +1,11 @@
 package gosns
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
 
+	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/conf"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
@@ -83,3 +85,69 @@ func TestListSubscriptionsV1_request_transformer_error(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, code)
 }
+
+func TestListSubscriptionsV1_paginates_by_subscription_arn(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("topic-%d", i)
+		topic := &app.Topic{Name: name, Arn: fmt.Sprintf("arn:aws:sns:region:accountID:%s", name)}
+		for j := 0; j < 40; j++ {
+			topic.Subscriptions = append(topic.Subscriptions, &app.Subscription{
+				TopicArn:        topic.Arn,
+				Protocol:        "sqs",
+				SubscriptionArn: fmt.Sprintf("%s:%02d", topic.Arn, j),
+			})
+		}
+		app.SyncTopics.Topics[name] = topic
+	}
+
+	request := models.ListSubscriptionsRequest{}
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListSubscriptionsRequest)
+		*v = request
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res := ListSubscriptionsV1(r)
+	response, _ := res.(models.ListSubscriptionsResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, response.Result.Subscriptions.Member, 100)
+	assert.Equal(t, "arn:aws:sns:region:accountID:topic-0:00", response.Result.Subscriptions.Member[0].SubscriptionArn)
+	assert.Equal(t, "arn:aws:sns:region:accountID:topic-2:19", response.Result.Subscriptions.Member[99].SubscriptionArn)
+	assert.NotEmpty(t, response.Result.NextToken)
+
+	request.NextToken = response.Result.NextToken
+	_, r = test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res = ListSubscriptionsV1(r)
+	response, _ = res.(models.ListSubscriptionsResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, response.Result.Subscriptions.Member, 20)
+	assert.Equal(t, "arn:aws:sns:region:accountID:topic-2:20", response.Result.Subscriptions.Member[0].SubscriptionArn)
+	assert.Empty(t, response.Result.NextToken)
+}
+
+func TestListSubscriptionsV1_invalid_next_token(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListSubscriptionsRequest)
+		*v = models.ListSubscriptionsRequest{NextToken: "not a token!"}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res := ListSubscriptionsV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "InvalidParameter", res.GetResult().(models.ErrorResult).Code)
+}
@@ -2,10 +2,12 @@ package gosns
 
 import (
 	"net/http"
+	"sort"
 
 	"github.com/google/uuid"
 
 	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/models"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
@@ -22,17 +24,28 @@ func ListTopicsV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	}
 
 	log.Debug("Listing Topics")
-	arnList := make([]models.TopicArnResult, 0)
-
+	arns := make([]string, 0)
+	app.SyncTopics.RLock()
 	for _, topic := range app.SyncTopics.Topics {
-		ta := models.TopicArnResult{TopicArn: topic.Arn}
-		arnList = append(arnList, ta)
+		arns = append(arns, topic.Arn)
+	}
+	app.SyncTopics.RUnlock()
+	sort.Strings(arns)
+
+	start, end, nextToken, ok := common.Paginate(arns, requestBody.NextToken, listPageSize)
+	if !ok {
+		return utils.CreateErrorResponseV1("InvalidNextToken", false)
+	}
+
+	arnList := make([]models.TopicArnResult, 0, end-start)
+	for _, arn := range arns[start:end] {
+		arnList = append(arnList, models.TopicArnResult{TopicArn: arn})
 	}
 
 	requestId := uuid.NewString()
 	respStruct := models.ListTopicsResponse{
 		Xmlns:    models.BASE_XMLNS,
-		Result:   models.ListTopicsResult{Topics: models.TopicNamestype{Member: arnList}},
+		Result:   models.ListTopicsResult{Topics: models.TopicNamestype{Member: arnList}, NextToken: nextToken},
 		Metadata: app.ResponseMetadata{RequestId: requestId},
 	}
 
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/conf"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
@@ -91,3 +92,61 @@ func TestListTopicsV1_request_transformer_error(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, code)
 }
+
+func TestListTopicsV1_paginates_by_arn(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	for i := 0; i < 150; i++ {
+		name := fmt.Sprintf("topic-%03d", i)
+		app.SyncTopics.Topics[name] = &app.Topic{Name: name, Arn: fmt.Sprintf("arn:aws:sns:region:accountID:%s", name)}
+	}
+
+	request := models.ListTopicsRequest{}
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListTopicsRequest)
+		*v = request
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res := ListTopicsV1(r)
+	response, _ := res.(models.ListTopicsResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, response.Result.Topics.Member, 100)
+	assert.Equal(t, "arn:aws:sns:region:accountID:topic-000", response.Result.Topics.Member[0].TopicArn)
+	assert.Equal(t, "arn:aws:sns:region:accountID:topic-099", response.Result.Topics.Member[99].TopicArn)
+	assert.NotEmpty(t, response.Result.NextToken)
+
+	request.NextToken = response.Result.NextToken
+	_, r = test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res = ListTopicsV1(r)
+	response, _ = res.(models.ListTopicsResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, response.Result.Topics.Member, 50)
+	assert.Equal(t, "arn:aws:sns:region:accountID:topic-100", response.Result.Topics.Member[0].TopicArn)
+	assert.Empty(t, response.Result.NextToken)
+}
+
+func TestListTopicsV1_invalid_next_token(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListTopicsRequest)
+		*v = models.ListTopicsRequest{NextToken: "not a token!"}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, res := ListTopicsV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "InvalidParameter", res.GetResult().(models.ErrorResult).Code)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -18,8 +19,6 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// TODO - Admiral-Piett - Pick a MessageAttribute style and get rid of `utils.ConvertToOldMessageAttributeValueStructure`
-
 // aws --endpoint-url http://localhost:47194 sns publish --topic-arn arn:aws:sns:yopa-local:000000000000:test1 --message "This is a test"
 func PublishV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	requestBody := models.NewPublishRequest()
@@ -37,35 +36,33 @@ func PublishV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	arnSegments := strings.Split(requestBody.TopicArn, ":")
 	topicName := arnSegments[len(arnSegments)-1]
 
-	_, ok = app.SyncTopics.Topics[topicName]
+	app.SyncTopics.RLock()
+	topic, ok := app.SyncTopics.Topics[topicName]
+	var subscriptions []*app.Subscription
+	contentBasedDeduplication := false
 	if ok {
-		log.WithFields(log.Fields{
-			"topic":    topicName,
-			"topicArn": requestBody.TopicArn,
-			"subject":  requestBody.Subject,
-		}).Debug("Publish to Topic")
-		for _, subscription := range app.SyncTopics.Topics[topicName].Subscriptions {
-			switch app.Protocol(subscription.Protocol) {
-			case app.ProtocolSQS:
-				err := publishSQS(subscription, topicName, requestBody)
-				if err != nil {
-					utils.CreateErrorResponseV1(err.Error(), false)
-				}
-			case app.ProtocolHTTP:
-				fallthrough
-			case app.ProtocolHTTPS:
-				publishHTTP(subscription, requestBody)
-			}
-		}
-	} else {
+		subscriptions = append(subscriptions, topic.Subscriptions...)
+		contentBasedDeduplication = topic.ContentBasedDeduplication
+	}
+	app.SyncTopics.RUnlock()
+	if !ok {
 		return utils.CreateErrorResponseV1("TopicNotFound", false)
 	}
+	if topic.FifoTopic {
+		if errKey := validateFifoMessage(requestBody.MessageGroupId, requestBody.MessageDeduplicationId, contentBasedDeduplication); errKey != "" {
+			return utils.CreateErrorResponseV1(errKey, false)
+		}
+		topic.Publishing.Lock()
+		defer topic.Publishing.Unlock()
+	}
+	messageId, sequenceNumber := publishToTopic(topic, subscriptions, requestBody, uuid.NewString())
 
 	//Create the response
 	respStruct := models.PublishResponse{
 		Xmlns: models.BASE_XMLNS,
 		Result: models.PublishResult{
-			MessageId: uuid.NewString(),
+			MessageId:      messageId,
+			SequenceNumber: sequenceNumber,
 		},
 		Metadata: app.ResponseMetadata{
 			RequestId: uuid.NewString(),
@@ -74,23 +71,96 @@ func PublishV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	return http.StatusOK, respStruct
 }
 
-func publishSQS(subscription *app.Subscription, topicName string, requestBody *models.PublishRequest) error {
-	messageAttributes := utils.ConvertToOldMessageAttributeValueStructure(requestBody.MessageAttributes)
-	if subscription.FilterPolicy != nil && !subscription.FilterPolicy.IsSatisfiedBy(messageAttributes) {
-		return nil
+// validateFifoMessage returns the key of the error of a message published to a FIFO topic, if there is one:
+// it needs a group, and a deduplication ID unless the topic has content based deduplication.
+func validateFifoMessage(messageGroupId string, messageDeduplicationId string, contentBasedDeduplication bool) string {
+	if messageGroupId == "" {
+		return "MissingMessageGroupId"
+	}
+	if messageDeduplicationId == "" && !contentBasedDeduplication {
+		return "MissingDeduplicationId"
+	}
+	return ""
+}
+
+// publishToTopic delivers the message `messageId` to the subscriptions of `topic`, returning the ID and, when
+// the topic is FIFO, the sequence number it was published with.  A FIFO topic drops a message whose
+// deduplication ID it already published within the deduplication period, and like AWS answers with the ID and
+// sequence number of the original.
+// NOTE: for a FIFO topic the caller must hold `topic.Publishing`.
+func publishToTopic(topic *app.Topic, subscriptions []*app.Subscription, requestBody *models.PublishRequest, messageId string) (string, string) {
+	sequenceNumber := ""
+	if topic.FifoTopic {
+		app.SyncTopics.Lock()
+		requestBody.MessageDeduplicationId = topic.DeduplicationId(requestBody.MessageDeduplicationId, requestBody.Message)
+		published, ok := topic.Sequence(requestBody.MessageDeduplicationId, messageId, time.Now())
+		persistence.TopicUpdated(topic)
+		app.SyncTopics.Unlock()
+		if !ok {
+			log.Debugf("Message with deduplicationId [%s] in topic [%s] is duplicate", requestBody.MessageDeduplicationId, topic.Name)
+			return published.MessageId, published.SequenceNumber
+		}
+		sequenceNumber = published.SequenceNumber
 	}
+	publishToSubscriptions(topic.Name, subscriptions, requestBody, messageId, sequenceNumber)
+	return messageId, sequenceNumber
+}
+
+// publishToSubscriptions delivers a message published to the topic to each of its subscriptions, they all
+// get it with the same message ID.
+func publishToSubscriptions(topicName string, subscriptions []*app.Subscription, requestBody *models.PublishRequest, messageId string, sequenceNumber string) {
+	log.WithFields(log.Fields{
+		"topic":    topicName,
+		"topicArn": requestBody.TopicArn,
+		"subject":  requestBody.Subject,
+	}).Debug("Publish to Topic")
+	for _, subscription := range subscriptions {
+		switch app.Protocol(subscription.Protocol) {
+		case app.ProtocolSQS:
+			err := publishSQS(subscription, topicName, requestBody, messageId, sequenceNumber)
+			if err != nil {
+				log.Errorf("Error publishing to subscription %s: %s", subscription.SubscriptionArn, err)
+			}
+		case app.ProtocolHTTP:
+			fallthrough
+		case app.ProtocolHTTPS:
+			publishHTTP(subscription, requestBody, messageId)
+		}
+	}
+}
 
-	endPoint := subscription.EndPoint
+// queueNameFromEndpoint is the name of the queue of an sqs subscription, whose endpoint is either the
+// queue's URL or ARN.
+func queueNameFromEndpoint(endPoint string) string {
 	uriSegments := strings.Split(endPoint, "/")
 	queueName := uriSegments[len(uriSegments)-1]
 	arnSegments := strings.Split(queueName, ":")
-	queueName = arnSegments[len(arnSegments)-1]
+	return arnSegments[len(arnSegments)-1]
+}
 
-	if _, ok := app.SyncQueues.Queues[queueName]; ok {
+// publishSQS sends a message to the queue of the subscription.  A FIFO queue keeps the group and
+// deduplication ID of the message, and deduplicates it again on its own.
+func publishSQS(subscription *app.Subscription, topicName string, requestBody *models.PublishRequest, messageId string, sequenceNumber string) error {
+	messageAttributes := requestBody.MessageAttributes
+	if subscription.FilterPolicy != nil && !subscription.FilterPolicy.IsSatisfiedBy(messageAttributes) {
+		return nil
+	}
+
+	queueName := queueNameFromEndpoint(subscription.EndPoint)
+	if queue, ok := app.SyncQueues.Get(queueName); ok {
+		// Like AWS, the queue's policy has to let the topic send to it, or the message goes nowhere.
+		if app.CurrentEnvironment.PolicyEvaluation.Enabled && !queue.Allows(app.PolicyRequest{
+			Action:    "sqs:SendMessage",
+			Service:   "sns.amazonaws.com",
+			SourceArn: requestBody.TopicArn,
+		}) {
+			log.Warnf("Queue %s doesn't allow topic %s to send to it, message discarded", queueName, topicName)
+			return nil
+		}
 		msg := app.Message{}
 
 		if subscription.Raw == false {
-			m, err := createMessageBody(subscription, requestBody.Message, requestBody.Subject, requestBody.MessageStructure, messageAttributes)
+			m, err := createMessageBody(subscription, requestBody.Message, requestBody.Subject, requestBody.MessageStructure, messageAttributes, messageId, sequenceNumber)
 			if err != nil {
 				return err
 			}
@@ -109,9 +179,20 @@ func publishSQS(subscription *app.Subscription, topicName string, requestBody *m
 
 		msg.MD5OfMessageBody = common.GetMD5Hash(requestBody.Message)
 		msg.Uuid, _ = common.NewUUID()
-		app.SyncQueues.Lock()
-		app.SyncQueues.Queues[queueName].Messages = append(app.SyncQueues.Queues[queueName].Messages, msg)
-		app.SyncQueues.Unlock()
+		msg.SentTime = time.Now()
+		queue.Lock()
+		if queue.IsFIFO {
+			msg.GroupID = requestBody.MessageGroupId
+			msg.DeduplicationID = queue.DeduplicationId(requestBody.MessageDeduplicationId, string(msg.MessageBody))
+		}
+		stored, duplicate := queue.Enqueue(msg)
+		if duplicate {
+			queue.Unlock()
+			log.Debugf("Message with deduplicationId [%s] in queue [%s] is duplicate", msg.DeduplicationID, queueName)
+			return nil
+		}
+		persistence.MessageUpdated(queue, stored)
+		queue.Unlock()
 
 		log.Infof("%s: Topic: %s(%s), Message: %s\n", time.Now().Format("2006-01-02 15:04:05"), topicName, queueName, msg.MessageBody)
 	} else {
@@ -120,9 +201,8 @@ func publishSQS(subscription *app.Subscription, topicName string, requestBody *m
 	return nil
 }
 
-func publishHTTP(subs *app.Subscription, requestBody *models.PublishRequest) {
-	messageAttributes := utils.ConvertToOldMessageAttributeValueStructure(requestBody.MessageAttributes)
-	id := uuid.NewString()
+func publishHTTP(subs *app.Subscription, requestBody *models.PublishRequest, id string) {
+	messageAttributes := requestBody.MessageAttributes
 	msg := app.SNSMessage{
 		Type:              "Notification",
 		MessageId:         id,
@@ -153,9 +233,8 @@ func publishHTTP(subs *app.Subscription, requestBody *models.PublishRequest) {
 }
 
 func createMessageBody(subs *app.Subscription, msg string, subject string, messageStructure string,
-	messageAttributes map[string]app.MessageAttributeValue) ([]byte, error) {
+	messageAttributes map[string]app.MessageAttributeValue, msgId string, sequenceNumber string) ([]byte, error) {
 
-	msgId := uuid.NewString()
 	message := app.SNSMessage{
 		Type:              "Notification",
 		MessageId:         msgId,
@@ -166,6 +245,7 @@ func createMessageBody(subs *app.Subscription, msg string, subject string, messa
 		SigningCertURL:    fmt.Sprintf("http://%s:%s/SimpleNotificationService/%s.pem", app.CurrentEnvironment.Host, app.CurrentEnvironment.Port, msgId),
 		UnsubscribeURL:    fmt.Sprintf("http://%s:%s/?Action=Unsubscribe&SubscriptionArn=%s", app.CurrentEnvironment.Host, app.CurrentEnvironment.Port, subs.SubscriptionArn),
 		MessageAttributes: formatAttributes(messageAttributes),
+		SequenceNumber:    sequenceNumber,
 	}
 
 	if app.MessageStructure(messageStructure) == app.MessageStructureJSON {
@@ -192,9 +272,13 @@ func createMessageBody(subs *app.Subscription, msg string, subject string, messa
 func formatAttributes(values map[string]app.MessageAttributeValue) map[string]app.MsgAttr {
 	attr := make(map[string]app.MsgAttr)
 	for k, v := range values {
+		value := v.StringValue
+		if v.BaseType() == "Binary" {
+			value = v.BinaryValue
+		}
 		attr[k] = app.MsgAttr{
 			Type:  v.DataType,
-			Value: v.Value,
+			Value: value,
 		}
 	}
 	return attr
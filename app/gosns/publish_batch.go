@@ -0,0 +1,129 @@
+package gosns
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/common"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// AWS limit of PublishBatch, see https://docs.aws.amazon.com/sns/latest/api/API_PublishBatch.html
+const maxPublishBatchSize = 262144
+
+func PublishBatchV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewPublishBatchRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - PublishBatchV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+	}
+	if requestBody.TopicArn == "" {
+		return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+	}
+
+	entries := requestBody.PublishBatchRequestEntries
+	if errKey := validatePublishBatch(entries); errKey != "" {
+		return utils.CreateErrorResponseV1(errKey, false)
+	}
+
+	arnSegments := strings.Split(requestBody.TopicArn, ":")
+	topicName := arnSegments[len(arnSegments)-1]
+
+	app.SyncTopics.RLock()
+	topic, ok := app.SyncTopics.Topics[topicName]
+	var subscriptions []*app.Subscription
+	isFifo, contentBasedDeduplication := false, false
+	if ok {
+		subscriptions = append(subscriptions, topic.Subscriptions...)
+		isFifo = topic.FifoTopic
+		contentBasedDeduplication = topic.ContentBasedDeduplication
+	}
+	app.SyncTopics.RUnlock()
+	if !ok {
+		return utils.CreateErrorResponseV1("TopicNotFound", false)
+	}
+
+	// The entries of a batch to a FIFO topic are published in order, with nothing in between.
+	if isFifo {
+		topic.Publishing.Lock()
+		defer topic.Publishing.Unlock()
+	}
+
+	successful := make([]models.PublishBatchResultEntry, 0, len(entries))
+	failed := make([]models.BatchResultErrorEntry, 0)
+	for _, entry := range entries {
+		errKey := validatePublishEntry(entry, isFifo, contentBasedDeduplication)
+		if errKey != "" {
+			er := models.SnsErrors[errKey]
+			failed = append(failed, models.BatchResultErrorEntry{
+				Code:        er.Code,
+				Id:          entry.Id,
+				Message:     er.Message,
+				SenderFault: true,
+			})
+			continue
+		}
+
+		messageId, sequenceNumber := publishToTopic(topic, subscriptions, &models.PublishRequest{
+			TopicArn:               requestBody.TopicArn,
+			Message:                entry.Message,
+			MessageAttributes:      entry.MessageAttributes,
+			MessageDeduplicationId: entry.MessageDeduplicationId,
+			MessageGroupId:         entry.MessageGroupId,
+			MessageStructure:       entry.MessageStructure,
+			Subject:                entry.Subject,
+		}, uuid.NewString())
+		successful = append(successful, models.PublishBatchResultEntry{
+			Id:             entry.Id,
+			MessageId:      messageId,
+			SequenceNumber: sequenceNumber,
+		})
+	}
+
+	respStruct := models.PublishBatchResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Result:   models.PublishBatchResult{Successful: successful, Failed: failed},
+		Metadata: app.ResponseMetadata{RequestId: uuid.NewString()},
+	}
+	return http.StatusOK, respStruct
+}
+
+// validatePublishBatch returns the key of the error that fails the whole batch, if there is one: AWS wants
+// 1 to 10 entries with distinct IDs, and all of their messages together within the size of a single one.
+func validatePublishBatch(entries []models.PublishBatchRequestEntry) string {
+	ids := make([]string, 0, len(entries))
+	size := 0
+	for _, entry := range entries {
+		ids = append(ids, entry.Id)
+		size += common.MessageSize(entry.Message, entry.MessageAttributes)
+	}
+	if errKey := common.ValidateBatchEntryIds(ids); errKey != "" {
+		return errKey
+	}
+	if size > maxPublishBatchSize {
+		return "BatchRequestTooLong"
+	}
+	return ""
+}
+
+// validatePublishEntry returns the key of the error of a single entry of a batch, if there is one.
+func validatePublishEntry(entry models.PublishBatchRequestEntry, isFifo bool, contentBasedDeduplication bool) string {
+	if entry.Message == "" {
+		return "EmptyMessage"
+	}
+	if app.MessageStructure(entry.MessageStructure) == app.MessageStructureJSON {
+		if _, err := extractMessageFromJSON(entry.Message, string(app.ProtocolDefault)); err != nil {
+			return "InvalidMessageStructure"
+		}
+	}
+	if isFifo {
+		return validateFifoMessage(entry.MessageGroupId, entry.MessageDeduplicationId, contentBasedDeduplication)
+	}
+	return ""
+}
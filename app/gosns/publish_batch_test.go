@@ -0,0 +1,203 @@
+package gosns
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setPublishBatchRequest(topicArn string, entries []models.PublishBatchRequestEntry) {
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.PublishBatchRequest)
+		*v = models.PublishBatchRequest{TopicArn: topicArn, PublishBatchRequestEntries: entries}
+		return true
+	}
+}
+
+func TestPublishBatchV1_success_sqs(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	attributes := map[string]app.MessageAttributeValue{"trace": {DataType: "String", StringValue: "abc"}}
+	setPublishBatchRequest(app.SyncTopics.Topics["unit-topic1"].Arn, []models.PublishBatchRequestEntry{
+		{Id: "first", Message: "message-1", MessageAttributes: attributes},
+		{Id: "second", Message: `{"default":"message-2","sqs":"sqs-message-2"}`, MessageStructure: "json"},
+	})
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := PublishBatchV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	result := response.(models.PublishBatchResponse).Result
+	assert.Len(t, result.Successful, 2)
+	assert.Equal(t, "first", result.Successful[0].Id)
+	assert.NotEmpty(t, result.Successful[0].MessageId)
+	assert.Equal(t, "second", result.Successful[1].Id)
+	assert.Empty(t, result.Failed)
+
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "message-1", string(messages[0].MessageBody))
+	assert.Equal(t, attributes, messages[0].MessageAttributes)
+	assert.Equal(t, "sqs-message-2", string(messages[1].MessageBody))
+}
+
+func TestPublishBatchV1_subject_and_attributes_not_raw(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	setPublishBatchRequest(app.SyncTopics.Topics["unit-topic3"].Arn, []models.PublishBatchRequestEntry{
+		{Id: "first", Message: "message-1", Subject: "the-subject", MessageAttributes: map[string]app.MessageAttributeValue{
+			"trace": {DataType: "String", StringValue: "abc"},
+		}},
+	})
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ := PublishBatchV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	messages := app.SyncQueues.Queues["subscribed-queue3"].Messages.All()
+	assert.Len(t, messages, 1)
+	body := string(messages[0].MessageBody)
+	assert.Contains(t, body, `"Subject":"the-subject"`)
+	assert.Contains(t, body, `"Message":"message-1"`)
+	assert.Contains(t, body, `"trace":{"Type":"String","Value":"abc"}`)
+}
+
+func TestPublishBatchV1_failed_entries(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	setPublishBatchRequest(app.SyncTopics.Topics["unit-topic1"].Arn, []models.PublishBatchRequestEntry{
+		{Id: "empty"},
+		{Id: "good", Message: "message"},
+		{Id: "no-default", Message: `{"sqs":"message"}`, MessageStructure: "json"},
+	})
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := PublishBatchV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	result := response.(models.PublishBatchResponse).Result
+	assert.Len(t, result.Successful, 1)
+	assert.Equal(t, "good", result.Successful[0].Id)
+	assert.Equal(t, []models.BatchResultErrorEntry{
+		{Id: "empty", Code: "InvalidParameter", Message: models.SnsErrors["EmptyMessage"].Message, SenderFault: true},
+		{Id: "no-default", Code: "InvalidParameter", Message: models.SnsErrors["InvalidMessageStructure"].Message, SenderFault: true},
+	}, result.Failed)
+	assert.Len(t, app.SyncQueues.Queues["subscribed-queue1"].Messages.All(), 1)
+}
+
+func TestPublishBatchV1_fifo_topic_entries(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	topicArn := "arn:aws:sns:region:accountID:unit-topic.fifo"
+	app.SyncTopics.Topics["unit-topic.fifo"] = &app.Topic{Name: "unit-topic.fifo", Arn: topicArn, FifoTopic: true}
+	setPublishBatchRequest(topicArn, []models.PublishBatchRequestEntry{
+		{Id: "no-group", Message: "message", MessageDeduplicationId: "dedup-1"},
+		{Id: "no-dedup", Message: "message", MessageGroupId: "group-1"},
+		{Id: "good", Message: "message", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-1"},
+		{Id: "duplicate", Message: "message", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-1"},
+	})
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	_, response := PublishBatchV1(r)
+
+	result := response.(models.PublishBatchResponse).Result
+	assert.Len(t, result.Successful, 2)
+	assert.Equal(t, "good", result.Successful[0].Id)
+	assert.NotEmpty(t, result.Successful[0].MessageId)
+	assert.NotEmpty(t, result.Successful[0].SequenceNumber)
+	assert.Equal(t, "duplicate", result.Successful[1].Id)
+	assert.Equal(t, result.Successful[0].MessageId, result.Successful[1].MessageId)
+	assert.Equal(t, result.Successful[0].SequenceNumber, result.Successful[1].SequenceNumber)
+	assert.Len(t, result.Failed, 2)
+	assert.Equal(t, models.SnsErrors["MissingMessageGroupId"].Message, result.Failed[0].Message)
+	assert.Equal(t, models.SnsErrors["MissingDeduplicationId"].Message, result.Failed[1].Message)
+}
+
+func TestPublishBatchV1_batch_errors(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	tooMany := make([]models.PublishBatchRequestEntry, 0, 11)
+	for i := 0; i < 11; i++ {
+		tooMany = append(tooMany, models.PublishBatchRequestEntry{Id: fmt.Sprintf("id-%d", i), Message: "message"})
+	}
+	topicArn := app.SyncTopics.Topics["unit-topic1"].Arn
+
+	for errKey, entries := range map[string][]models.PublishBatchRequestEntry{
+		"EmptyBatchRequest":            {},
+		"TooManyEntriesInBatchRequest": tooMany,
+		"BatchEntryIdsNotDistinct":     {{Id: "same", Message: "message"}, {Id: "same", Message: "message"}},
+		"InvalidBatchEntryId":          {{Id: "not valid!", Message: "message"}},
+		"BatchRequestTooLong": {
+			{Id: "first", Message: strings.Repeat("a", 200000)},
+			{Id: "second", Message: strings.Repeat("b", 62145)},
+		},
+	} {
+		setPublishBatchRequest(topicArn, entries)
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		status, response := PublishBatchV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, status, errKey)
+		assert.Equal(t, models.SnsErrors[errKey].Response(), response.(models.ErrorResponse).Result, errKey)
+	}
+	assert.Empty(t, app.SyncQueues.Queues["subscribed-queue1"].Messages.All())
+}
+
+func TestPublishBatchV1_topic_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	setPublishBatchRequest("arn:aws:sns:region:accountID:not-a-topic", []models.PublishBatchRequestEntry{{Id: "first", Message: "message"}})
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := PublishBatchV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, models.SnsErrors["TopicNotFound"].Response(), response.(models.ErrorResponse).Result)
+}
+
+func TestPublishBatchV1_request_transformer_error(t *testing.T) {
+	defer func() {
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		return false
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ := PublishBatchV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -44,7 +44,7 @@ func TestPublishV1_success_sqs(t *testing.T) {
 	_, ok := response.(models.PublishResponse)
 	assert.True(t, ok)
 
-	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
 	assert.Len(t, messages, 1)
 	assert.Equal(t, message, string(messages[0].MessageBody))
 }
@@ -145,7 +145,7 @@ func TestPublishV1_success_with_optional_fields(t *testing.T) {
 	_, ok := response.(models.PublishResponse)
 	assert.True(t, ok)
 
-	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
 	assert.Len(t, messages, 1)
 	assert.Equal(t, message, string(messages[0].MessageBody))
 }
@@ -249,11 +249,11 @@ func Test_publishSQS_success_raw(t *testing.T) {
 		TopicArn: topicArn,
 		Message:  message,
 	}
-	err := publishSQS(sub, "unit-topic1", &request)
+	err := publishSQS(sub, "unit-topic1", &request, "message-id", "")
 
 	assert.Nil(t, err)
 
-	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
 	assert.Len(t, messages, 1)
 	assert.Equal(t, message, string(messages[0].MessageBody))
 }
@@ -277,11 +277,11 @@ func Test_publishSQS_success_json(t *testing.T) {
 		TopicArn: topicArn,
 		Message:  message,
 	}
-	err := publishSQS(sub, "unit-topic1", &request)
+	err := publishSQS(sub, "unit-topic1", &request, "message-id", "")
 
 	assert.Nil(t, err)
 
-	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
 	assert.Len(t, messages, 1)
 
 	body := string(messages[0].MessageBody)
@@ -320,7 +320,7 @@ func Test_publishSQS_filter_policy_not_satisfied_by_attributes(t *testing.T) {
 			},
 		},
 	}
-	err := publishSQS(sub, "unit-topic1", &request)
+	err := publishSQS(sub, "unit-topic1", &request, "message-id", "")
 
 	assert.Nil(t, err)
 }
@@ -344,11 +344,51 @@ func Test_publishSQS_missing_queue_returns_nil(t *testing.T) {
 		TopicArn: topicArn,
 		Message:  message,
 	}
-	err := publishSQS(sub, "unit-topic1", &request)
+	err := publishSQS(sub, "unit-topic1", &request, "message-id", "")
 
 	assert.Nil(t, err)
 }
 
+func Test_publishSQS_denied_by_queue_policy_discards_message(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+	}()
+	app.CurrentEnvironment.PolicyEvaluation.Enabled = true
+
+	topicArn := app.SyncTopics.Topics["unit-topic1"].Arn
+	sub := app.SyncTopics.Topics["unit-topic1"].Subscriptions[0]
+	request := models.PublishRequest{
+		TopicArn: topicArn,
+		Message:  "{\"IAm\": \"aMessage\"}",
+	}
+	err := publishSQS(sub, "unit-topic1", &request, "message-id", "")
+
+	assert.Nil(t, err)
+	assert.Len(t, app.SyncQueues.Queues["subscribed-queue1"].Messages.All(), 0)
+}
+
+func Test_publishSQS_allowed_by_queue_policy(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+	}()
+	app.CurrentEnvironment.PolicyEvaluation.Enabled = true
+
+	topicArn := app.SyncTopics.Topics["unit-topic1"].Arn
+	app.SyncQueues.Queues["subscribed-queue1"].Policy = fmt.Sprintf(`{"Statement":[{"Effect":"Allow",
+		"Principal":{"Service":"sns.amazonaws.com"},"Action":"sqs:SendMessage","Condition":{"ArnEquals":{"aws:SourceArn":"%s"}}}]}`, topicArn)
+	sub := app.SyncTopics.Topics["unit-topic1"].Subscriptions[0]
+	request := models.PublishRequest{
+		TopicArn: topicArn,
+		Message:  "{\"IAm\": \"aMessage\"}",
+	}
+	err := publishSQS(sub, "unit-topic1", &request, "message-id", "")
+
+	assert.Nil(t, err)
+	assert.Len(t, app.SyncQueues.Queues["subscribed-queue1"].Messages.All(), 1)
+}
+
 // Most other scenarios should be tested in the functions above, if reasonably possible
 func Test_publishHTTP_success(t *testing.T) {
 	called := false
@@ -376,7 +416,7 @@ func Test_publishHTTP_success(t *testing.T) {
 		Message:  message,
 	}
 
-	publishHTTP(sub, &request)
+	publishHTTP(sub, &request, "message-id")
 
 	assert.True(t, called)
 }
@@ -399,7 +439,7 @@ func Test_publishHTTP_callEndpoint_failure(t *testing.T) {
 		Message:  message,
 	}
 
-	publishHTTP(sub, &request)
+	publishHTTP(sub, &request, "message-id")
 	// swallows all errors
 }
 
@@ -413,15 +453,14 @@ func Test_createMessageBody_success_json(t *testing.T) {
 	subject := "I'm the subject"
 	attrs := map[string]app.MessageAttributeValue{
 		"test": app.MessageAttributeValue{
-			Name:     "MyAttr",
-			DataType: "string",
-			Value:    "value",
+			DataType:    "string",
+			StringValue: "value",
 		},
 	}
 
 	sub := app.SyncTopics.Topics["unit-topic1"].Subscriptions[0]
 
-	result, err := createMessageBody(sub, message, subject, "json", attrs)
+	result, err := createMessageBody(sub, message, subject, "json", attrs, "message-id", "")
 
 	assert.Nil(t, err)
 
@@ -449,15 +488,14 @@ func Test_createMessageBody_success_raw(t *testing.T) {
 	subject := "I'm the subject"
 	attrs := map[string]app.MessageAttributeValue{
 		"test": app.MessageAttributeValue{
-			Name:     "MyAttr",
-			DataType: "string",
-			Value:    "value",
+			DataType:    "string",
+			StringValue: "value",
 		},
 	}
 
 	sub := app.SyncTopics.Topics["unit-topic1"].Subscriptions[0]
 
-	result, err := createMessageBody(sub, message, subject, "not-json", attrs)
+	result, err := createMessageBody(sub, message, subject, "not-json", attrs, "message-id", "")
 
 	assert.Nil(t, err)
 
@@ -478,14 +516,12 @@ func Test_createMessageBody_success_raw(t *testing.T) {
 func Test_formatAttributes_success(t *testing.T) {
 	attrs := map[string]app.MessageAttributeValue{
 		"test1": app.MessageAttributeValue{
-			Name:     "MyAttr",
-			DataType: "string",
-			Value:    "value1",
+			DataType:    "string",
+			StringValue: "value1",
 		},
 		"test2": app.MessageAttributeValue{
-			Name:     "MyAttr",
-			DataType: "string",
-			Value:    "value2",
+			DataType:    "string",
+			StringValue: "value2",
 		},
 	}
 	expected := map[string]app.MsgAttr{
@@ -497,3 +533,117 @@ func Test_formatAttributes_success(t *testing.T) {
 
 	assert.Equal(t, expected, result)
 }
+
+// addFifoTopic adds a FIFO topic with a raw subscription to a FIFO queue, returning the topic and queue.
+func addFifoTopic(contentBasedDeduplication bool) (*app.Topic, *app.Queue) {
+	queue := &app.Queue{
+		Name:       "subscribed-queue.fifo",
+		Arn:        "arn:aws:sqs:region:accountID:subscribed-queue.fifo",
+		IsFIFO:     true,
+		Duplicates: make(map[string]app.Deduplicated),
+	}
+	app.SyncQueues.Queues[queue.Name] = queue
+	topicArn := "arn:aws:sns:region:accountID:unit-topic.fifo"
+	topic := &app.Topic{
+		Name:                      "unit-topic.fifo",
+		Arn:                       topicArn,
+		FifoTopic:                 true,
+		ContentBasedDeduplication: contentBasedDeduplication,
+		Subscriptions: []*app.Subscription{
+			{TopicArn: topicArn, Protocol: "sqs", SubscriptionArn: topicArn + ":sub-1", EndPoint: queue.Arn, Raw: true},
+		},
+	}
+	app.SyncTopics.Topics[topic.Name] = topic
+	return topic, queue
+}
+
+func publishRequest(request models.PublishRequest) (int, interfaces.AbstractResponseBody) {
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.PublishRequest)
+		*v = request
+		return true
+	}
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	return PublishV1(r)
+}
+
+func TestPublishV1_fifo_topic_to_fifo_queue_keeps_group_and_order(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	topic, queue := addFifoTopic(false)
+
+	sequenceNumbers := []string{}
+	for i := 1; i <= 3; i++ {
+		status, response := publishRequest(models.PublishRequest{
+			TopicArn:               topic.Arn,
+			Message:                fmt.Sprintf("message-%d", i),
+			MessageGroupId:         "group-1",
+			MessageDeduplicationId: fmt.Sprintf("dedup-%d", i),
+		})
+		assert.Equal(t, http.StatusOK, status)
+		sequenceNumbers = append(sequenceNumbers, response.(models.PublishResponse).Result.SequenceNumber)
+	}
+	assert.True(t, sequenceNumbers[0] != "" && sequenceNumbers[0] < sequenceNumbers[1] && sequenceNumbers[1] < sequenceNumbers[2])
+
+	messages := queue.Messages.All()
+	assert.Len(t, messages, 3)
+	for i, msg := range messages {
+		assert.Equal(t, fmt.Sprintf("message-%d", i+1), string(msg.MessageBody))
+		assert.Equal(t, "group-1", msg.GroupID)
+		assert.Equal(t, fmt.Sprintf("dedup-%d", i+1), msg.DeduplicationID)
+		assert.NotEmpty(t, msg.SequenceNumber)
+	}
+	assert.True(t, messages[0].SequenceNumber < messages[1].SequenceNumber && messages[1].SequenceNumber < messages[2].SequenceNumber)
+}
+
+func TestPublishV1_fifo_topic_deduplicates_messages(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	topic, queue := addFifoTopic(true)
+	topic.Subscriptions[0].Raw = false
+
+	request := models.PublishRequest{TopicArn: topic.Arn, Message: "message", MessageGroupId: "group-1"}
+	status, response := publishRequest(request)
+	assert.Equal(t, http.StatusOK, status)
+	original := response.(models.PublishResponse).Result
+	assert.NotEmpty(t, original.MessageId)
+	assert.NotEmpty(t, original.SequenceNumber)
+
+	status, response = publishRequest(request)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, original, response.(models.PublishResponse).Result)
+
+	messages := queue.Messages.All()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, topic.DeduplicationId("", "message"), messages[0].DeduplicationID)
+
+	var envelope app.SNSMessage
+	assert.Nil(t, json.Unmarshal(messages[0].MessageBody, &envelope))
+	assert.Equal(t, original.MessageId, envelope.MessageId)
+	assert.Equal(t, original.SequenceNumber, envelope.SequenceNumber)
+}
+
+func TestPublishV1_fifo_topic_errors(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	topic, queue := addFifoTopic(false)
+
+	status, response := publishRequest(models.PublishRequest{TopicArn: topic.Arn, Message: "message", MessageDeduplicationId: "dedup-1"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, models.SnsErrors["MissingMessageGroupId"].Message, response.(models.ErrorResponse).Result.Message)
+
+	status, response = publishRequest(models.PublishRequest{TopicArn: topic.Arn, Message: "message", MessageGroupId: "group-1"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, models.SnsErrors["MissingDeduplicationId"].Message, response.(models.ErrorResponse).Result.Message)
+
+	assert.Len(t, queue.Messages.All(), 0)
+}
@@ -38,6 +38,7 @@ func SetSubscriptionAttributesV1(req *http.Request) (int, interfaces.AbstractRes
 		} else {
 			sub.Raw = false
 		}
+		subscriptionUpdated(sub)
 		app.SyncTopics.Unlock()
 
 	case "FilterPolicy":
@@ -48,6 +49,7 @@ func SetSubscriptionAttributesV1(req *http.Request) (int, interfaces.AbstractRes
 		}
 		app.SyncTopics.Lock()
 		sub.FilterPolicy = filterPolicy
+		subscriptionUpdated(sub)
 		app.SyncTopics.Unlock()
 
 	case "DeliveryPolicy", "FilterPolicyScope", "RedrivePolicy", "SubscriptionRoleArn":
@@ -0,0 +1,105 @@
+package gosns
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+func SetTopicAttributesV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewSetTopicAttributesRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - SetTopicAttributesV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+	}
+
+	uriSegments := strings.Split(requestBody.TopicArn, ":")
+	topicName := uriSegments[len(uriSegments)-1]
+
+	app.SyncTopics.Lock()
+	defer app.SyncTopics.Unlock()
+	topic, ok := app.SyncTopics.Topics[topicName]
+	if !ok {
+		return utils.CreateErrorResponseV1("TopicNotFound", false)
+	}
+
+	if !setTopicAttribute(topic, requestBody.AttributeName, requestBody.AttributeValue) {
+		log.Errorf("Invalid value [%s] for attribute [%s] of topic [%s]", requestBody.AttributeValue, requestBody.AttributeName, topicName)
+		return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+	}
+	persistence.TopicUpdated(topic)
+
+	respStruct := models.SetTopicAttributesResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Metadata: app.ResponseMetadata{RequestId: uuid.NewString()},
+	}
+	return http.StatusOK, respStruct
+}
+
+// setTopicAttribute sets one of the attributes of `topic` that can be changed, after checking its value.
+// It returns false, leaving `topic` alone, for an invalid value or an attribute that can't be set.
+// NOTE: the caller must hold `app.SyncTopics`.
+func setTopicAttribute(topic *app.Topic, name string, value string) bool {
+	switch name {
+	case "DisplayName":
+		topic.DisplayName = value
+	case "Policy":
+		if value != "" {
+			if _, err := app.ParsePolicy(value); err != nil {
+				return false
+			}
+		}
+		topic.Policy = value
+	case "DeliveryPolicy":
+		if !isJSONObject(value) {
+			return false
+		}
+		topic.DeliveryPolicy = value
+	case "SignatureVersion":
+		if value != "1" && value != "2" {
+			return false
+		}
+		topic.SignatureVersion = value
+	case "TracingConfig":
+		if value != "PassThrough" && value != "Active" {
+			return false
+		}
+		topic.TracingConfig = value
+	case "KmsMasterKeyId":
+		topic.KmsMasterKeyId = value
+	case "ContentBasedDeduplication":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil || !topic.FifoTopic {
+			return false
+		}
+		topic.ContentBasedDeduplication = enabled
+	case "ArchivePolicy":
+		if !topic.FifoTopic || !isJSONObject(value) {
+			return false
+		}
+		topic.ArchivePolicy = value
+	default:
+		// Including FifoTopic, which can only be chosen when the topic is created.
+		return false
+	}
+	return true
+}
+
+// isJSONObject reports whether `value` is empty, to remove the attribute, or a JSON object.
+func isJSONObject(value string) bool {
+	if value == "" {
+		return true
+	}
+	var tmp map[string]interface{}
+	return json.Unmarshal([]byte(value), &tmp) == nil
+}
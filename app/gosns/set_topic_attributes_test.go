@@ -0,0 +1,132 @@
+package gosns
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func setTopicAttributeRequest(name string, value string) {
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.SetTopicAttributesRequest)
+		*v = models.SetTopicAttributesRequest{TopicArn: attributesTopicArn, AttributeName: name, AttributeValue: value}
+		return true
+	}
+}
+
+func TestSetTopicAttributesV1_success(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	topic := &app.Topic{Name: "attributes-topic", Arn: attributesTopicArn}
+	app.SyncTopics.Topics["attributes-topic"] = topic
+
+	for _, attribute := range []struct{ name, value string }{
+		{"DisplayName", "Attributes"},
+		{"Policy", `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"sns:Publish"}]}`},
+		{"DeliveryPolicy", `{"http":{}}`},
+		{"SignatureVersion", "2"},
+		{"TracingConfig", "PassThrough"},
+		{"KmsMasterKeyId", "alias/aws/sns"},
+	} {
+		setTopicAttributeRequest(attribute.name, attribute.value)
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		status, response := SetTopicAttributesV1(r)
+
+		assert.Equal(t, http.StatusOK, status, attribute.name)
+		assert.Equal(t, models.BASE_XMLNS, response.(models.SetTopicAttributesResponse).Xmlns)
+	}
+
+	assert.Equal(t, "Attributes", topic.DisplayName)
+	assert.Equal(t, `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"sns:Publish"}]}`, topic.Policy)
+	assert.Equal(t, `{"http":{}}`, topic.DeliveryPolicy)
+	assert.Equal(t, "2", topic.SignatureVersion)
+	assert.Equal(t, "PassThrough", topic.TracingConfig)
+	assert.Equal(t, "alias/aws/sns", topic.KmsMasterKeyId)
+
+	setTopicAttributeRequest("Policy", "")
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ := SetTopicAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "", topic.Policy)
+}
+
+func TestSetTopicAttributesV1_invalid_values(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	topic := &app.Topic{Name: "attributes-topic", Arn: attributesTopicArn, SignatureVersion: "1"}
+	app.SyncTopics.Topics["attributes-topic"] = topic
+
+	for _, attribute := range []struct{ name, value string }{
+		{"Policy", `{"i-am":"not-a-policy"}`},
+		{"DeliveryPolicy", "not-json"},
+		{"SignatureVersion", "3"},
+		{"TracingConfig", "Sometimes"},
+		{"FifoTopic", "true"},
+		{"ContentBasedDeduplication", "true"},
+		{"ArchivePolicy", `{"MessageRetentionPeriod":"30"}`},
+		{"NotAnAttribute", "value"},
+	} {
+		setTopicAttributeRequest(attribute.name, attribute.value)
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		status, response := SetTopicAttributesV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, status, attribute.name)
+		assert.Equal(t, models.SnsErrors["InvalidParameterValue"].Response(), response.(models.ErrorResponse).Result, attribute.name)
+	}
+	assert.Equal(t, &app.Topic{Name: "attributes-topic", Arn: attributesTopicArn, SignatureVersion: "1"}, topic)
+}
+
+func TestSetTopicAttributesV1_fifo_topic_attributes(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	topic := &app.Topic{Name: "attributes-topic", Arn: attributesTopicArn, FifoTopic: true}
+	app.SyncTopics.Topics["attributes-topic"] = topic
+
+	setTopicAttributeRequest("ContentBasedDeduplication", "true")
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ := SetTopicAttributesV1(r)
+	assert.Equal(t, http.StatusOK, status)
+
+	setTopicAttributeRequest("ArchivePolicy", `{"MessageRetentionPeriod":"30"}`)
+	_, r = test.GenerateRequestInfo("POST", "/", nil, true)
+	status, _ = SetTopicAttributesV1(r)
+	assert.Equal(t, http.StatusOK, status)
+
+	assert.True(t, topic.ContentBasedDeduplication)
+	assert.Equal(t, `{"MessageRetentionPeriod":"30"}`, topic.ArchivePolicy)
+}
+
+func TestSetTopicAttributesV1_topic_not_found(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	setTopicAttributeRequest("DisplayName", "Attributes")
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := SetTopicAttributesV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, models.SnsErrors["TopicNotFound"].Response(), response.(models.ErrorResponse).Result)
+}
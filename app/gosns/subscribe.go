@@ -10,6 +10,7 @@ import (
 
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	"github.com/Admiral-Piett/goaws/app/interfaces"
@@ -44,59 +45,80 @@ func SubscribeV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	//Create the response
 	requestId := uuid.NewString()
 	respStruct := models.SubscribeResponse{Xmlns: models.BASE_XMLNS, Result: models.SubscribeResult{SubscriptionArn: subscription.SubscriptionArn}, Metadata: app.ResponseMetadata{RequestId: requestId}}
-	if app.SyncTopics.Topics[topicName] != nil {
-		app.SyncTopics.Lock()
-		isDuplicate := false
-		// Duplicate check
-		for _, sub := range app.SyncTopics.Topics[topicName].Subscriptions {
-			if sub.EndPoint == requestBody.Endpoint && sub.TopicArn == requestBody.TopicArn {
-				isDuplicate = true
-				sub.SubscriptionArn = subscription.SubscriptionArn
-			}
-		}
-		if !isDuplicate {
-			app.SyncTopics.Topics[topicName].Subscriptions = append(app.SyncTopics.Topics[topicName].Subscriptions, subscription)
-			log.WithFields(extraLogFields).Debug("Created subscription")
-		}
+	app.SyncTopics.Lock()
+	topic, ok := app.SyncTopics.Topics[topicName]
+	if !ok {
+		app.SyncTopics.Unlock()
+		return utils.CreateErrorResponseV1("InvalidParameterValue", false)
+	}
+	if errKey := validateFifoSubscription(topic, subscription); errKey != "" {
 		app.SyncTopics.Unlock()
+		log.WithFields(extraLogFields).Error("Invalid endpoint for the topic")
+		return utils.CreateErrorResponseV1(errKey, false)
+	}
+	isDuplicate := false
+	// Duplicate check
+	for _, sub := range topic.Subscriptions {
+		if sub.EndPoint == requestBody.Endpoint && sub.TopicArn == requestBody.TopicArn {
+			isDuplicate = true
+			sub.SubscriptionArn = subscription.SubscriptionArn
+		}
+	}
+	if !isDuplicate {
+		topic.Subscriptions = append(topic.Subscriptions, subscription)
+		log.WithFields(extraLogFields).Debug("Created subscription")
+	}
+	persistence.TopicUpdated(topic)
+	app.SyncTopics.Unlock()
 
-		if app.Protocol(subscription.Protocol) == app.ProtocolHTTP || app.Protocol(subscription.Protocol) == app.ProtocolHTTPS {
-			id := uuid.NewString()
-			token := uuid.NewString()
+	if app.Protocol(subscription.Protocol) == app.ProtocolHTTP || app.Protocol(subscription.Protocol) == app.ProtocolHTTPS {
+		id := uuid.NewString()
+		token := uuid.NewString()
 
-			TOPIC_DATA[requestBody.TopicArn] = &pendingConfirm{
-				subArn: subscription.SubscriptionArn,
-				token:  token,
-			}
+		TOPIC_DATA[requestBody.TopicArn] = &pendingConfirm{
+			subArn: subscription.SubscriptionArn,
+			token:  token,
+		}
 
-			//QUESTION - do we need this?
-			time.Sleep(time.Second)
+		//QUESTION - do we need this?
+		time.Sleep(time.Second)
 
-			snsMSG := &app.SNSMessage{
-				Type:             "SubscriptionConfirmation",
-				MessageId:        id,
-				Token:            token,
-				TopicArn:         requestBody.TopicArn,
-				Message:          fmt.Sprintf("You have chosen to subscribe to the topic %s.\nTo confirm the subscription, visit the SubscribeURL included in this message.", requestBody.TopicArn),
-				SigningCertURL:   fmt.Sprintf("http://%s:%s/SimpleNotificationService/%s.pem", app.CurrentEnvironment.Host, app.CurrentEnvironment.Port, requestId),
-				SignatureVersion: "1",
-				SubscribeURL:     fmt.Sprintf("http://%s:%s/?Action=ConfirmSubscription&TopicArn=%s&Token=%s", app.CurrentEnvironment.Host, app.CurrentEnvironment.Port, requestBody.TopicArn, token),
-				Timestamp:        time.Now().UTC().Format(time.RFC3339),
-			}
-			signature, err := signMessage(PrivateKEY, snsMSG)
-			if err != nil {
-				log.Error("Error signing message")
-			} else {
-				snsMSG.Signature = signature
-			}
-			err = callEndpoint(subscription.EndPoint, requestId, *snsMSG, subscription.Raw)
-			if err != nil {
-				log.Error("Error posting to url ", err)
-			}
+		snsMSG := &app.SNSMessage{
+			Type:             "SubscriptionConfirmation",
+			MessageId:        id,
+			Token:            token,
+			TopicArn:         requestBody.TopicArn,
+			Message:          fmt.Sprintf("You have chosen to subscribe to the topic %s.\nTo confirm the subscription, visit the SubscribeURL included in this message.", requestBody.TopicArn),
+			SigningCertURL:   fmt.Sprintf("http://%s:%s/SimpleNotificationService/%s.pem", app.CurrentEnvironment.Host, app.CurrentEnvironment.Port, requestId),
+			SignatureVersion: "1",
+			SubscribeURL:     fmt.Sprintf("http://%s:%s/?Action=ConfirmSubscription&TopicArn=%s&Token=%s", app.CurrentEnvironment.Host, app.CurrentEnvironment.Port, requestBody.TopicArn, token),
+			Timestamp:        time.Now().UTC().Format(time.RFC3339),
+		}
+		signature, err := signMessage(PrivateKEY, snsMSG)
+		if err != nil {
+			log.Error("Error signing message")
+		} else {
+			snsMSG.Signature = signature
+		}
+		err = callEndpoint(subscription.EndPoint, requestId, *snsMSG, subscription.Raw)
+		if err != nil {
+			log.Error("Error posting to url ", err)
 		}
-
-	} else {
-		return utils.CreateErrorResponseV1("InvalidParameterValue", false)
 	}
+
 	return http.StatusOK, respStruct
 }
+
+// validateFifoSubscription returns the key of the error when the subscription doesn't fit the kind of topic:
+// FIFO topics only deliver to FIFO queues, which in turn only take messages from FIFO topics.
+func validateFifoSubscription(topic *app.Topic, subscription *app.Subscription) string {
+	isFifoQueue := app.Protocol(subscription.Protocol) == app.ProtocolSQS &&
+		app.HasFIFOQueueName(queueNameFromEndpoint(subscription.EndPoint))
+	if topic.FifoTopic && !isFifoQueue {
+		return "InvalidFifoEndpoint"
+	}
+	if !topic.FifoTopic && isFifoQueue {
+		return "InvalidStandardEndpoint"
+	}
+	return ""
+}
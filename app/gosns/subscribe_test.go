@@ -168,3 +168,83 @@ func TestSubscribeV1_error_missing_topic(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, code)
 }
+
+func TestSubscribeV1_fifo_endpoints(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	fifoTopicArn := fmt.Sprintf("%s:%s", fixtures.BASE_SNS_ARN, "unit-topic.fifo")
+	app.SyncTopics.Topics["unit-topic.fifo"] = &app.Topic{Name: "unit-topic.fifo", Arn: fifoTopicArn, FifoTopic: true}
+	standardTopicArn := fmt.Sprintf("%s:%s", fixtures.BASE_SNS_ARN, "unit-topic2")
+
+	var tests = []struct {
+		topicArn string
+		protocol string
+		endpoint string
+		errKey   string
+	}{
+		{fifoTopicArn, "sqs", fmt.Sprintf("%s:%s", fixtures.BASE_URL, "unit-queue.fifo"), ""},
+		{fifoTopicArn, "sqs", fmt.Sprintf("%s:%s", fixtures.BASE_URL, "unit-queue2"), "InvalidFifoEndpoint"},
+		{fifoTopicArn, "http", "http://localhost/endpoint", "InvalidFifoEndpoint"},
+		{standardTopicArn, "sqs", fmt.Sprintf("%s:%s", fixtures.BASE_URL, "unit-queue.fifo"), "InvalidStandardEndpoint"},
+	}
+	for _, tt := range tests {
+		utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+			v := resultingStruct.(*models.SubscribeRequest)
+			*v = models.SubscribeRequest{TopicArn: tt.topicArn, Endpoint: tt.endpoint, Protocol: tt.protocol}
+			return true
+		}
+
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		code, res := SubscribeV1(r)
+
+		if tt.errKey == "" {
+			assert.Equal(t, http.StatusOK, code)
+			continue
+		}
+		assert.Equal(t, http.StatusBadRequest, code, tt.endpoint)
+		assert.Equal(t, models.SnsErrors[tt.errKey].Message, res.(models.ErrorResponse).Result.Message)
+	}
+	assert.Len(t, app.SyncTopics.Topics["unit-topic.fifo"].Subscriptions, 1)
+	assert.Len(t, app.SyncTopics.Topics["unit-topic2"].Subscriptions, 0)
+}
+
+func TestSubscribeV1_while_topics_change(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	topicArn := fmt.Sprintf("%s:%s", fixtures.BASE_SNS_ARN, "unit-topic2")
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.SubscribeRequest)
+		*v = models.SubscribeRequest{TopicArn: topicArn, Endpoint: fmt.Sprintf("%s:%s", fixtures.BASE_URL, "unit-queue2"), Protocol: "sqs"}
+		return true
+	}
+
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			app.SyncTopics.Lock()
+			app.SyncTopics.Topics[fmt.Sprintf("created-topic-%d", i)] = &app.Topic{Name: fmt.Sprintf("created-topic-%d", i)}
+			app.SyncTopics.Unlock()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		code, _ := SubscribeV1(r)
+		assert.Equal(t, http.StatusOK, code)
+	}
+	close(stop)
+	<-done
+
+	assert.Len(t, app.SyncTopics.Topics["unit-topic2"].Subscriptions, 1)
+}
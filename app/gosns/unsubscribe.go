@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -30,6 +31,8 @@ func UnsubscribeV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 				copy(topic.Subscriptions[i:], topic.Subscriptions[i+1:])
 				topic.Subscriptions[len(topic.Subscriptions)-1] = nil
 				topic.Subscriptions = topic.Subscriptions[:len(topic.Subscriptions)-1]
+				topic.SubscriptionsDeleted++
+				persistence.TopicUpdated(topic)
 
 				app.SyncTopics.Unlock()
 
@@ -0,0 +1,97 @@
+package gosqs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// The actions AddPermission can grant, each also grants its batch version.
+var permissionActions = map[string]bool{
+	"*":                       true,
+	"ChangeMessageVisibility": true,
+	"DeleteMessage":           true,
+	"GetQueueAttributes":      true,
+	"GetQueueUrl":             true,
+	"ReceiveMessage":          true,
+	"SendMessage":             true,
+}
+
+// AddPermissionV1 adds a statement to the queue's policy allowing the accounts the actions, with the label
+// as its Sid.
+//
+//	https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_AddPermission.html
+func AddPermissionV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewAddPermissionRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok || !queueNameRegexp.MatchString(requestBody.Label) ||
+		len(requestBody.AWSAccountIds) == 0 || len(requestBody.Actions) == 0 {
+		log.Error("Invalid Request - AddPermissionV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+	statement := app.PolicyStatement{
+		Sid:    requestBody.Label,
+		Effect: app.PolicyEffectAllow,
+	}
+	for _, account := range requestBody.AWSAccountIds {
+		statement.Principal.AWS = append(statement.Principal.AWS, "arn:aws:iam::"+account+":root")
+	}
+	for _, action := range requestBody.Actions {
+		if !permissionActions[action] {
+			log.Errorf("Invalid action for AddPermission: %s", action)
+			return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+		}
+		statement.Action = append(statement.Action, "SQS:"+action)
+	}
+
+	uriSegments := strings.Split(requestBody.QueueUrl, "/")
+	queueName := uriSegments[len(uriSegments)-1]
+
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
+		log.Errorf("Add Permission: %s, queue does not exist!!!", queueName)
+		return utils.CreateErrorResponseV1("QueueNotFound", true)
+	}
+	statement.Resource = app.PolicyValues{queue.Arn}
+
+	queue.Lock()
+	defer queue.Unlock()
+	policy := &app.PolicyDocument{Version: "2012-10-17", Id: queue.Arn + "/SQSDefaultPolicy"}
+	if queue.Policy != "" {
+		var err error
+		if policy, err = app.ParsePolicy(queue.Policy); err != nil {
+			log.Errorf("Invalid policy of queue %s: %s", queue.Name, err)
+			return utils.CreateErrorResponseV1("InvalidPolicy", true)
+		}
+	}
+	for _, existing := range policy.Statement {
+		if existing.Sid == requestBody.Label {
+			log.Errorf("Add Permission: %s, label %s already exists", queueName, requestBody.Label)
+			return utils.CreateErrorResponseV1("PermissionLabelExists", true)
+		}
+	}
+	policy.Statement = append(policy.Statement, statement)
+
+	text, err := json.Marshal(policy)
+	if err != nil {
+		log.Errorf("Add Permission: %s, %s", queueName, err)
+		return utils.CreateErrorResponseV1("InvalidPolicy", true)
+	}
+	queue.Policy = string(text)
+	queue.LastModified = time.Now()
+	persistence.QueueUpdated(queue)
+
+	respStruct := models.AddPermissionResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
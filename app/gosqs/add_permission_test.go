@@ -0,0 +1,162 @@
+package gosqs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddPermissionV1_success(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.AddPermissionRequest)
+		*v = models.AddPermissionRequest{
+			QueueUrl:      fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Label:         "send-and-receive",
+			AWSAccountIds: []string{"200020002000"},
+			Actions:       []string{"SendMessage", "ReceiveMessage"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := AddPermissionV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.AddPermissionResponse{Xmlns: models.BASE_XMLNS, Metadata: models.BASE_RESPONSE_METADATA}, response)
+
+	q := app.SyncQueues.Queues["unit-queue1"]
+	assert.JSONEq(t, fmt.Sprintf(`{
+		"Version": "2012-10-17",
+		"Id": "%[1]s/SQSDefaultPolicy",
+		"Statement": [{
+			"Sid": "send-and-receive",
+			"Effect": "Allow",
+			"Principal": {"AWS": "arn:aws:iam::200020002000:root"},
+			"Action": ["SQS:SendMessage", "SQS:ReceiveMessage"],
+			"Resource": "%[1]s"
+		}]
+	}`, q.Arn), q.Policy)
+	assert.True(t, q.Allows(app.PolicyRequest{Action: "sqs:SendMessage", AccountId: "200020002000"}))
+	assert.False(t, q.Allows(app.PolicyRequest{Action: "sqs:DeleteMessage", AccountId: "200020002000"}))
+}
+
+func TestAddPermissionV1_appends_to_existing_policy(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].Policy = fixtures.QueuePolicy
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.AddPermissionRequest)
+		*v = models.AddPermissionRequest{
+			QueueUrl:      fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Label:         "receive",
+			AWSAccountIds: []string{"200020002000"},
+			Actions:       []string{"ReceiveMessage"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := AddPermissionV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+
+	doc, err := app.ParsePolicy(app.SyncQueues.Queues["unit-queue1"].Policy)
+	assert.Nil(t, err)
+	assert.Equal(t, "2012-10-17", doc.Version)
+	assert.Equal(t, 2, len(doc.Statement))
+	assert.Equal(t, "receive", doc.Statement[1].Sid)
+}
+
+func TestAddPermissionV1_error_label_exists(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].Policy = `{"Statement":[{"Sid":"taken","Effect":"Allow","Principal":"*","Action":"sqs:*"}]}`
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.AddPermissionRequest)
+		*v = models.AddPermissionRequest{
+			QueueUrl:      fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Label:         "taken",
+			AWSAccountIds: []string{"200020002000"},
+			Actions:       []string{"SendMessage"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := AddPermissionV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, models.SqsErrors["PermissionLabelExists"].Response(), response.(models.ErrorResponse).Result)
+}
+
+func TestAddPermissionV1_error_invalid_action(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.AddPermissionRequest)
+		*v = models.AddPermissionRequest{
+			QueueUrl:      fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Label:         "delete",
+			AWSAccountIds: []string{"200020002000"},
+			Actions:       []string{"DeleteQueue"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := AddPermissionV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "", app.SyncQueues.Queues["unit-queue1"].Policy)
+}
+
+func TestAddPermissionV1_error_queue_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.AddPermissionRequest)
+		*v = models.AddPermissionRequest{
+			QueueUrl:      fmt.Sprintf("%s/%s", fixtures.BASE_URL, "not-a-queue"),
+			Label:         "send",
+			AWSAccountIds: []string{"200020002000"},
+			Actions:       []string{"SendMessage"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := AddPermissionV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,50 @@
+package gosqs
+
+import (
+	"net/http"
+
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func CancelMessageMoveTaskV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewCancelMessageMoveTaskRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - CancelMessageMoveTaskV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+
+	messageMoveTasks.Lock()
+	var task *messageMoveTask
+	for _, t := range messageMoveTasks.tasks {
+		if t.handle == requestBody.TaskHandle {
+			task = t
+			break
+		}
+	}
+	if task == nil {
+		messageMoveTasks.Unlock()
+		log.Errorf("Cancel Message Move Task: %s, task does not exist!!!", requestBody.TaskHandle)
+		return utils.CreateErrorResponseV1("ResourceNotFound", true)
+	}
+	if task.status != messageMoveTaskRunning {
+		messageMoveTasks.Unlock()
+		log.Errorf("Cancel Message Move Task: %s, task is %s", requestBody.TaskHandle, task.status)
+		return utils.CreateErrorResponseV1("MessageMoveTaskNotRunning", true)
+	}
+	task.status = messageMoveTaskCancelled
+	moved := task.moved
+	messageMoveTasks.Unlock()
+
+	log.Infof("Cancelled moving messages out of %s", task.sourceArn)
+	respStruct := models.CancelMessageMoveTaskResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Result:   models.CancelMessageMoveTaskResult{ApproximateNumberOfMessagesMoved: moved},
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -35,41 +36,18 @@ func ChangeMessageVisibilityV1(req *http.Request) (int, interfaces.AbstractRespo
 	receiptHandle := requestBody.ReceiptHandle
 
 	visibilityTimeout := requestBody.VisibilityTimeout
-	if visibilityTimeout > 43200 {
-		return utils.CreateErrorResponseV1("ValidationError", true)
+	if visibilityTimeout < 0 || visibilityTimeout > maxVisibilityTimeout {
+		return utils.CreateErrorResponseV1("InvalidVisibilityTimeout", true)
 	}
 
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
 	}
 
-	app.SyncQueues.Lock()
-	messageFound := false
-	for i := 0; i < len(app.SyncQueues.Queues[queueName].Messages); i++ {
-		queue := app.SyncQueues.Queues[queueName]
-		msgs := queue.Messages
-		if msgs[i].ReceiptHandle == receiptHandle {
-			timeout := app.SyncQueues.Queues[queueName].VisibilityTimeout
-			if visibilityTimeout == 0 {
-				msgs[i].ReceiptTime = time.Now().UTC()
-				msgs[i].ReceiptHandle = ""
-				msgs[i].VisibilityTimeout = time.Now().Add(time.Duration(timeout) * time.Second)
-				msgs[i].Retry++
-				if queue.MaxReceiveCount > 0 &&
-					queue.DeadLetterQueue != nil &&
-					msgs[i].Retry > queue.MaxReceiveCount {
-					queue.DeadLetterQueue.Messages = append(queue.DeadLetterQueue.Messages, msgs[i])
-					queue.Messages = append(queue.Messages[:i], queue.Messages[i+1:]...)
-					i++
-				}
-			} else {
-				msgs[i].VisibilityTimeout = time.Now().Add(time.Duration(visibilityTimeout) * time.Second)
-			}
-			messageFound = true
-			break
-		}
-	}
-	app.SyncQueues.Unlock()
+	_, unlock := queue.LockWithDeadLetterQueue()
+	messageFound := changeMessageVisibility(queue, receiptHandle, visibilityTimeout)
+	unlock()
 	if !messageFound {
 		return utils.CreateErrorResponseV1("MessageNotInFlight", true)
 	}
@@ -81,3 +59,24 @@ func ChangeMessageVisibilityV1(req *http.Request) (int, interfaces.AbstractRespo
 
 	return http.StatusOK, &respStruct
 }
+
+// changeMessageVisibility gives the in flight message with `receiptHandle` a new visibility timeout,
+// making it visible again straight away (or dead lettering it) when the timeout is 0.  It reports
+// whether the message was found.
+// NOTE: the caller must hold the locks from `queue.LockWithDeadLetterQueue`.
+func changeMessageVisibility(queue *app.Queue, receiptHandle string, visibilityTimeout int) bool {
+	msg, messageFound := queue.Messages.GetByReceiptHandle(receiptHandle)
+	if !messageFound {
+		return false
+	}
+	if visibilityTimeout != 0 {
+		msg.VisibilityTimeout = time.Now().Add(time.Duration(visibilityTimeout) * time.Second)
+		queue.Messages.Update(msg)
+		persistence.MessageUpdated(queue, msg)
+		return true
+	}
+
+	msg.VisibilityTimeout = time.Now().Add(time.Duration(queue.VisibilityTimeout) * time.Second)
+	returnMessage(queue, msg, time.Now())
+	return true
+}
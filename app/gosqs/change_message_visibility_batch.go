@@ -0,0 +1,81 @@
+package gosqs
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/common"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/gorilla/mux"
+	log "github.com/sirupsen/logrus"
+)
+
+func ChangeMessageVisibilityBatchV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewChangeMessageVisibilityBatchRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - ChangeMessageVisibilityBatchV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+
+	queueName := ""
+	if requestBody.QueueUrl == "" {
+		vars := mux.Vars(req)
+		queueName = vars["queueName"]
+	} else {
+		uriSegments := strings.Split(requestBody.QueueUrl, "/")
+		queueName = uriSegments[len(uriSegments)-1]
+	}
+
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
+		return utils.CreateErrorResponseV1("QueueNotFound", true)
+	}
+
+	ids := make([]string, 0, len(requestBody.Entries))
+	for _, v := range requestBody.Entries {
+		ids = append(ids, v.Id)
+	}
+	if errKey := common.ValidateBatchEntryIds(ids); errKey != "" {
+		return utils.CreateErrorResponseV1(errKey, true)
+	}
+
+	changedEntries := make([]models.ChangeMessageVisibilityBatchResultEntry, 0)
+	failedEntries := make([]models.BatchResultErrorEntry, 0)
+
+	_, unlock := queue.LockWithDeadLetterQueue()
+	for _, entry := range requestBody.Entries {
+		errKey := ""
+		if entry.VisibilityTimeout < 0 || entry.VisibilityTimeout > maxVisibilityTimeout {
+			errKey = "InvalidVisibilityTimeout"
+		} else if !changeMessageVisibility(queue, entry.ReceiptHandle, entry.VisibilityTimeout) {
+			errKey = "MessageNotInFlight"
+		}
+		if errKey != "" {
+			er := models.SqsErrors[errKey]
+			failedEntries = append(failedEntries, models.BatchResultErrorEntry{
+				Code:        er.Code,
+				Id:          entry.Id,
+				Message:     er.Message,
+				SenderFault: true,
+			})
+			continue
+		}
+		changedEntries = append(changedEntries, models.ChangeMessageVisibilityBatchResultEntry{Id: entry.Id})
+	}
+	unlock()
+
+	respStruct := models.ChangeMessageVisibilityBatchResponse{
+		Xmlns: models.BASE_XMLNS,
+		Result: models.ChangeMessageVisibilityBatchResult{
+			Successful: changedEntries,
+			Failed:     failedEntries,
+		},
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+
+	return http.StatusOK, respStruct
+}
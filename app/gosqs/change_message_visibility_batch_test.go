@@ -0,0 +1,100 @@
+package gosqs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func changeMessageVisibilityBatch(entries []models.ChangeMessageVisibilityBatchRequestEntry) (int, interfaces.AbstractResponseBody) {
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ChangeMessageVisibilityBatchRequest)
+		*v = models.ChangeMessageVisibilityBatchRequest{
+			Entries:  entries,
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "testing"),
+		}
+		return true
+	}
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	return ChangeMessageVisibilityBatchV1(r)
+}
+
+func TestChangeMessageVisibilityBatchV1_success_and_failed_entries(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	inFlightUntil := time.Now().Add(time.Minute)
+	q := &app.Queue{
+		Name:              "testing",
+		VisibilityTimeout: 30,
+		Messages: app.NewMessageStore(
+			app.Message{Uuid: "message-1", ReceiptHandle: "test1", VisibilityTimeout: inFlightUntil},
+			app.Message{Uuid: "message-2", ReceiptHandle: "test2", VisibilityTimeout: inFlightUntil},
+			app.Message{Uuid: "message-3", ReceiptHandle: "test3", VisibilityTimeout: inFlightUntil},
+		),
+	}
+	app.SyncQueues.Queues["testing"] = q
+
+	status, response := changeMessageVisibilityBatch([]models.ChangeMessageVisibilityBatchRequestEntry{
+		{Id: "extend", ReceiptHandle: "test1", VisibilityTimeout: 600},
+		{Id: "release", ReceiptHandle: "test2", VisibilityTimeout: 0},
+		{Id: "too-long", ReceiptHandle: "test3", VisibilityTimeout: 43201},
+		{Id: "unknown", ReceiptHandle: "garbage", VisibilityTimeout: 10},
+	})
+
+	assert.Equal(t, http.StatusOK, status)
+	result := response.(models.ChangeMessageVisibilityBatchResponse).Result
+	assert.Equal(t, []models.ChangeMessageVisibilityBatchResultEntry{{Id: "extend"}, {Id: "release"}}, result.Successful)
+	assert.Len(t, result.Failed, 2)
+	assert.Equal(t, "too-long", result.Failed[0].Id)
+	assert.Equal(t, models.SqsErrors["InvalidVisibilityTimeout"].Code, result.Failed[0].Code)
+	assert.Equal(t, "unknown", result.Failed[1].Id)
+	assert.Equal(t, models.SqsErrors["MessageNotInFlight"].Code, result.Failed[1].Code)
+
+	extended, _ := q.Messages.GetByReceiptHandle("test1")
+	assert.True(t, extended.VisibilityTimeout.After(time.Now().Add(9*time.Minute)))
+	_, found := q.Messages.GetByReceiptHandle("test2")
+	assert.False(t, found)
+	assert.Equal(t, 2, q.Messages.CountInFlight())
+}
+
+func TestChangeMessageVisibilityBatchV1_request_errors(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	status, _ := changeMessageVisibilityBatch([]models.ChangeMessageVisibilityBatchRequestEntry{{Id: "1", ReceiptHandle: "test1"}})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	app.SyncQueues.Queues["testing"] = &app.Queue{Name: "testing"}
+
+	status, response := changeMessageVisibilityBatch([]models.ChangeMessageVisibilityBatchRequestEntry{})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "AWS.SimpleQueueService.EmptyBatchRequest", response.GetResult().(models.ErrorResult).Code)
+
+	entries := []models.ChangeMessageVisibilityBatchRequestEntry{}
+	for i := 0; i < 11; i++ {
+		entries = append(entries, models.ChangeMessageVisibilityBatchRequestEntry{Id: fmt.Sprintf("%d", i)})
+	}
+	status, response = changeMessageVisibilityBatch(entries)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "AWS.SimpleQueueService.TooManyEntriesInBatchRequest", response.GetResult().(models.ErrorResult).Code)
+
+	status, response = changeMessageVisibilityBatch([]models.ChangeMessageVisibilityBatchRequestEntry{{Id: "1"}, {Id: "1"}})
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "AWS.SimpleQueueService.BatchEntryIdsNotDistinct", response.GetResult().(models.ErrorResult).Code)
+}
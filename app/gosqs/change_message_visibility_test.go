@@ -21,15 +21,15 @@ func TestChangeMessageVisibility_POST_SUCCESS(t *testing.T) {
 
 	q := &app.Queue{
 		Name: "testing",
-		Messages: []app.Message{{
+		Messages: app.NewMessageStore(app.Message{
 			MessageBody:   []byte("test1"),
 			ReceiptHandle: "123",
-		}},
+		}),
 	}
 	app.SyncQueues.Queues["testing"] = q
 
 	// The default value for the VisibilityTimeout is the zero value of time.Time
-	assert.Zero(t, q.Messages[0].VisibilityTimeout)
+	assert.Zero(t, q.Messages.All()[0].VisibilityTimeout)
 
 	_, r := test.GenerateRequestInfo("POST", "/", models.ChangeMessageVisibilityRequest{
 		QueueUrl:          "http://localhost:4100/queue/testing",
@@ -45,5 +45,45 @@ func TestChangeMessageVisibility_POST_SUCCESS(t *testing.T) {
 	// Given that the current time is relative between calling the endpoint and
 	// the time being set, we can't reliably assert an exact value. So assert
 	// that the time.Time value is no longer the default zero value.
-	assert.NotZero(t, q.Messages[0].VisibilityTimeout)
+	assert.NotZero(t, q.Messages.All()[0].VisibilityTimeout)
+}
+
+func TestChangeMessageVisibility_POST_zero_timeout_dead_letters_message(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	dlq := &app.Queue{Name: "testing-dlq"}
+	q := &app.Queue{
+		Name:            "testing",
+		Arn:             "arn:aws:sqs:us-east-1:100010001000:testing",
+		MaxReceiveCount: 1,
+		DeadLetterQueue: dlq,
+		Messages: app.NewMessageStore(app.Message{
+			Uuid:          "poison",
+			MessageBody:   []byte("test1"),
+			ReceiptHandle: "123",
+			Retry:         1,
+		}),
+	}
+	app.SyncQueues.Queues["testing"] = q
+	app.SyncQueues.Queues["testing-dlq"] = dlq
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.ChangeMessageVisibilityRequest{
+		QueueUrl:          "http://localhost:4100/queue/testing",
+		ReceiptHandle:     "123",
+		VisibilityTimeout: 0,
+	}, true)
+	status, _ := ChangeMessageVisibilityV1(r)
+	assert.Equal(t, http.StatusOK, status)
+
+	assert.Equal(t, 0, q.Messages.Len())
+	moved := dlq.Messages.All()
+	assert.Len(t, moved, 1)
+	assert.Equal(t, "poison", moved[0].Uuid)
+	assert.Equal(t, q.Arn, moved[0].DeadLetterQueueSourceArn)
+	assert.Equal(t, "", moved[0].ReceiptHandle)
+	assert.Zero(t, moved[0].VisibilityTimeout)
+	assert.Equal(t, 0, moved[0].Retry)
 }
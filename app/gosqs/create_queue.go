@@ -7,6 +7,7 @@ import (
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	log "github.com/sirupsen/logrus"
 )
@@ -19,6 +20,10 @@ func CreateQueueV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
 	}
 	queueName := requestBody.QueueName
+	if !validQueueName(queueName) {
+		log.Errorf("Invalid queue name: %s", queueName)
+		return utils.CreateErrorResponseV1("InvalidQueueName", true)
+	}
 
 	queueUrl := "http://" + app.CurrentEnvironment.Host + ":" + app.CurrentEnvironment.Port +
 		"/" + app.CurrentEnvironment.AccountID + "/" + queueName
@@ -28,22 +33,32 @@ func CreateQueueV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	}
 	queueArn := "arn:aws:sqs:" + app.CurrentEnvironment.Region + ":" + app.CurrentEnvironment.AccountID + ":" + queueName
 
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	if queue, ok := app.SyncQueues.Get(queueName); ok {
+		if !queueAttributesMatch(queue, requestBody.Attributes, requestBody.GivenAttributes) {
+			return utils.CreateErrorResponseV1("QueueAlreadyExists", true)
+		}
+	} else {
 		log.Println("Creating Queue:", queueName)
 		queue := &app.Queue{
-			Name:             queueName,
-			URL:              queueUrl,
-			Arn:              queueArn,
-			IsFIFO:           app.HasFIFOQueueName(queueName),
-			EnableDuplicates: app.CurrentEnvironment.EnableDuplicates,
-			Duplicates:       make(map[string]time.Time),
+			Name:       queueName,
+			URL:        queueUrl,
+			Arn:        queueArn,
+			IsFIFO:     app.HasFIFOQueueName(queueName),
+			Duplicates: make(map[string]app.Deduplicated),
+			Created:    time.Now(),
 		}
 		if err := setQueueAttributesV1(queue, requestBody.Attributes); err != nil {
 			return utils.CreateErrorResponseV1(err.Error(), true)
 		}
-		app.SyncQueues.Lock()
-		app.SyncQueues.Queues[queueName] = queue
-		app.SyncQueues.Unlock()
+		if err := tagQueue(queue, requestBody.Tags); err != nil {
+			return utils.CreateErrorResponseV1(err.Error(), true)
+		}
+		// Somebody else may have created it in the meantime, theirs wins.
+		if _, added := app.SyncQueues.Add(queue); added {
+			queue.RLock()
+			persistence.QueueUpdated(queue)
+			queue.RUnlock()
+		}
 	}
 
 	respStruct := models.CreateQueueResponse{
@@ -3,8 +3,8 @@ package gosqs
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/Admiral-Piett/goaws/app/test"
 
@@ -37,6 +37,7 @@ func TestCreateQueueV1_success(t *testing.T) {
 	assert.Equal(t, fixtures.CreateQueueResponse, response)
 
 	actualQueue := app.SyncQueues.Queues[fixtures.QueueName]
+	test.ClearQueueTimestamps(t, actualQueue)
 	assert.Equal(t, fixtures.FullyPopulatedQueue, actualQueue)
 }
 
@@ -83,10 +84,13 @@ func TestCreateQueueV1_success_with_redrive_policy(t *testing.T) {
 		ReceiveMessageWaitTimeSeconds: 4,
 		DelaySeconds:                  1,
 		MaximumMessageSize:            2,
-		MessageRetentionPeriod:        3,
+		MessageRetentionPeriod:        60,
 		DeadLetterQueue:               dlq,
 		MaxReceiveCount:               100,
-		Duplicates:                    make(map[string]time.Time),
+		Duplicates:                    make(map[string]app.Deduplicated),
+		Tags:                          map[string]string{"my": "tag"},
+		Policy:                        fixtures.FullyPopulatedQueue.Policy,
+		RedriveAllowPolicy:            fixtures.FullyPopulatedQueue.RedriveAllowPolicy,
 	}
 
 	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
@@ -96,6 +100,7 @@ func TestCreateQueueV1_success_with_redrive_policy(t *testing.T) {
 	assert.Equal(t, fixtures.CreateQueueResponse, response)
 
 	actualQueue := app.SyncQueues.Queues[fixtures.QueueName]
+	test.ClearQueueTimestamps(t, actualQueue)
 	assert.Equal(t, expectedQueue, actualQueue)
 }
 
@@ -163,7 +168,8 @@ func TestCreateQueueV1_success_with_no_request_attributes_falls_back_to_default(
 		DelaySeconds:                  0,
 		MaximumMessageSize:            0,
 		MessageRetentionPeriod:        0,
-		Duplicates:                    make(map[string]time.Time),
+		Duplicates:                    make(map[string]app.Deduplicated),
+		Tags:                          map[string]string{"my": "tag"},
 	}
 
 	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
@@ -173,6 +179,7 @@ func TestCreateQueueV1_success_with_no_request_attributes_falls_back_to_default(
 	assert.Equal(t, fixtures.CreateQueueResponse, response)
 
 	actualQueue := app.SyncQueues.Queues[fixtures.QueueName]
+	test.ClearQueueTimestamps(t, actualQueue)
 	assert.Equal(t, expectedQueue, actualQueue)
 }
 
@@ -253,3 +260,47 @@ func TestCreateQueueV1_invalid_dead_letter_queue_error(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, code)
 }
+
+func TestCreateQueueV1_invalid_queue_name(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	for _, queueName := range []string{"", "my queue", "my.queue", strings.Repeat("q", 81), strings.Repeat("q", 76) + ".fifo"} {
+		_, r := test.GenerateRequestInfo("POST", "/", models.CreateQueueRequest{QueueName: queueName}, true)
+		code, response := CreateQueueV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, models.SqsErrors["InvalidQueueName"].Response(), response.(models.ErrorResponse).Result)
+	}
+	assert.Empty(t, app.SyncQueues.Queues)
+}
+
+func TestCreateQueueV1_existing_queue_with_different_attributes(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: fixtures.QueueName, VisibilityTimeout: 30, DelaySeconds: 5}
+	app.SyncQueues.Queues[fixtures.QueueName] = q
+
+	// Leaving an attribute out is fine, only the given ones have to match.
+	_, r := test.GenerateRequestInfo("POST", "/", map[string]interface{}{
+		"QueueName":  fixtures.QueueName,
+		"Attributes": map[string]string{"VisibilityTimeout": "30"},
+	}, true)
+	code, _ := CreateQueueV1(r)
+	assert.Equal(t, http.StatusOK, code)
+
+	_, r = test.GenerateRequestInfo("POST", "/", map[string]interface{}{
+		"QueueName":  fixtures.QueueName,
+		"Attributes": map[string]string{"VisibilityTimeout": "30", "DelaySeconds": "10"},
+	}, true)
+	code, response := CreateQueueV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, models.SqsErrors["QueueAlreadyExists"].Response(), response.(models.ErrorResponse).Result)
+	assert.Equal(t, 5, q.DelaySeconds)
+}
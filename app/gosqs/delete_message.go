@@ -7,6 +7,7 @@ import (
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -37,25 +38,23 @@ func DeleteMessageV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	log.Info("Deleting Message, Queue:", queueName, ", ReceiptHandle:", receiptHandle)
 
 	// Find queue/message with the receipt handle and delete
-	app.SyncQueues.Lock()
-	defer app.SyncQueues.Unlock()
-	if _, ok := app.SyncQueues.Queues[queueName]; ok {
-		for i, msg := range app.SyncQueues.Queues[queueName].Messages {
-			if msg.ReceiptHandle == receiptHandle {
-				// Unlock messages for the group
-				log.Debugf("FIFO Queue %s unlocking group %s:", queueName, msg.GroupID)
-				app.SyncQueues.Queues[queueName].UnlockGroup(msg.GroupID)
-				//Delete message from Q
-				app.SyncQueues.Queues[queueName].Messages = append(app.SyncQueues.Queues[queueName].Messages[:i], app.SyncQueues.Queues[queueName].Messages[i+1:]...)
-				delete(app.SyncQueues.Queues[queueName].Duplicates, msg.DeduplicationID)
+	if queue, ok := app.SyncQueues.Get(queueName); ok {
+		queue.Lock()
+		defer queue.Unlock()
+		if msg, ok := queue.Messages.GetByReceiptHandle(receiptHandle); ok {
+			// Unlock messages for the group
+			log.Debugf("FIFO Queue %s unlocking group %s:", queueName, msg.GroupID)
+			queue.UnlockGroup(msg.GroupID)
+			//Delete message from Q
+			queue.Messages.Remove(msg.Uuid)
+			persistence.MessageDeleted(queue, msg.Uuid)
 
-				// Create, encode/xml and send response
-				respStruct := models.DeleteMessageResponse{
-					Xmlns:    models.BASE_XMLNS,
-					Metadata: models.BASE_RESPONSE_METADATA,
-				}
-				return 200, &respStruct
+			// Create, encode/xml and send response
+			respStruct := models.DeleteMessageResponse{
+				Xmlns:    models.BASE_XMLNS,
+				Metadata: models.BASE_RESPONSE_METADATA,
 			}
+			return 200, &respStruct
 		}
 		log.Warning("Receipt Handle not found")
 	} else {
@@ -5,8 +5,10 @@ import (
 	"strings"
 
 	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -33,28 +35,21 @@ func DeleteMessageBatchV1(req *http.Request) (int, interfaces.AbstractResponseBo
 		queueName = uriSegments[len(uriSegments)-1]
 	}
 
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
 	}
 
-	if len(requestBody.Entries) == 0 {
-		return utils.CreateErrorResponseV1("EmptyBatchRequest", true)
-	}
-
-	if len(requestBody.Entries) > 10 {
-		return utils.CreateErrorResponseV1("TooManyEntriesInBatchRequest", true)
-	}
-
-	ids := map[string]struct{}{}
+	ids := make([]string, 0, len(requestBody.Entries))
 	for _, v := range requestBody.Entries {
-		if _, ok := ids[v.Id]; ok {
-			return utils.CreateErrorResponseV1("BatchEntryIdsNotDistinct", true)
-		}
-		ids[v.Id] = struct{}{}
+		ids = append(ids, v.Id)
+	}
+	if errKey := common.ValidateBatchEntryIds(ids); errKey != "" {
+		return utils.CreateErrorResponseV1(errKey, true)
 	}
 
-	app.SyncQueues.Lock()
-	defer app.SyncQueues.Unlock()
+	queue.Lock()
+	defer queue.Unlock()
 
 	// create deleteMessageMap
 	deleteMessageMap := make(map[string]*deleteEntry)
@@ -67,26 +62,23 @@ func DeleteMessageBatchV1(req *http.Request) (int, interfaces.AbstractResponseBo
 	}
 
 	deletedEntries := make([]models.DeleteMessageBatchResultEntry, 0)
-	// create a slice to hold messages that are not deleted
-	remainingMessages := make([]app.Message, 0, len(app.SyncQueues.Queues[queueName].Messages))
 
 	// delete message from queue
-	for _, message := range app.SyncQueues.Queues[queueName].Messages {
-		if deleteEntry, found := deleteMessageMap[message.ReceiptHandle]; found {
-			// Unlock messages for the group
-			log.Debugf("FIFO Queue %s unlocking group %s:", queueName, message.GroupID)
-			app.SyncQueues.Queues[queueName].UnlockGroup(message.GroupID)
-			delete(app.SyncQueues.Queues[queueName].Duplicates, message.DeduplicationID)
-			deleteEntry.Deleted = true
-			deletedEntries = append(deletedEntries, models.DeleteMessageBatchResultEntry{Id: deleteEntry.Id})
-		} else {
-			remainingMessages = append(remainingMessages, message)
+	for _, entry := range requestBody.Entries {
+		deleteEntry := deleteMessageMap[entry.ReceiptHandle]
+		message, found := queue.Messages.GetByReceiptHandle(entry.ReceiptHandle)
+		if !found || deleteEntry.Deleted {
+			continue
 		}
+		// Unlock messages for the group
+		log.Debugf("FIFO Queue %s unlocking group %s:", queueName, message.GroupID)
+		queue.UnlockGroup(message.GroupID)
+		queue.Messages.Remove(message.Uuid)
+		persistence.MessageDeleted(queue, message.Uuid)
+		deleteEntry.Deleted = true
+		deletedEntries = append(deletedEntries, models.DeleteMessageBatchResultEntry{Id: deleteEntry.Id})
 	}
 
-	// Update the queue with the remaining mesages
-	app.SyncQueues.Queues[queueName].Messages = remainingMessages
-
 	// Process not found entries
 	notFoundEntries := make([]models.BatchResultErrorEntry, 0)
 	for _, deleteEntry := range deleteMessageMap {
@@ -25,20 +25,23 @@ func TestDeleteMessageBatchV1_success_all_message(t *testing.T) {
 
 	q := &app.Queue{
 		Name: "testing",
-		Messages: []app.Message{
-			{
+		Messages: app.NewMessageStore(
+			app.Message{
 				MessageBody:   []byte("test%20message%20body%201"),
+				Uuid:          "message-1",
 				ReceiptHandle: "test1",
 			},
-			{
+			app.Message{
 				MessageBody:   []byte("test%20message%20body%202"),
+				Uuid:          "message-2",
 				ReceiptHandle: "test2",
 			},
-			{
+			app.Message{
 				MessageBody:   []byte("test%20message%20body%203"),
+				Uuid:          "message-3",
 				ReceiptHandle: "test3",
 			},
-		},
+		),
 	}
 	app.SyncQueues.Queues["testing"] = q
 
@@ -76,7 +79,7 @@ func TestDeleteMessageBatchV1_success_all_message(t *testing.T) {
 	assert.Equal(t, "delete-test-2", deleteMessageBatchResponse.Result.Successful[1].Id)
 	assert.Equal(t, "delete-test-3", deleteMessageBatchResponse.Result.Successful[2].Id)
 	assert.Empty(t, deleteMessageBatchResponse.Result.Failed)
-	assert.Empty(t, app.SyncQueues.Queues["testing"].Messages)
+	assert.Equal(t, 0, app.SyncQueues.Queues["testing"].Messages.Len())
 }
 func TestDeleteMessageBatchV1_success_not_found_message(t *testing.T) {
 	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
@@ -88,16 +91,18 @@ func TestDeleteMessageBatchV1_success_not_found_message(t *testing.T) {
 
 	q := &app.Queue{
 		Name: "testing",
-		Messages: []app.Message{
-			{
+		Messages: app.NewMessageStore(
+			app.Message{
 				MessageBody:   []byte("test%20message%20body%201"),
+				Uuid:          "message-1",
 				ReceiptHandle: "test1",
 			},
-			{
+			app.Message{
 				MessageBody:   []byte("test%20message%20body%203"),
+				Uuid:          "message-3",
 				ReceiptHandle: "test3",
 			},
-		},
+		),
 	}
 	app.SyncQueues.Queues["testing"] = q
 
@@ -137,7 +142,7 @@ func TestDeleteMessageBatchV1_success_not_found_message(t *testing.T) {
 	assert.Equal(t, "delete-test-2", deleteMessageBatchResponse.Result.Failed[0].Id)
 	assert.Equal(t, "Message not found", deleteMessageBatchResponse.Result.Failed[0].Message)
 	assert.True(t, deleteMessageBatchResponse.Result.Failed[0].SenderFault)
-	assert.Empty(t, app.SyncQueues.Queues["testing"].Messages)
+	assert.Equal(t, 0, app.SyncQueues.Queues["testing"].Messages.Len())
 }
 
 func TestDeleteMessageBatchV1_error_not_found_queue(t *testing.T) {
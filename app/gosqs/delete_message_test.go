@@ -20,10 +20,11 @@ func TestDeleteMessage(t *testing.T) {
 
 	q := &app.Queue{
 		Name: "testing",
-		Messages: []app.Message{{
+		Messages: app.NewMessageStore(app.Message{
 			MessageBody:   []byte("test1"),
+			Uuid:          "message-1",
 			ReceiptHandle: "123",
-		}},
+		}),
 	}
 
 	app.SyncQueues.Queues["testing"] = q
@@ -35,5 +36,5 @@ func TestDeleteMessage(t *testing.T) {
 	status, _ := DeleteMessageV1(r)
 
 	assert.Equal(t, status, http.StatusOK)
-	assert.Empty(t, q.Messages)
+	assert.Equal(t, 0, q.Messages.Len())
 }
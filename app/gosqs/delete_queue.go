@@ -7,6 +7,7 @@ import (
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -26,9 +27,13 @@ func DeleteQueueV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 
 	log.Infof("Deleting Queue: %s", queueName)
 
-	app.SyncQueues.Lock()
-	delete(app.SyncQueues.Queues, queueName)
-	app.SyncQueues.Unlock()
+	if queue, ok := app.SyncQueues.Delete(queueName); ok {
+		queue.Lock()
+		persistence.QueueDeleted(queueName)
+		queue.Unlock()
+		// Let any long polling receivers know the queue is gone.
+		queue.Signal()
+	}
 
 	respStruct := models.DeleteQueueResponse{
 		Xmlns:    models.BASE_XMLNS,
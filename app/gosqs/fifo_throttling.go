@@ -0,0 +1,81 @@
+package gosqs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+)
+
+// fifoThrottle counts the send transactions to FIFO queues in one second windows, see app.EnvFifoThrottling.
+type fifoThrottle struct {
+	sync.Mutex
+	windows   map[string]*throttleWindow
+	lastPrune time.Time
+}
+
+type throttleWindow struct {
+	start time.Time
+	count int
+}
+
+var fifoThrottling = newFifoThrottle()
+
+func newFifoThrottle() *fifoThrottle {
+	return &fifoThrottle{windows: make(map[string]*throttleWindow)}
+}
+
+// allow counts a transaction against each of the keys, unless any of them is already at the limit in which
+// case nothing is counted.
+func (t *fifoThrottle) allow(keys []string, limit int, now time.Time) bool {
+	t.Lock()
+	defer t.Unlock()
+
+	// Forget the windows nobody sent to lately.
+	if now.Sub(t.lastPrune) >= time.Second {
+		for key, w := range t.windows {
+			if now.Sub(w.start) >= time.Second {
+				delete(t.windows, key)
+			}
+		}
+		t.lastPrune = now
+	}
+
+	for _, key := range keys {
+		w, ok := t.windows[key]
+		if ok && now.Sub(w.start) < time.Second && w.count >= limit {
+			return false
+		}
+	}
+	for _, key := range keys {
+		w, ok := t.windows[key]
+		if !ok || now.Sub(w.start) >= time.Second {
+			t.windows[key] = &throttleWindow{start: now, count: 1}
+			continue
+		}
+		w.count++
+	}
+	return true
+}
+
+// fifoSendThrottled tells if a send of messages of the given groups to a FIFO queue is beyond its throughput.
+// The limit is for the whole queue, or for each message group with a FifoThroughputLimit of perMessageGroupId.
+func fifoSendThrottled(queueName string, fifoThroughputLimit string, groupIds ...string) bool {
+	if !app.CurrentEnvironment.FifoThrottling.Enabled {
+		return false
+	}
+
+	keys := []string{queueName}
+	if fifoThroughputLimit == app.FifoThroughputLimitPerMessageGroupId {
+		keys = []string{}
+		seen := map[string]struct{}{}
+		for _, groupId := range groupIds {
+			if _, ok := seen[groupId]; ok {
+				continue
+			}
+			seen[groupId] = struct{}{}
+			keys = append(keys, queueName+"/"+groupId)
+		}
+	}
+	return !fifoThrottling.allow(keys, app.CurrentEnvironment.FifoThrottling.TransactionsPerSecond, time.Now())
+}
@@ -0,0 +1,44 @@
+package gosqs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFifoThrottle_allow_limits_each_window(t *testing.T) {
+	throttle := newFifoThrottle()
+	now := time.Now()
+
+	assert.True(t, throttle.allow([]string{"a"}, 2, now))
+	assert.True(t, throttle.allow([]string{"a"}, 2, now.Add(100*time.Millisecond)))
+	assert.False(t, throttle.allow([]string{"a"}, 2, now.Add(200*time.Millisecond)))
+	assert.True(t, throttle.allow([]string{"b"}, 2, now.Add(200*time.Millisecond)))
+
+	// Nothing is counted when one of the keys is at the limit.
+	assert.False(t, throttle.allow([]string{"b", "a"}, 2, now.Add(300*time.Millisecond)))
+	assert.Equal(t, 1, throttle.windows["b"].count)
+
+	assert.True(t, throttle.allow([]string{"a"}, 2, now.Add(time.Second)))
+}
+
+func TestFifoSendThrottled(t *testing.T) {
+	defer func() {
+		app.CurrentEnvironment.FifoThrottling = app.EnvFifoThrottling{}
+		fifoThrottling = newFifoThrottle()
+	}()
+
+	app.CurrentEnvironment.FifoThrottling = app.EnvFifoThrottling{Enabled: true, TransactionsPerSecond: 1}
+
+	assert.False(t, fifoSendThrottled("queue.fifo", "", "group-1"))
+	assert.True(t, fifoSendThrottled("queue.fifo", "", "group-2"))
+
+	assert.False(t, fifoSendThrottled("groups.fifo", app.FifoThroughputLimitPerMessageGroupId, "group-1", "group-1"))
+	assert.False(t, fifoSendThrottled("groups.fifo", app.FifoThroughputLimitPerMessageGroupId, "group-2"))
+	assert.True(t, fifoSendThrottled("groups.fifo", app.FifoThroughputLimitPerMessageGroupId, "group-1"))
+
+	app.CurrentEnvironment.FifoThrottling.Enabled = false
+	assert.False(t, fifoSendThrottled("queue.fifo", "", "group-1"))
+}
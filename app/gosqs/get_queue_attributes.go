@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/models"
@@ -60,13 +61,13 @@ func GetQueueAttributesV1(req *http.Request) (int, interfaces.AbstractResponseBo
 	log.Infof("Get Queue QueueAttributes: %s", queueName)
 	queueAttributes := make([]models.Attribute, 0, 0)
 
-	app.SyncQueues.RLock()
-	defer app.SyncQueues.RUnlock()
-	queue, ok := app.SyncQueues.Queues[queueName]
+	queue, ok := app.SyncQueues.Get(queueName)
 	if !ok {
 		log.Errorf("Get Queue URL: %s queue does not exist!!!", queueName)
 		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
 	}
+	queue.RLock()
+	defer queue.RUnlock()
 
 	if _, ok := includedAttributes["DelaySeconds"]; ok {
 		attr := models.Attribute{Name: "DelaySeconds", Value: strconv.Itoa(queue.DelaySeconds)}
@@ -89,39 +90,64 @@ func GetQueueAttributesV1(req *http.Request) (int, interfaces.AbstractResponseBo
 		queueAttributes = append(queueAttributes, attr)
 	}
 	if _, ok := includedAttributes["ApproximateNumberOfMessages"]; ok {
-		attr := models.Attribute{Name: "ApproximateNumberOfMessages", Value: strconv.Itoa(len(queue.Messages))}
+		attr := models.Attribute{Name: "ApproximateNumberOfMessages", Value: strconv.Itoa(queue.Messages.Len())}
 		queueAttributes = append(queueAttributes, attr)
 	}
 	// TODO - implement
 	//if _, ok := includedAttributes["ApproximateNumberOfMessagesDelayed"]; ok {
-	//	attr := models.Attribute{Name: "ApproximateNumberOfMessagesDelayed", Value: strconv.Itoa(len(queue.Messages))}
+	//	attr := models.Attribute{Name: "ApproximateNumberOfMessagesDelayed", Value: strconv.Itoa(queue.Messages.Len())}
 	//	queueAttributes = append(queueAttributes, attr)
 	//}
 	if _, ok := includedAttributes["ApproximateNumberOfMessagesNotVisible"]; ok {
-		attr := models.Attribute{Name: "ApproximateNumberOfMessagesNotVisible", Value: strconv.Itoa(numberOfHiddenMessagesInQueue(*queue))}
+		attr := models.Attribute{Name: "ApproximateNumberOfMessagesNotVisible", Value: strconv.Itoa(numberOfHiddenMessagesInQueue(queue))}
 		queueAttributes = append(queueAttributes, attr)
 	}
 	if _, ok := includedAttributes["CreatedTimestamp"]; ok {
-		attr := models.Attribute{Name: "CreatedTimestamp", Value: "0000000000"}
+		attr := models.Attribute{Name: "CreatedTimestamp", Value: unixTimestamp(queue.Created)}
 		queueAttributes = append(queueAttributes, attr)
 	}
 	if _, ok := includedAttributes["LastModifiedTimestamp"]; ok {
-		attr := models.Attribute{Name: "LastModifiedTimestamp", Value: "0000000000"}
+		attr := models.Attribute{Name: "LastModifiedTimestamp", Value: unixTimestamp(queue.LastModified)}
 		queueAttributes = append(queueAttributes, attr)
 	}
 	if _, ok := includedAttributes["QueueArn"]; ok {
 		attr := models.Attribute{Name: "QueueArn", Value: queue.Arn}
 		queueAttributes = append(queueAttributes, attr)
 	}
-	// TODO - implement
-	//if _, ok := includedAttributes["Policy"]; ok {
-	//	attr := models.Attribute{Name: "Policy", Value: ""}
-	//	queueAttributes = append(queueAttributes, attr)
-	//}
-	//if _, ok := includedAttributes["RedriveAllowPolicy"]; ok {
-	//	attr := models.Attribute{Name: "RedriveAllowPolicy", Value: ""}
-	//	queueAttributes = append(queueAttributes, attr)
-	//}
+	// Like AWS, only a queue with a policy has the attribute.
+	if _, ok := includedAttributes["Policy"]; ok && queue.Policy != "" {
+		attr := models.Attribute{Name: "Policy", Value: queue.Policy}
+		queueAttributes = append(queueAttributes, attr)
+	}
+	if _, ok := includedAttributes["RedriveAllowPolicy"]; ok && queue.RedriveAllowPolicy != "" {
+		attr := models.Attribute{Name: "RedriveAllowPolicy", Value: queue.RedriveAllowPolicy}
+		queueAttributes = append(queueAttributes, attr)
+	}
+	// The FIFO attributes only exist on FIFO queues.
+	if _, ok := includedAttributes["FifoQueue"]; ok && queue.IsFIFO {
+		attr := models.Attribute{Name: "FifoQueue", Value: "true"}
+		queueAttributes = append(queueAttributes, attr)
+	}
+	if _, ok := includedAttributes["ContentBasedDeduplication"]; ok && queue.IsFIFO {
+		attr := models.Attribute{Name: "ContentBasedDeduplication", Value: strconv.FormatBool(queue.ContentBasedDeduplication)}
+		queueAttributes = append(queueAttributes, attr)
+	}
+	if _, ok := includedAttributes["DeduplicationScope"]; ok && queue.IsFIFO {
+		value := queue.DeduplicationScope
+		if value == "" {
+			value = app.DeduplicationScopeQueue
+		}
+		attr := models.Attribute{Name: "DeduplicationScope", Value: value}
+		queueAttributes = append(queueAttributes, attr)
+	}
+	if _, ok := includedAttributes["FifoThroughputLimit"]; ok && queue.IsFIFO {
+		value := queue.FifoThroughputLimit
+		if value == "" {
+			value = app.FifoThroughputLimitPerQueue
+		}
+		attr := models.Attribute{Name: "FifoThroughputLimit", Value: value}
+		queueAttributes = append(queueAttributes, attr)
+	}
 	if _, ok := includedAttributes["RedrivePolicy"]; ok && queue.DeadLetterQueue != nil {
 		attr := models.Attribute{Name: "RedrivePolicy", Value: fmt.Sprintf(`{"maxReceiveCount":"%d", "deadLetterTargetArn":"%s"}`, queue.MaxReceiveCount, queue.DeadLetterQueue.Arn)}
 		queueAttributes = append(queueAttributes, attr)
@@ -134,3 +160,12 @@ func GetQueueAttributesV1(req *http.Request) (int, interfaces.AbstractResponseBo
 	}
 	return http.StatusOK, respStruct
 }
+
+// unixTimestamp is the time in seconds since the epoch, the way the queue timestamps are given out.  A queue
+// that never had one set, e.g. made up by hand, gets 0.
+func unixTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.Unix(), 10)
+}
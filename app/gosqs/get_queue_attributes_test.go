@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/Admiral-Piett/goaws/app/test"
 
@@ -11,6 +12,7 @@ import (
 
 	"github.com/Admiral-Piett/goaws/app/conf"
 
+	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/fixtures"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
@@ -33,6 +35,7 @@ func TestGetQueueAttributesV1_success_all(t *testing.T) {
 
 	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
 	code, response := GetQueueAttributesV1(r)
+	blankQueueTimestamps(t, response)
 
 	assert.Equal(t, http.StatusOK, code)
 	assert.Equal(t, fixtures.GetQueueAttributesResponse, response)
@@ -55,6 +58,7 @@ func TestGetQueueAttributesV1_success_no_request_attrs_returns_all(t *testing.T)
 
 	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
 	code, response := GetQueueAttributesV1(r)
+	blankQueueTimestamps(t, response)
 
 	assert.Equal(t, http.StatusOK, code)
 	assert.Equal(t, fixtures.GetQueueAttributesResponse, response)
@@ -78,6 +82,7 @@ func TestGetQueueAttributesV1_success_all_with_redrive_queue(t *testing.T) {
 
 	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
 	code, response := GetQueueAttributesV1(r)
+	blankQueueTimestamps(t, response)
 
 	dupe, _ := copystructure.Copy(fixtures.GetQueueAttributesResponse)
 	expectedResponse, _ := dupe.(models.GetQueueAttributesResponse)
@@ -178,3 +183,104 @@ func TestGetQueueAttributesV1_missing_queue_returns_error(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, code)
 }
+
+func TestGetQueueAttributesV1_success_fifo_queue(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+	}()
+
+	app.SyncQueues.Queues["unit-queue1.fifo"] = &app.Queue{Name: "unit-queue1.fifo", IsFIFO: true, ContentBasedDeduplication: true}
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.GetQueueAttributesRequest{
+		QueueUrl:       fmt.Sprintf("%s/unit-queue1.fifo", fixtures.BASE_URL),
+		AttributeNames: []string{"FifoQueue", "ContentBasedDeduplication"},
+	}, true)
+	code, response := GetQueueAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []models.Attribute{
+		{Name: "FifoQueue", Value: "true"},
+		{Name: "ContentBasedDeduplication", Value: "true"},
+	}, response.(models.GetQueueAttributesResponse).Result.Attrs)
+}
+
+func TestGetQueueAttributesV1_success_timestamps(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+	}()
+
+	created := time.Unix(1700000000, 0)
+	app.SyncQueues.Queues["unit-queue1"] = &app.Queue{Name: "unit-queue1", Created: created, LastModified: created.Add(time.Hour)}
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.GetQueueAttributesRequest{
+		QueueUrl:       fmt.Sprintf("%s/unit-queue1", fixtures.BASE_URL),
+		AttributeNames: []string{"CreatedTimestamp", "LastModifiedTimestamp"},
+	}, true)
+	code, response := GetQueueAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []models.Attribute{
+		{Name: "CreatedTimestamp", Value: "1700000000"},
+		{Name: "LastModifiedTimestamp", Value: "1700003600"},
+	}, response.(models.GetQueueAttributesResponse).Result.Attrs)
+}
+
+// blankQueueTimestamps checks the queue timestamps of the response are from just now, then blanks them so that
+// the response can be compared with the fixtures.
+func blankQueueTimestamps(t *testing.T, response interfaces.AbstractResponseBody) {
+	attrs := response.(models.GetQueueAttributesResponse).Result.Attrs
+	for i, attr := range attrs {
+		if attr.Name == "CreatedTimestamp" || attr.Name == "LastModifiedTimestamp" {
+			assert.True(t, test.RecentTimestamp(attr.Value), attr.Name)
+			attrs[i].Value = ""
+		}
+	}
+}
+
+func TestGetQueueAttributesV1_success_policy(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+	}()
+
+	app.SyncQueues.Queues["unit-queue1"] = &app.Queue{Name: "unit-queue1", Policy: fixtures.QueuePolicy}
+	app.SyncQueues.Queues["unit-queue2"] = &app.Queue{Name: "unit-queue2"}
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.GetQueueAttributesRequest{
+		QueueUrl:       fmt.Sprintf("%s/unit-queue1", fixtures.BASE_URL),
+		AttributeNames: []string{"Policy"},
+	}, true)
+	code, response := GetQueueAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []models.Attribute{
+		{Name: "Policy", Value: fixtures.QueuePolicy},
+	}, response.(models.GetQueueAttributesResponse).Result.Attrs)
+
+	_, r = test.GenerateRequestInfo("POST", "/", models.GetQueueAttributesRequest{
+		QueueUrl:       fmt.Sprintf("%s/unit-queue2", fixtures.BASE_URL),
+		AttributeNames: []string{"Policy"},
+	}, true)
+	code, response = GetQueueAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, response.(models.GetQueueAttributesResponse).Result.Attrs)
+}
+
+func TestGetQueueAttributesV1_success_redrive_allow_policy(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+	}()
+
+	app.SyncQueues.Queues["unit-queue1"] = &app.Queue{Name: "unit-queue1", RedriveAllowPolicy: fixtures.QueueRedriveAllowPolicy}
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.GetQueueAttributesRequest{
+		QueueUrl:       fmt.Sprintf("%s/unit-queue1", fixtures.BASE_URL),
+		AttributeNames: []string{"All"},
+	}, true)
+	code, response := GetQueueAttributesV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, response.(models.GetQueueAttributesResponse).Result.Attrs,
+		models.Attribute{Name: "RedriveAllowPolicy", Value: fixtures.QueueRedriveAllowPolicy})
+}
@@ -19,12 +19,12 @@ func GetQueueUrlV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	}
 
 	queueName := requestBody.QueueName
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
 		log.Error("Get Queue URL:", queueName, ", queue does not exist!!!")
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
 	}
 
-	queue := app.SyncQueues.Queues[queueName]
 	log.Debug("Get Queue URL:", queue.Name)
 
 	result := models.GetQueueUrlResult{QueueUrl: queue.URL}
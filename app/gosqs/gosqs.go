@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 
 	log "github.com/sirupsen/logrus"
 
@@ -22,41 +23,10 @@ func PeriodicTasks(d time.Duration, quit <-chan struct{}) {
 	for {
 		select {
 		case <-ticker.C:
-			app.SyncQueues.Lock()
-			for j := range app.SyncQueues.Queues {
-				queue := app.SyncQueues.Queues[j]
-
-				log.Debugf("Queue [%s] length [%d]", queue.Name, len(queue.Messages))
-				for i := 0; i < len(queue.Messages); i++ {
-					msg := &queue.Messages[i]
-
-					// Reset deduplication period
-					for dedupId, startTime := range queue.Duplicates {
-						if time.Now().After(startTime.Add(app.DeduplicationPeriod)) {
-							log.Debugf("deduplication period for message with deduplicationId [%s] expired", dedupId)
-							delete(queue.Duplicates, dedupId)
-						}
-					}
-
-					if msg.ReceiptHandle != "" {
-						if msg.VisibilityTimeout.Before(time.Now()) {
-							log.Debugf("Making message visible again %s", msg.ReceiptHandle)
-							queue.UnlockGroup(msg.GroupID)
-							msg.ReceiptHandle = ""
-							msg.ReceiptTime = time.Now().UTC()
-							msg.Retry++
-							if queue.MaxReceiveCount > 0 &&
-								queue.DeadLetterQueue != nil &&
-								msg.Retry > queue.MaxReceiveCount {
-								queue.DeadLetterQueue.Messages = append(queue.DeadLetterQueue.Messages, *msg)
-								queue.Messages = append(queue.Messages[:i], queue.Messages[i+1:]...)
-								i++
-							}
-						}
-					}
-				}
+			for _, queue := range app.SyncQueues.All() {
+				maintainQueue(queue, time.Now())
 			}
-			app.SyncQueues.Unlock()
+			advanceMessageMoveTasks(d)
 		case <-quit:
 			ticker.Stop()
 			return
@@ -64,14 +34,108 @@ func PeriodicTasks(d time.Duration, quit <-chan struct{}) {
 	}
 }
 
-func numberOfHiddenMessagesInQueue(queue app.Queue) int {
-	num := 0
-	for _, m := range queue.Messages {
-		if m.ReceiptHandle != "" || m.DelaySecs > 0 && time.Now().Before(m.SentTime.Add(time.Duration(m.DelaySecs)*time.Second)) {
-			num++
+// maintainQueue does the housekeeping of `queue` that is due at `now`: it drops the messages and
+// deduplication IDs that expired, and makes the in flight messages whose visibility timeout ran out visible
+// again - or dead letters them.
+// NOTE: this takes the locks of `queue` and its dead letter queue itself.
+func maintainQueue(queue *app.Queue, now time.Time) {
+	_, unlock := queue.LockWithDeadLetterQueue()
+	defer unlock()
+
+	log.Debugf("Queue [%s] length [%d]", queue.Name, queue.Messages.Len())
+	expireMessages(queue, now)
+	queue.ExpireReceiveAttempts(now)
+
+	// Reset deduplication period
+	for dedupId, original := range queue.Duplicates {
+		if original.Expired(now) {
+			log.Debugf("deduplication period for message with deduplicationId [%s] expired", dedupId)
+			delete(queue.Duplicates, dedupId)
 		}
 	}
-	return num
+
+	for _, msg := range queue.Messages.ExpiredInFlight(now) {
+		log.Debugf("Making message visible again %s", msg.ReceiptHandle)
+		returnMessage(queue, msg, now)
+	}
+}
+
+// returnMessage puts the in flight `msg` back in `queue`, visible again, once its receive has ended without
+// it being deleted.  When that was the last receive the redrive policy of `queue` allows, it's moved to the
+// dead letter queue instead.
+// NOTE: the caller must hold the locks from `queue.LockWithDeadLetterQueue`.
+func returnMessage(queue *app.Queue, msg *app.Message, now time.Time) {
+	queue.UnlockGroup(msg.GroupID)
+	msg.ReceiptHandle = ""
+	msg.ReceiptTime = now.UTC()
+	msg.Retry++
+	if queue.DeadLetterQueue != nil && queue.MaxReceiveCount > 0 && msg.Retry > queue.MaxReceiveCount {
+		moveToDeadLetterQueue(queue, msg.Uuid, now)
+		return
+	}
+	queue.Messages.Update(msg)
+	persistence.MessageUpdated(queue, msg)
+	queue.Signal()
+}
+
+// moveToDeadLetterQueue moves a message out of `queue` into its dead letter queue.  It keeps its ID, body and
+// attributes, and records where it came from to be redriven back there, but is sent to the dead letter
+// queue afresh: visible straight away and never received from it.
+// NOTE: the caller must hold the locks from `queue.LockWithDeadLetterQueue`.
+func moveToDeadLetterQueue(queue *app.Queue, messageId string, now time.Time) {
+	dlq := queue.DeadLetterQueue
+	msg, ok := queue.Messages.Remove(messageId)
+	if !ok {
+		return
+	}
+	persistence.MessageDeleted(queue, messageId)
+
+	msg.DeadLetterQueueSourceArn = queue.Arn
+	resetDelivery(&msg, now)
+	persistence.MessageUpdated(dlq, dlq.Messages.Add(msg))
+	log.Debugf("Moved message [%s] from queue [%s] to its dead letter queue [%s]", messageId, queue.Name, dlq.Name)
+	dlq.Signal()
+}
+
+// resetDelivery makes `msg` look freshly sent: visible straight away, out of flight and with no retries.
+func resetDelivery(msg *app.Message, now time.Time) {
+	msg.ReceiptHandle = ""
+	msg.ReceiptTime = time.Time{}
+	msg.VisibilityTimeout = time.Time{}
+	msg.Retry = 0
+	msg.SentTime = now
+	msg.DelaySecs = 0
+}
+
+// expireMessages drops every message that has been in `queue` for longer than its MessageRetentionPeriod,
+// whether it is in flight or not.
+// NOTE: the caller must hold the lock of `queue`.
+func expireMessages(queue *app.Queue, now time.Time) {
+	if queue.MessageRetentionPeriod <= 0 {
+		return
+	}
+	cutoff := now.Add(-time.Duration(queue.MessageRetentionPeriod) * time.Second)
+	for _, msg := range queue.Messages.RemoveSentBefore(cutoff) {
+		log.Debugf("Retention period for message [%s] in queue [%s] expired", msg.Uuid, queue.Name)
+		if msg.ReceiptHandle != "" {
+			queue.UnlockGroup(msg.GroupID)
+		}
+		persistence.MessageDeleted(queue, msg.Uuid)
+	}
+}
+
+// nextMessageReveal reports whether `queue` has a message that can be received right now and, when it
+// doesn't, the time its earliest delayed message becomes visible (zero if there are none).
+// NOTE: the caller must hold the lock of `queue`.
+func nextMessageReveal(queue *app.Queue, now time.Time) (bool, time.Time) {
+	if queue.Messages.HasVisible(now) {
+		return true, time.Time{}
+	}
+	return false, queue.Messages.NextReveal()
+}
+
+func numberOfHiddenMessagesInQueue(queue *app.Queue) int {
+	return queue.Messages.CountInFlight() + queue.Messages.CountDelayed(time.Now())
 }
 
 func getQueueFromPath(formVal string, theUrl string) string {
@@ -1,6 +1,9 @@
 package gosqs
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -10,6 +13,7 @@ import (
 
 	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	"github.com/stretchr/testify/assert"
 )
@@ -222,17 +226,13 @@ func TestRequeueing_ResetVisibilityTimeout(t *testing.T) {
 }
 
 func TestDeadLetterQueue(t *testing.T) {
-	done := make(chan struct{}, 0)
-	go PeriodicTasks(1*time.Second, done)
-
 	// create a queue
 	req, err := http.NewRequest("POST", "/", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 	deadLetterQueue := &app.Queue{
-		Name:     "failed-messages",
-		Messages: []app.Message{},
+		Name: "failed-messages",
 	}
 	app.SyncQueues.Lock()
 	app.SyncQueues.Queues["failed-messages"] = deadLetterQueue
@@ -284,7 +284,8 @@ func TestDeadLetterQueue(t *testing.T) {
 	status, _ = ReceiveMessageV1(req)
 	assert.Equal(t, status, http.StatusOK)
 
-	time.Sleep(2 * time.Second)
+	sourceQueue, _ := app.SyncQueues.Get("testing-deadletter")
+	maintainQueue(sourceQueue, time.Now().Add(2*time.Second))
 
 	// receive the message one more time
 	req, err = http.NewRequest("POST", "/", nil)
@@ -296,7 +297,7 @@ func TestDeadLetterQueue(t *testing.T) {
 
 	status, _ = ReceiveMessageV1(req)
 	assert.Equal(t, status, http.StatusOK)
-	time.Sleep(2 * time.Second)
+	maintainQueue(sourceQueue, time.Now().Add(2*time.Second))
 
 	// another receive attempt
 	req, err = http.NewRequest("POST", "/", nil)
@@ -308,9 +309,10 @@ func TestDeadLetterQueue(t *testing.T) {
 
 	status, _ = ReceiveMessageV1(req)
 	assert.Equal(t, status, http.StatusOK)
-	if len(deadLetterQueue.Messages) == 0 {
+	if deadLetterQueue.Messages.Len() == 0 {
 		t.Fatal("expected a message")
 	}
+	assert.Equal(t, sourceQueue.Arn, deadLetterQueue.Messages.All()[0].DeadLetterQueueSourceArn)
 }
 
 func TestSendingAndReceivingFromFIFOQueueReturnsSameMessageOnError(t *testing.T) {
@@ -345,6 +347,7 @@ func TestSendingAndReceivingFromFIFOQueueReturnsSameMessageOnError(t *testing.T)
 	form.Add("QueueUrl", "http://localhost:4100/queue/requeue-reset.fifo")
 	form.Add("MessageBody", "1")
 	form.Add("MessageGroupId", "GROUP-X")
+	form.Add("MessageDeduplicationId", "1")
 	form.Add("Version", "2012-11-05")
 	req.PostForm = form
 
@@ -365,6 +368,7 @@ func TestSendingAndReceivingFromFIFOQueueReturnsSameMessageOnError(t *testing.T)
 	form.Add("QueueUrl", "http://localhost:4100/queue/requeue-reset.fifo")
 	form.Add("MessageBody", "2")
 	form.Add("MessageGroupId", "GROUP-X")
+	form.Add("MessageDeduplicationId", "2")
 	form.Add("Version", "2012-11-05")
 	req.PostForm = form
 
@@ -414,7 +418,7 @@ func TestSendingAndReceivingFromFIFOQueueReturnsSameMessageOnError(t *testing.T)
 		t.Fatal("there should be only 1 group locked")
 	}
 
-	if app.SyncQueues.Queues["requeue-reset.fifo"].FIFOMessages["GROUP-X"] != 0 {
+	if app.SyncQueues.Queues["requeue-reset.fifo"].FIFOMessages["GROUP-X"] != 1 {
 		t.Fatal("there should be GROUP-X locked")
 	}
 
@@ -434,7 +438,7 @@ func TestSendingAndReceivingFromFIFOQueueReturnsSameMessageOnError(t *testing.T)
 	status, _ = DeleteMessageV1(req)
 	assert.Equal(t, status, http.StatusOK)
 
-	if len(app.SyncQueues.Queues["requeue-reset.fifo"].Messages) != 1 {
+	if app.SyncQueues.Queues["requeue-reset.fifo"].Messages.Len() != 1 {
 		t.Fatal("there should be only 1 message in queue")
 	}
 
@@ -471,6 +475,7 @@ func TestSendingAndReceivingFromFIFOQueueReturnsSameMessageOnError(t *testing.T)
 func TestSendMessage_POST_DuplicatationNotAppliedToStandardQueue(t *testing.T) {
 	done := make(chan struct{}, 0)
 	go PeriodicTasks(1*time.Second, done)
+	defer close(done)
 
 	// create a queue
 	req, err := http.NewRequest("POST", "/", nil)
@@ -508,7 +513,7 @@ func TestSendMessage_POST_DuplicatationNotAppliedToStandardQueue(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got \n%v want %v",
 			status, http.StatusOK)
 	}
-	if len(app.SyncQueues.Queues["stantdard-testing"].Messages) == 0 {
+	if app.SyncQueues.Queues["stantdard-testing"].Messages.Len() == 0 {
 		t.Fatal("there should be 1 message in queue")
 	}
 
@@ -527,14 +532,15 @@ func TestSendMessage_POST_DuplicatationNotAppliedToStandardQueue(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got \n%v want %v",
 			status, http.StatusOK)
 	}
-	if len(app.SyncQueues.Queues["stantdard-testing"].Messages) == 1 {
+	if app.SyncQueues.Queues["stantdard-testing"].Messages.Len() == 1 {
 		t.Fatal("there should be 2 messages in queue")
 	}
 }
 
-func TestSendMessage_POST_DuplicatationDisabledOnFifoQueue(t *testing.T) {
+func TestSendMessage_POST_ContentBasedDeduplicationOnFifoQueue(t *testing.T) {
 	done := make(chan struct{}, 0)
 	go PeriodicTasks(1*time.Second, done)
+	defer close(done)
 
 	// create a queue
 	req, err := http.NewRequest("POST", "/", nil)
@@ -544,7 +550,9 @@ func TestSendMessage_POST_DuplicatationDisabledOnFifoQueue(t *testing.T) {
 
 	form := url.Values{}
 	form.Add("Action", "CreateQueue")
-	form.Add("QueueName", "no-dup-testing.fifo")
+	form.Add("QueueName", "content-dup-testing.fifo")
+	form.Add("Attribute.1.Name", "ContentBasedDeduplication")
+	form.Add("Attribute.1.Value", "true")
 	form.Add("Version", "2012-11-05")
 	req.PostForm = form
 
@@ -552,53 +560,36 @@ func TestSendMessage_POST_DuplicatationDisabledOnFifoQueue(t *testing.T) {
 
 	assert.Equal(t, status, http.StatusOK)
 
-	req, err = http.NewRequest("POST", "/", nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	form = url.Values{}
-	form.Add("Action", "SendMessage")
-	form.Add("QueueUrl", "http://localhost:4100/queue/no-dup-testing.fifo")
-	form.Add("MessageBody", "Test1")
-	form.Add("MessageDeduplicationId", "123")
-	form.Add("Version", "2012-11-05")
-	req.PostForm = form
+	for _, body := range []string{"Test1", "Test1", "Test2"} {
+		req, err = http.NewRequest("POST", "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
 
-	status, _ = SendMessageV1(req)
+		form = url.Values{}
+		form.Add("Action", "SendMessage")
+		form.Add("QueueUrl", "http://localhost:4100/queue/content-dup-testing.fifo")
+		form.Add("MessageBody", body)
+		form.Add("MessageGroupId", "GROUP-X")
+		form.Add("Version", "2012-11-05")
+		req.PostForm = form
 
-	// Check the status code is what we expect.
-	if status != http.StatusOK {
-		t.Errorf("handler returned wrong status code: got \n%v want %v",
-			status, http.StatusOK)
+		status, _ = SendMessageV1(req)
+		assert.Equal(t, http.StatusOK, status)
 	}
-	if len(app.SyncQueues.Queues["no-dup-testing.fifo"].Messages) == 0 {
-		t.Fatal("there should be 1 message in queue")
-	}
-
-	form = url.Values{}
-	form.Add("Action", "SendMessage")
-	form.Add("QueueUrl", "http://localhost:4100/queue/no-dup-testing.fifo")
-	form.Add("MessageBody", "Test2")
-	form.Add("MessageDeduplicationId", "123")
-	form.Add("Version", "2012-11-05")
-	req.PostForm = form
-
-	status, _ = SendMessageV1(req)
 
-	// Check the status code is what we expect.
-	if status != http.StatusOK {
-		t.Errorf("handler returned wrong status code: got \n%v want %v",
-			status, http.StatusOK)
-	}
-	if len(app.SyncQueues.Queues["no-dup-testing.fifo"].Messages) != 2 {
-		t.Fatal("there should be 2 message in queue")
+	messages := app.SyncQueues.Queues["content-dup-testing.fifo"].Messages.All()
+	if len(messages) != 2 {
+		t.Fatal("there should be 2 messages in queue")
 	}
+	sum := sha256.Sum256([]byte("Test1"))
+	assert.Equal(t, hex.EncodeToString(sum[:]), messages[0].DeduplicationID)
 }
 
 func TestSendMessage_POST_DuplicatationEnabledOnFifoQueue(t *testing.T) {
 	done := make(chan struct{}, 0)
 	go PeriodicTasks(1*time.Second, done)
+	defer close(done)
 
 	// create a queue
 	req, err := http.NewRequest("POST", "/", nil)
@@ -621,13 +612,12 @@ func TestSendMessage_POST_DuplicatationEnabledOnFifoQueue(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	app.SyncQueues.Queues["dup-testing.fifo"].EnableDuplicates = true
-
 	form = url.Values{}
 	form.Add("Action", "SendMessage")
 	form.Add("QueueUrl", "http://localhost:4100/queue/dup-testing.fifo")
 	form.Add("MessageBody", "Test1")
 	form.Add("MessageDeduplicationId", "123")
+	form.Add("MessageGroupId", "GROUP-X")
 	form.Add("Version", "2012-11-05")
 	req.PostForm = form
 
@@ -638,7 +628,7 @@ func TestSendMessage_POST_DuplicatationEnabledOnFifoQueue(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got \n%v want %v",
 			status, http.StatusOK)
 	}
-	if len(app.SyncQueues.Queues["dup-testing.fifo"].Messages) == 0 {
+	if app.SyncQueues.Queues["dup-testing.fifo"].Messages.Len() == 0 {
 		t.Fatal("there should be 1 message in queue")
 	}
 
@@ -647,6 +637,7 @@ func TestSendMessage_POST_DuplicatationEnabledOnFifoQueue(t *testing.T) {
 	form.Add("QueueUrl", "http://localhost:4100/queue/dup-testing.fifo")
 	form.Add("MessageBody", "Test2")
 	form.Add("MessageDeduplicationId", "123")
+	form.Add("MessageGroupId", "GROUP-X")
 	form.Add("Version", "2012-11-05")
 	req.PostForm = form
 
@@ -657,10 +648,10 @@ func TestSendMessage_POST_DuplicatationEnabledOnFifoQueue(t *testing.T) {
 		t.Errorf("handler returned wrong status code: got \n%v want %v",
 			status, http.StatusOK)
 	}
-	if len(app.SyncQueues.Queues["dup-testing.fifo"].Messages) != 1 {
+	if app.SyncQueues.Queues["dup-testing.fifo"].Messages.Len() != 1 {
 		t.Fatal("there should be 1 message in queue")
 	}
-	if body := app.SyncQueues.Queues["dup-testing.fifo"].Messages[0].MessageBody; string(body) == "Test2" {
+	if body := app.SyncQueues.Queues["dup-testing.fifo"].Messages.All()[0].MessageBody; string(body) == "Test2" {
 		t.Fatal("duplicate message should not be added to queue")
 	}
 }
@@ -736,6 +727,228 @@ func TestSendMessage_POST_DelaySeconds(t *testing.T) {
 	}
 }
 
+func TestExpireMessages_removes_messages_older_than_retention_period(t *testing.T) {
+	now := time.Now()
+	q := &app.Queue{
+		Name:                   "retention-queue.fifo",
+		MessageRetentionPeriod: 60,
+		IsFIFO:                 true,
+		FIFOMessages:           map[string]int{"group-1": 0},
+		Messages: app.NewMessageStore(
+			app.Message{Uuid: "expired", SentTime: now.Add(-61 * time.Second)},
+			app.Message{Uuid: "expired-in-flight", SentTime: now.Add(-2 * time.Minute), ReceiptHandle: "handle", GroupID: "group-1"},
+			app.Message{Uuid: "kept", SentTime: now.Add(-59 * time.Second)},
+			app.Message{Uuid: "no-sent-time"},
+		),
+	}
+
+	expireMessages(q, now)
+
+	assert.Len(t, q.Messages.All(), 2)
+	assert.Equal(t, "kept", q.Messages.All()[0].Uuid)
+	assert.Equal(t, "no-sent-time", q.Messages.All()[1].Uuid)
+	assert.False(t, q.IsLocked("group-1"))
+}
+
+func TestExpireMessages_no_retention_period_keeps_messages(t *testing.T) {
+	q := &app.Queue{
+		Messages: app.NewMessageStore(
+			app.Message{Uuid: "old", SentTime: time.Now().Add(-30 * 24 * time.Hour)},
+		),
+	}
+
+	expireMessages(q, time.Now())
+
+	assert.Len(t, q.Messages.All(), 1)
+}
+
+func TestMaintainQueue_dead_letters_burst_of_poison_messages(t *testing.T) {
+	now := time.Now()
+	dlq := &app.Queue{Name: "poison-dlq", Arn: "arn:aws:sqs:us-east-1:100010001000:poison-dlq"}
+	q := &app.Queue{
+		Name:            "poison",
+		Arn:             "arn:aws:sqs:us-east-1:100010001000:poison",
+		MaxReceiveCount: 2,
+		DeadLetterQueue: dlq,
+	}
+	attributes := map[string]app.MessageAttributeValue{"trace": {DataType: "String", StringValue: "abc"}}
+	for i := 0; i < 100; i++ {
+		q.Messages.Add(app.Message{
+			Uuid:                   fmt.Sprintf("poison-%d", i),
+			MessageBody:            []byte("poison"),
+			MessageAttributes:      attributes,
+			MD5OfMessageAttributes: "md5-of-attributes",
+			ReceiptHandle:          fmt.Sprintf("handle-%d", i),
+			VisibilityTimeout:      now.Add(-time.Second),
+			NumberOfReceives:       3,
+			Retry:                  2,
+			SentTime:               now.Add(-time.Minute),
+			DelaySecs:              5,
+		})
+	}
+	q.Messages.Add(app.Message{Uuid: "healthy", ReceiptHandle: "handle", VisibilityTimeout: now.Add(-time.Second), Retry: 1})
+	q.Messages.Add(app.Message{Uuid: "still-in-flight", ReceiptHandle: "other", VisibilityTimeout: now.Add(time.Minute), Retry: 2})
+
+	maintainQueue(q, now)
+
+	assert.Equal(t, 2, q.Messages.Len())
+	healthy, _ := q.Messages.Get("healthy")
+	assert.Equal(t, "", healthy.ReceiptHandle)
+	assert.Equal(t, 2, healthy.Retry)
+	assert.Equal(t, 1, q.Messages.CountInFlight())
+
+	moved := dlq.Messages.All()
+	assert.Len(t, moved, 100)
+	for _, msg := range moved {
+		assert.Contains(t, msg.Uuid, "poison-")
+		assert.Equal(t, []byte("poison"), msg.MessageBody)
+		assert.Equal(t, attributes, msg.MessageAttributes)
+		assert.Equal(t, "md5-of-attributes", msg.MD5OfMessageAttributes)
+		assert.Equal(t, q.Arn, msg.DeadLetterQueueSourceArn)
+		assert.Equal(t, "", msg.ReceiptHandle)
+		assert.Zero(t, msg.VisibilityTimeout)
+		assert.Equal(t, 0, msg.Retry)
+		assert.Equal(t, 0, msg.DelaySecs)
+		assert.Equal(t, now, msg.SentTime)
+	}
+	assert.True(t, dlq.Messages.HasVisible(now))
+	assert.Equal(t, 0, dlq.Messages.CountInFlight())
+}
+
+func TestMaintainQueue_without_dead_letter_queue_keeps_poison_messages(t *testing.T) {
+	now := time.Now()
+	q := &app.Queue{Name: "poison", MaxReceiveCount: 1}
+	for i := 0; i < 10; i++ {
+		q.Messages.Add(app.Message{
+			Uuid:              fmt.Sprintf("poison-%d", i),
+			ReceiptHandle:     fmt.Sprintf("handle-%d", i),
+			VisibilityTimeout: now.Add(-time.Second),
+			Retry:             5,
+		})
+	}
+
+	maintainQueue(q, now)
+
+	assert.Equal(t, 10, q.Messages.Len())
+	assert.Equal(t, 0, q.Messages.CountInFlight())
+}
+
+func TestMaintainQueue_dead_lettering_unlocks_fifo_group(t *testing.T) {
+	now := time.Now()
+	dlq := &app.Queue{Name: "poison-dlq.fifo", IsFIFO: true}
+	q := &app.Queue{
+		Name:            "poison.fifo",
+		Arn:             "arn:aws:sqs:us-east-1:100010001000:poison.fifo",
+		IsFIFO:          true,
+		FIFOMessages:    map[string]int{},
+		MaxReceiveCount: 1,
+		DeadLetterQueue: dlq,
+	}
+	q.Messages.Add(app.Message{Uuid: "first", GroupID: "group-1", ReceiptHandle: "handle", VisibilityTimeout: now.Add(-time.Second), Retry: 1})
+	q.Messages.Add(app.Message{Uuid: "second", GroupID: "group-1"})
+	q.LockGroup("group-1")
+
+	maintainQueue(q, now)
+
+	assert.False(t, q.IsLocked("group-1"))
+	assert.Equal(t, 1, q.Messages.Len())
+	assert.Equal(t, "first", dlq.Messages.All()[0].Uuid)
+	assert.Equal(t, "group-1", dlq.Messages.All()[0].GroupID)
+}
+
+func TestNextMessageReveal(t *testing.T) {
+	now := time.Now()
+	q := &app.Queue{
+		Messages: app.NewMessageStore(
+			app.Message{Uuid: "in-flight", ReceiptHandle: "handle"},
+			app.Message{Uuid: "delayed-later", SentTime: now, DelaySecs: 10},
+			app.Message{Uuid: "delayed-sooner", SentTime: now.Add(-5 * time.Second), DelaySecs: 10},
+		),
+	}
+
+	found, next := nextMessageReveal(q, now)
+	assert.False(t, found)
+	assert.Equal(t, now.Add(5*time.Second), next)
+
+	q.Messages.Add(app.Message{Uuid: "visible", SentTime: now})
+	found, next = nextMessageReveal(q, now)
+	assert.True(t, found)
+	assert.True(t, next.IsZero())
+}
+
+func TestConcurrentQueueAccess(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+	}()
+	done := make(chan struct{}, 0)
+	go PeriodicTasks(10*time.Millisecond, done)
+	defer close(done)
+
+	dlq := &app.Queue{Name: "concurrent-dlq", Duplicates: make(map[string]app.Deduplicated)}
+	q := &app.Queue{Name: "concurrent-queue", DeadLetterQueue: dlq, MaxReceiveCount: 1, Duplicates: make(map[string]app.Deduplicated)}
+	app.SyncQueues.Lock()
+	app.SyncQueues.Queues[dlq.Name] = dlq
+	app.SyncQueues.Queues[q.Name] = q
+	app.SyncQueues.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageRequest{
+					QueueUrl:    "http://localhost:4100/queue/concurrent-queue",
+					MessageBody: "body",
+				}, true)
+				SendMessageV1(r)
+
+				_, r = test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+					QueueUrl:            "http://localhost:4100/queue/concurrent-queue",
+					MaxNumberOfMessages: 10,
+				}, true)
+				_, resp := ReceiveMessageV1(r)
+				for _, msg := range resp.GetResult().(models.ReceiveMessageResult).Messages {
+					_, r = test.GenerateRequestInfo("POST", "/", models.ChangeMessageVisibilityRequest{
+						QueueUrl:      "http://localhost:4100/queue/concurrent-queue",
+						ReceiptHandle: msg.ReceiptHandle,
+					}, true)
+					ChangeMessageVisibilityV1(r)
+				}
+
+				_, r = test.GenerateRequestInfo("POST", "/", models.GetQueueAttributesRequest{
+					QueueUrl: "http://localhost:4100/queue/concurrent-dlq",
+				}, true)
+				GetQueueAttributesV1(r)
+
+				_, r = test.GenerateRequestInfo("POST", "/", models.ListQueueRequest{}, true)
+				ListQueuesV1(r)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 25; j++ {
+			_, r := test.GenerateRequestInfo("POST", "/", models.CreateQueueRequest{QueueName: "short-lived"}, true)
+			CreateQueueV1(r)
+			_, r = test.GenerateRequestInfo("POST", "/", models.DeleteQueueRequest{
+				QueueUrl: "http://localhost:4100/queue/short-lived",
+			}, true)
+			DeleteQueueV1(r)
+		}
+	}()
+
+	if timedout := waitTimeout(&wg, 20*time.Second); timedout {
+		t.Fatal("concurrent queue access didn't finish")
+	}
+	q.RLock()
+	dlq.RLock()
+	assert.Equal(t, 100, q.Messages.Len()+dlq.Messages.Len())
+	dlq.RUnlock()
+	q.RUnlock()
+}
+
 func TestCreateErrorResponseV1(t *testing.T) {
 	expectedResponse := models.ErrorResponse{
 		Result: models.ErrorResult{
@@ -0,0 +1,68 @@
+package gosqs
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app/common"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+
+	"github.com/Admiral-Piett/goaws/app"
+	log "github.com/sirupsen/logrus"
+)
+
+func ListDeadLetterSourceQueuesV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewListDeadLetterSourceQueuesRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - ListDeadLetterSourceQueuesV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+	if requestBody.MaxResults < 0 || requestBody.MaxResults > maxListQueuesResults {
+		log.Errorf("Invalid MaxResults: %d", requestBody.MaxResults)
+		return utils.CreateErrorResponseV1("InvalidMaxResults", true)
+	}
+
+	uriSegments := strings.Split(requestBody.QueueUrl, "/")
+	queueName := uriSegments[len(uriSegments)-1]
+
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
+		log.Errorf("List Dead Letter Source Queues: %s, queue does not exist!!!", queueName)
+		return utils.CreateErrorResponseV1("QueueNotFound", true)
+	}
+
+	// The sources come ordered by name, like ListQueues they page by it.
+	sources := deadLetterSourceQueues(queue)
+	names := make([]string, 0, len(sources))
+	for _, source := range sources {
+		names = append(names, source.Name)
+	}
+
+	pageSize := requestBody.MaxResults
+	if pageSize == 0 {
+		pageSize = maxListQueuesResults
+	}
+	start, end, nextToken, ok := common.Paginate(names, requestBody.NextToken, pageSize)
+	if !ok {
+		log.Errorf("Invalid NextToken: %s", requestBody.NextToken)
+		return utils.CreateErrorResponseV1("InvalidNextToken", true)
+	}
+	if requestBody.MaxResults == 0 {
+		nextToken = ""
+	}
+
+	result := models.ListDeadLetterSourceQueuesResult{QueueUrls: make([]string, 0, end-start), NextToken: nextToken}
+	for _, source := range sources[start:end] {
+		result.QueueUrls = append(result.QueueUrls, source.URL)
+	}
+
+	respStruct := models.ListDeadLetterSourceQueuesResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Result:   result,
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
@@ -0,0 +1,50 @@
+package gosqs
+
+import (
+	"net/http"
+
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func ListMessageMoveTasksV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewListMessageMoveTasksRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - ListMessageMoveTasksV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+	maxResults := requestBody.MaxResults
+	if maxResults == 0 {
+		maxResults = 1
+	}
+	if maxResults < 1 || maxResults > maxMessageMoveTaskResults {
+		log.Errorf("Invalid MaxResults: %d", requestBody.MaxResults)
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+
+	if _, ok := queueFromArn(requestBody.SourceArn); !ok {
+		log.Errorf("List Message Move Tasks: %s, queue does not exist!!!", requestBody.SourceArn)
+		return utils.CreateErrorResponseV1("ResourceNotFound", true)
+	}
+
+	// Most recent first.
+	results := []models.MessageMoveTaskResultEntry{}
+	messageMoveTasks.Lock()
+	for i := len(messageMoveTasks.tasks) - 1; i >= 0 && len(results) < maxResults; i-- {
+		if task := messageMoveTasks.tasks[i]; task.sourceArn == requestBody.SourceArn {
+			results = append(results, task.entry())
+		}
+	}
+	messageMoveTasks.Unlock()
+
+	respStruct := models.ListMessageMoveTasksResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Result:   models.ListMessageMoveTasksResult{Results: results},
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
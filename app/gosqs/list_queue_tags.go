@@ -0,0 +1,48 @@
+package gosqs
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+func ListQueueTagsV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewListQueueTagsRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - ListQueueTagsV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+
+	uriSegments := strings.Split(requestBody.QueueUrl, "/")
+	queueName := uriSegments[len(uriSegments)-1]
+
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
+		log.Errorf("List Queue Tags: %s, queue does not exist!!!", queueName)
+		return utils.CreateErrorResponseV1("QueueNotFound", true)
+	}
+
+	tags := []models.QueueTag{}
+	queue.RLock()
+	for key, value := range queue.Tags {
+		tags = append(tags, models.QueueTag{Key: key, Value: value})
+	}
+	queue.RUnlock()
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Key < tags[j].Key
+	})
+
+	respStruct := models.ListQueueTagsResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Result:   models.ListQueueTagsResult{Tags: tags},
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
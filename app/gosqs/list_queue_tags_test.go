@@ -0,0 +1,69 @@
+package gosqs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListQueueTagsV1_success_sorted_by_key(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].Tags = map[string]string{"team": "platform", "env": "dev"}
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListQueueTagsRequest)
+		*v = models.ListQueueTagsRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+		}
+		return true
+	}
+
+	expectedResponse := models.ListQueueTagsResponse{
+		Xmlns: models.BASE_XMLNS,
+		Result: models.ListQueueTagsResult{Tags: []models.QueueTag{
+			{Key: "env", Value: "dev"},
+			{Key: "team", Value: "platform"},
+		}},
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := ListQueueTagsV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, expectedResponse, response)
+}
+
+func TestListQueueTagsV1_error_queue_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListQueueTagsRequest)
+		*v = models.ListQueueTagsRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "not-a-queue"),
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := ListQueueTagsV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -2,8 +2,11 @@ package gosqs
 
 import (
 	"net/http"
+	"sort"
 	"strings"
 
+	"github.com/Admiral-Piett/goaws/app/common"
+
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	"github.com/Admiral-Piett/goaws/app/models"
@@ -13,7 +16,7 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// TODO - set up MaxResults, NextToken request params
+// ListQueuesV1 lists the queues by name, like AWS a NextToken is only returned when MaxResults is given.
 //
 //	https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_ListQueues.html
 func ListQueuesV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
@@ -24,21 +27,44 @@ func ListQueuesV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
 	}
 
+	if requestBody.MaxResults < 0 || requestBody.MaxResults > maxListQueuesResults {
+		return utils.CreateErrorResponseV1("InvalidMaxResults", true)
+	}
+
 	log.Info("Listing Queues")
-	queueUrls := make([]string, 0)
-	app.SyncQueues.Lock()
-	for _, queue := range app.SyncQueues.Queues {
+	urlsByName := map[string]string{}
+	names := make([]string, 0)
+	for _, queue := range app.SyncQueues.All() {
 		if strings.HasPrefix(queue.Name, requestBody.QueueNamePrefix) {
-			queueUrls = append(queueUrls, queue.URL)
+			urlsByName[queue.Name] = queue.URL
+			names = append(names, queue.Name)
 		}
 	}
-	app.SyncQueues.Unlock()
+	sort.Strings(names)
+
+	pageSize := requestBody.MaxResults
+	if pageSize == 0 {
+		pageSize = maxListQueuesResults
+	}
+	start, end, nextToken, ok := common.Paginate(names, requestBody.NextToken, pageSize)
+	if !ok {
+		return utils.CreateErrorResponseV1("InvalidNextToken", true)
+	}
+	if requestBody.MaxResults == 0 {
+		nextToken = ""
+	}
+
+	queueUrls := make([]string, 0, end-start)
+	for _, name := range names[start:end] {
+		queueUrls = append(queueUrls, urlsByName[name])
+	}
 
 	respStruct := models.ListQueuesResponse{
 		Xmlns:    models.BASE_XMLNS,
 		Metadata: models.BASE_RESPONSE_METADATA,
 		Result: models.ListQueuesResult{
 			QueueUrls: queueUrls,
+			NextToken: nextToken,
 		},
 	}
 
@@ -114,3 +114,103 @@ func TestListQueuesV1_request_transformer_error(t *testing.T) {
 
 	assert.Equal(t, http.StatusBadRequest, code)
 }
+
+func TestListQueuesV1_success_paginates_by_name(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	request := models.ListQueueRequest{MaxResults: 3}
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListQueueRequest)
+		*v = request
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := ListQueuesV1(r)
+	r1 := response.(models.ListQueuesResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{
+		fmt.Sprintf("%s/%s", fixtures.BASE_URL, "other-queue1"),
+		fmt.Sprintf("%s/%s", fixtures.BASE_URL, "subscribed-queue1"),
+		fmt.Sprintf("%s/%s", fixtures.BASE_URL, "subscribed-queue3"),
+	}, r1.Result.QueueUrls)
+	assert.NotEmpty(t, r1.Result.NextToken)
+
+	request.NextToken = r1.Result.NextToken
+	_, r = test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response = ListQueuesV1(r)
+	r2 := response.(models.ListQueuesResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []string{
+		fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+		fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue2"),
+	}, r2.Result.QueueUrls)
+	assert.Empty(t, r2.Result.NextToken)
+}
+
+func TestListQueuesV1_success_no_next_token_without_max_results(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListQueueRequest)
+		*v = models.ListQueueRequest{}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := ListQueuesV1(r)
+	r1 := response.(models.ListQueuesResponse)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, r1.Result.NextToken)
+}
+
+func TestListQueuesV1_error_invalid_max_results(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	for _, maxResults := range []int{-1, 1001} {
+		utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+			v := resultingStruct.(*models.ListQueueRequest)
+			*v = models.ListQueueRequest{MaxResults: maxResults}
+			return true
+		}
+
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		code, response := ListQueuesV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, response.GetResult().(models.ErrorResult).Message, "MaxResults")
+	}
+}
+
+func TestListQueuesV1_error_invalid_next_token(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListQueueRequest)
+		*v = models.ListQueueRequest{MaxResults: 1, NextToken: "not a token!"}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := ListQueuesV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, response.GetResult().(models.ErrorResult).Message, "NextToken")
+}
@@ -0,0 +1,26 @@
+package gosqs
+
+import (
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMessageAttributeResult_custom_types_and_lists(t *testing.T) {
+	result := getMessageAttributeResult("attr1", &app.MessageAttributeValue{DataType: "Number.float", StringValue: "1.5"})
+	assert.Equal(t, &models.ResultMessageAttribute{
+		Name:  "attr1",
+		Value: &models.ResultMessageAttributeValue{DataType: "Number.float", StringValue: "1.5"},
+	}, result)
+
+	result = getMessageAttributeResult("attr2", &app.MessageAttributeValue{DataType: "Binary.gif", BinaryListValues: []string{"AQI="}})
+	assert.Equal(t, &models.ResultMessageAttribute{
+		Name:  "attr2",
+		Value: &models.ResultMessageAttributeValue{DataType: "Binary.gif", BinaryListValues: []string{"AQI="}},
+	}, result)
+
+	result = getMessageAttributeResult("attr3", &app.MessageAttributeValue{DataType: "String", StringListValues: []string{"a", "b"}})
+	assert.Equal(t, []string{"a", "b"}, result.Value.StringListValues)
+}
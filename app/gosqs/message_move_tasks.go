@@ -0,0 +1,233 @@
+package gosqs
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
+	"github.com/google/uuid"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	messageMoveTaskRunning   = "RUNNING"
+	messageMoveTaskCompleted = "COMPLETED"
+	messageMoveTaskCancelled = "CANCELLED"
+	messageMoveTaskFailed    = "FAILED"
+
+	// AWS picks a "system optimized" rate when none is given, we just go as fast as we allow.
+	maxMessageMoveRate = 500
+	// The most tasks ListMessageMoveTasks returns for a queue, older ones are forgotten.
+	maxMessageMoveTaskResults = 10
+)
+
+// messageMoveTask redrives the messages of a dead letter queue, either to a chosen queue or back to the
+// queues they were dead lettered from.  It is advanced by `PeriodicTasks`.
+type messageMoveTask struct {
+	handle         string
+	source         *app.Queue
+	sourceArn      string
+	destinationArn string
+	rate           int // messages per second
+	status         string
+	moved          int64
+	toMove         int64
+	failureReason  string
+	started        time.Time
+}
+
+// messageMoveTasks holds the latest tasks started for each source queue, oldest first.  Its lock is a leaf,
+// it is never held while taking any other.
+var messageMoveTasks = struct {
+	sync.Mutex
+	tasks []*messageMoveTask
+}{}
+
+func newMessageMoveTaskHandle(sourceArn string) string {
+	handle, _ := json.Marshal(map[string]string{"taskId": uuid.NewString(), "sourceArn": sourceArn})
+	return base64.StdEncoding.EncodeToString(handle)
+}
+
+// addMessageMoveTask records a newly started task, and forgets the source queue's tasks that
+// ListMessageMoveTasks can no longer return.
+// NOTE: the caller must hold `messageMoveTasks`.
+func addMessageMoveTask(task *messageMoveTask) {
+	tasks := append(messageMoveTasks.tasks, task)
+	kept := make([]*messageMoveTask, len(tasks))
+	next := len(kept)
+	count := 0
+	for i := len(tasks) - 1; i >= 0; i-- {
+		if tasks[i].sourceArn == task.sourceArn {
+			if count == maxMessageMoveTaskResults {
+				continue
+			}
+			count++
+		}
+		next--
+		kept[next] = tasks[i]
+	}
+	messageMoveTasks.tasks = kept[next:]
+}
+
+// NOTE: the caller must hold `messageMoveTasks`.
+func (t *messageMoveTask) entry() models.MessageMoveTaskResultEntry {
+	e := models.MessageMoveTaskResultEntry{
+		Status:                            t.status,
+		SourceArn:                         t.sourceArn,
+		DestinationArn:                    t.destinationArn,
+		MaxNumberOfMessagesPerSecond:      t.rate,
+		ApproximateNumberOfMessagesMoved:  t.moved,
+		ApproximateNumberOfMessagesToMove: t.toMove,
+		FailureReason:                     t.failureReason,
+		StartedTimestamp:                  t.started.UnixMilli(),
+	}
+	// AWS only hands out the handle while the task can still be cancelled.
+	if t.status == messageMoveTaskRunning {
+		e.TaskHandle = t.handle
+	}
+	return e
+}
+
+// queueFromArn looks a queue up by its ARN.
+func queueFromArn(arn string) (*app.Queue, bool) {
+	segments := strings.Split(arn, ":")
+	queue, ok := app.SyncQueues.Get(segments[len(segments)-1])
+	if !ok || queue.Arn != arn {
+		return nil, false
+	}
+	return queue, true
+}
+
+// deadLetterSourceQueues returns every queue whose redrive policy points at `dlq`, ordered by name.
+func deadLetterSourceQueues(dlq *app.Queue) []*app.Queue {
+	sources := []*app.Queue{}
+	for _, queue := range app.SyncQueues.All() {
+		queue.RLock()
+		isSource := queue.DeadLetterQueue == dlq
+		queue.RUnlock()
+		if isSource {
+			sources = append(sources, queue)
+		}
+	}
+	return sources
+}
+
+// advanceMessageMoveTasks gives every running task its share of moves for the `elapsed` time.
+func advanceMessageMoveTasks(elapsed time.Duration) {
+	messageMoveTasks.Lock()
+	running := []*messageMoveTask{}
+	for _, task := range messageMoveTasks.tasks {
+		if task.status == messageMoveTaskRunning {
+			running = append(running, task)
+		}
+	}
+	messageMoveTasks.Unlock()
+
+	for _, task := range running {
+		budget := int(float64(task.rate) * elapsed.Seconds())
+		if budget < 1 {
+			budget = 1
+		}
+		moveMessages(task, budget)
+	}
+}
+
+// moveMessages moves up to `budget` of the visible messages of the task's source queue, and finishes the
+// task once it has moved everything that was there when it started.
+func moveMessages(task *messageMoveTask, budget int) {
+	source := task.source
+	if current, ok := queueFromArn(task.sourceArn); !ok || current != source {
+		finishMessageMoveTask(task, 0, messageMoveTaskFailed, "The source queue no longer exists.")
+		return
+	}
+
+	messageMoveTasks.Lock()
+	if remaining := int(task.toMove - task.moved); remaining < budget {
+		budget = remaining
+	}
+	messageMoveTasks.Unlock()
+
+	batch := []app.Message{}
+	source.Lock()
+	if budget > 0 {
+		ids := []string{}
+		source.Messages.Receive(func(msg *app.Message) bool {
+			ids = append(ids, msg.Uuid)
+			return len(ids) < budget
+		})
+		for _, id := range ids {
+			msg, _ := source.Messages.Remove(id)
+			persistence.MessageDeleted(source, id)
+			batch = append(batch, msg)
+		}
+	}
+	drained := source.Messages.Len() == 0
+	source.Unlock()
+
+	moved := 0
+	failureReason := ""
+	for _, msg := range batch {
+		destinationArn := task.destinationArn
+		if destinationArn == "" {
+			destinationArn = msg.DeadLetterQueueSourceArn
+		}
+		destination, ok := queueFromArn(destinationArn)
+		if !ok {
+			failureReason = "Message " + msg.Uuid + " has no destination queue to be moved to."
+			break
+		}
+		// It arrives like a new message, it was never received from the destination.
+		msg.DeadLetterQueueSourceArn = ""
+		resetDelivery(&msg, time.Now())
+		msg.NumberOfReceives = 0
+		msg.FirstReceiveTime = time.Time{}
+		destination.Lock()
+		if stored, duplicate := destination.Enqueue(msg); duplicate {
+			log.Debugf("Message [%s] moved to [%s] is a duplicate, dropping it", msg.Uuid, destination.Name)
+		} else {
+			persistence.MessageUpdated(destination, stored)
+		}
+		destination.Unlock()
+		moved++
+	}
+
+	if failureReason != "" {
+		// Put back what we couldn't move.
+		source.Lock()
+		for _, msg := range batch[moved:] {
+			persistence.MessageUpdated(source, source.Messages.Add(msg))
+		}
+		source.Unlock()
+		source.Signal()
+		finishMessageMoveTask(task, moved, messageMoveTaskFailed, failureReason)
+		return
+	}
+
+	messageMoveTasks.Lock()
+	task.moved += int64(moved)
+	done := task.moved >= task.toMove || drained
+	messageMoveTasks.Unlock()
+	if done {
+		finishMessageMoveTask(task, 0, messageMoveTaskCompleted, "")
+	}
+}
+
+// finishMessageMoveTask counts the last of the task's moves and gives it its final status, unless it has
+// already been cancelled.
+func finishMessageMoveTask(task *messageMoveTask, moved int, status string, failureReason string) {
+	messageMoveTasks.Lock()
+	defer messageMoveTasks.Unlock()
+	task.moved += int64(moved)
+	if task.status != messageMoveTaskRunning {
+		return
+	}
+	log.Debugf("Message move task from [%s] %s after moving %d messages", task.sourceArn, strings.ToLower(status), task.moved)
+	task.status = status
+	task.failureReason = failureReason
+}
@@ -0,0 +1,405 @@
+package gosqs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDeadLetterQueueArn = "arn:aws:sqs:region:accountID:other-queue1"
+
+func resetMessageMoveTasks() {
+	messageMoveTasks.Lock()
+	messageMoveTasks.tasks = nil
+	messageMoveTasks.Unlock()
+}
+
+func startMessageMoveTask(request models.StartMessageMoveTaskRequest) (int, interfaces.AbstractResponseBody) {
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.StartMessageMoveTaskRequest)
+		*v = request
+		return true
+	}
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	return StartMessageMoveTaskV1(r)
+}
+
+func TestListDeadLetterSourceQueuesV1_success(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListDeadLetterSourceQueuesRequest)
+		*v = models.ListDeadLetterSourceQueuesRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "other-queue1"),
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := ListDeadLetterSourceQueuesV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	result := response.(models.ListDeadLetterSourceQueuesResponse).Result
+	assert.Equal(t, []string{app.SyncQueues.Queues["unit-queue2"].URL}, result.QueueUrls)
+	assert.Equal(t, "", result.NextToken)
+}
+
+func TestListDeadLetterSourceQueuesV1_pages(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].DeadLetterQueue = app.SyncQueues.Queues["other-queue1"]
+
+	nextToken := ""
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListDeadLetterSourceQueuesRequest)
+		*v = models.ListDeadLetterSourceQueuesRequest{
+			QueueUrl:   fmt.Sprintf("%s/%s", fixtures.BASE_URL, "other-queue1"),
+			MaxResults: 1,
+			NextToken:  nextToken,
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	_, response := ListDeadLetterSourceQueuesV1(r)
+	result := response.(models.ListDeadLetterSourceQueuesResponse).Result
+	assert.Equal(t, []string{app.SyncQueues.Queues["unit-queue1"].URL}, result.QueueUrls)
+	assert.NotEqual(t, "", result.NextToken)
+
+	nextToken = result.NextToken
+	_, r = test.GenerateRequestInfo("POST", "/", nil, true)
+	_, response = ListDeadLetterSourceQueuesV1(r)
+	result = response.(models.ListDeadLetterSourceQueuesResponse).Result
+	assert.Equal(t, []string{app.SyncQueues.Queues["unit-queue2"].URL}, result.QueueUrls)
+	assert.Equal(t, "", result.NextToken)
+}
+
+func TestListDeadLetterSourceQueuesV1_error_queue_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListDeadLetterSourceQueuesRequest)
+		*v = models.ListDeadLetterSourceQueuesRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "garbage"),
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := ListDeadLetterSourceQueuesV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestListDeadLetterSourceQueuesV1_error_invalid_paging(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	var tests = []struct {
+		maxResults int
+		nextToken  string
+		errKey     string
+	}{
+		{-1, "", "InvalidMaxResults"},
+		{1001, "", "InvalidMaxResults"},
+		{1, "not base64!", "InvalidNextToken"},
+	}
+	for _, tt := range tests {
+		utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+			v := resultingStruct.(*models.ListDeadLetterSourceQueuesRequest)
+			*v = models.ListDeadLetterSourceQueuesRequest{
+				QueueUrl:   fmt.Sprintf("%s/%s", fixtures.BASE_URL, "other-queue1"),
+				MaxResults: tt.maxResults,
+				NextToken:  tt.nextToken,
+			}
+			return true
+		}
+
+		_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+		code, response := ListDeadLetterSourceQueuesV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Equal(t, models.SqsErrors[tt.errKey].Message, response.(models.ErrorResponse).Result.Message)
+	}
+}
+
+func TestStartMessageMoveTaskV1_redrives_to_original_source_at_rate(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+	source := app.SyncQueues.Queues["unit-queue2"]
+	dlq := app.SyncQueues.Queues["other-queue1"]
+	for i := 0; i < 3; i++ {
+		dlq.Messages.Add(app.Message{Uuid: fmt.Sprintf("message-%d", i), Retry: 101, DeadLetterQueueSourceArn: source.Arn})
+	}
+
+	code, response := startMessageMoveTask(models.StartMessageMoveTaskRequest{
+		SourceArn:                    testDeadLetterQueueArn,
+		MaxNumberOfMessagesPerSecond: 2,
+	})
+	assert.Equal(t, http.StatusOK, code)
+	handle := response.(models.StartMessageMoveTaskResponse).Result.TaskHandle
+	assert.NotEqual(t, "", handle)
+
+	advanceMessageMoveTasks(time.Second)
+
+	assert.Equal(t, 1, dlq.Messages.Len())
+	assert.Equal(t, 2, source.Messages.Len())
+	moved := source.Messages.All()[0]
+	assert.Equal(t, "message-0", moved.Uuid)
+	assert.Equal(t, 0, moved.Retry)
+	assert.Equal(t, "", moved.DeadLetterQueueSourceArn)
+	entry := messageMoveTasks.tasks[0].entry()
+	assert.Equal(t, "RUNNING", entry.Status)
+	assert.Equal(t, handle, entry.TaskHandle)
+	assert.Equal(t, int64(2), entry.ApproximateNumberOfMessagesMoved)
+	assert.Equal(t, int64(3), entry.ApproximateNumberOfMessagesToMove)
+
+	advanceMessageMoveTasks(time.Second)
+
+	assert.Equal(t, 0, dlq.Messages.Len())
+	assert.Equal(t, 3, source.Messages.Len())
+	entry = messageMoveTasks.tasks[0].entry()
+	assert.Equal(t, "COMPLETED", entry.Status)
+	assert.Equal(t, "", entry.TaskHandle)
+	assert.Equal(t, int64(3), entry.ApproximateNumberOfMessagesMoved)
+}
+
+func TestStartMessageMoveTaskV1_to_destination(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+	dlq := app.SyncQueues.Queues["other-queue1"]
+	dlq.Messages.Add(app.Message{Uuid: "message-1"})
+	destination := app.SyncQueues.Queues["unit-queue1"]
+
+	code, _ := startMessageMoveTask(models.StartMessageMoveTaskRequest{
+		SourceArn:      testDeadLetterQueueArn,
+		DestinationArn: destination.Arn,
+	})
+	assert.Equal(t, http.StatusOK, code)
+
+	advanceMessageMoveTasks(time.Second)
+
+	assert.Equal(t, 0, dlq.Messages.Len())
+	assert.Equal(t, "message-1", destination.Messages.All()[0].Uuid)
+	assert.Equal(t, "COMPLETED", messageMoveTasks.tasks[0].status)
+}
+
+func TestStartMessageMoveTaskV1_redrives_into_fifo_queue(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+	source := &app.Queue{Name: "source.fifo", Arn: "arn:aws:sqs:region:accountID:source.fifo", IsFIFO: true}
+	dlq := &app.Queue{Name: "dlq.fifo", Arn: "arn:aws:sqs:region:accountID:dlq.fifo", IsFIFO: true}
+	source.DeadLetterQueue = dlq
+	app.SyncQueues.Queues[source.Name] = source
+	app.SyncQueues.Queues[dlq.Name] = dlq
+	for i := 0; i < 2; i++ {
+		dlq.Messages.Add(app.Message{
+			Uuid:                     fmt.Sprintf("message-%d", i),
+			GroupID:                  "group-1",
+			DeduplicationID:          fmt.Sprintf("dedup-%d", i),
+			SequenceNumber:           "00000000000000000001",
+			NumberOfReceives:         4,
+			FirstReceiveTime:         time.Now().Add(-time.Hour),
+			ReceiptTime:              time.Now().Add(-time.Minute),
+			DeadLetterQueueSourceArn: source.Arn,
+		})
+	}
+
+	code, _ := startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: dlq.Arn})
+	assert.Equal(t, http.StatusOK, code)
+
+	advanceMessageMoveTasks(time.Second)
+
+	moved := source.Messages.All()
+	assert.Len(t, moved, 2)
+	for i, msg := range moved {
+		assert.Equal(t, fmt.Sprintf("message-%d", i), msg.Uuid)
+		assert.Equal(t, 0, msg.NumberOfReceives)
+		assert.True(t, msg.FirstReceiveTime.IsZero())
+		assert.True(t, msg.ReceiptTime.IsZero())
+		assert.True(t, source.IsDuplicate("group-1", msg.DeduplicationID))
+	}
+	assert.NotEqual(t, "00000000000000000001", moved[0].SequenceNumber)
+	assert.True(t, moved[0].SequenceNumber < moved[1].SequenceNumber)
+	assert.Equal(t, moved[1].SequenceNumber, fmt.Sprintf("%020d", source.FIFOSequenceNumber))
+
+	// Within the deduplication period the same messages are duplicates, like any resend.
+	dlq.Messages.Add(app.Message{Uuid: "message-2", GroupID: "group-1", DeduplicationID: "dedup-0", DeadLetterQueueSourceArn: source.Arn})
+	code, _ = startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: dlq.Arn})
+	assert.Equal(t, http.StatusOK, code)
+	advanceMessageMoveTasks(time.Second)
+	assert.Equal(t, 0, dlq.Messages.Len())
+	assert.Len(t, source.Messages.All(), 2)
+}
+
+func TestStartMessageMoveTaskV1_fails_without_a_destination(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+	dlq := app.SyncQueues.Queues["other-queue1"]
+	dlq.Messages.Add(app.Message{Uuid: "message-1"})
+
+	startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn})
+	advanceMessageMoveTasks(time.Second)
+
+	assert.Equal(t, 1, dlq.Messages.Len())
+	assert.Equal(t, "FAILED", messageMoveTasks.tasks[0].status)
+	assert.NotEqual(t, "", messageMoveTasks.tasks[0].failureReason)
+}
+
+func TestStartMessageMoveTaskV1_errors(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+
+	code, _ := startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: "arn:aws:sqs:region:accountID:garbage"})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: app.SyncQueues.Queues["unit-queue1"].Arn})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn, MaxNumberOfMessagesPerSecond: 501})
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	fifo := &app.Queue{Name: "destination.fifo", Arn: "arn:aws:sqs:region:accountID:destination.fifo", IsFIFO: true}
+	app.SyncQueues.Queues[fifo.Name] = fifo
+	code, response := startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn, DestinationArn: fifo.Arn})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, models.SqsErrors["InvalidMessageMoveDestination"].Response(), response.(models.ErrorResponse).Result)
+
+	code, _ = startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn})
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestListMessageMoveTasksV1_most_recent_first(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+	_, response := startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn})
+	first := response.(models.StartMessageMoveTaskResponse).Result.TaskHandle
+	advanceMessageMoveTasks(time.Second)
+	_, response = startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn, MaxNumberOfMessagesPerSecond: 10})
+	second := response.(models.StartMessageMoveTaskResponse).Result.TaskHandle
+	assert.NotEqual(t, first, second)
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.ListMessageMoveTasksRequest)
+		*v = models.ListMessageMoveTasksRequest{SourceArn: testDeadLetterQueueArn, MaxResults: 10}
+		return true
+	}
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := ListMessageMoveTasksV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	results := response.(models.ListMessageMoveTasksResponse).Result.Results
+	assert.Len(t, results, 2)
+	assert.Equal(t, second, results[0].TaskHandle)
+	assert.Equal(t, "RUNNING", results[0].Status)
+	assert.Equal(t, 10, results[0].MaxNumberOfMessagesPerSecond)
+	assert.Equal(t, "", results[1].TaskHandle)
+	assert.Equal(t, "COMPLETED", results[1].Status)
+}
+
+func TestStartMessageMoveTaskV1_keeps_only_the_listed_history(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+	other := &messageMoveTask{sourceArn: "arn:aws:sqs:region:accountID:other-dlq", status: messageMoveTaskCompleted}
+	messageMoveTasks.tasks = []*messageMoveTask{other}
+
+	handles := []string{}
+	for i := 0; i < maxMessageMoveTaskResults+5; i++ {
+		code, response := startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn})
+		assert.Equal(t, http.StatusOK, code)
+		handles = append(handles, response.(models.StartMessageMoveTaskResponse).Result.TaskHandle)
+		advanceMessageMoveTasks(time.Second)
+	}
+
+	assert.Len(t, messageMoveTasks.tasks, maxMessageMoveTaskResults+1)
+	assert.Equal(t, other, messageMoveTasks.tasks[0])
+	assert.Equal(t, handles[5], messageMoveTasks.tasks[1].handle)
+	assert.Equal(t, handles[len(handles)-1], messageMoveTasks.tasks[maxMessageMoveTaskResults].handle)
+}
+
+func TestCancelMessageMoveTaskV1(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+		resetMessageMoveTasks()
+	}()
+	dlq := app.SyncQueues.Queues["other-queue1"]
+	dlq.Messages.Add(app.Message{Uuid: "message-1"})
+	_, response := startMessageMoveTask(models.StartMessageMoveTaskRequest{SourceArn: testDeadLetterQueueArn})
+	handle := response.(models.StartMessageMoveTaskResponse).Result.TaskHandle
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.CancelMessageMoveTaskRequest)
+		*v = models.CancelMessageMoveTaskRequest{TaskHandle: handle}
+		return true
+	}
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := CancelMessageMoveTaskV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, int64(0), response.(models.CancelMessageMoveTaskResponse).Result.ApproximateNumberOfMessagesMoved)
+	assert.Equal(t, "CANCELLED", messageMoveTasks.tasks[0].status)
+
+	// Nothing moves once cancelled, and it can't be cancelled twice.
+	advanceMessageMoveTasks(time.Second)
+	assert.Equal(t, 1, dlq.Messages.Len())
+
+	_, r = test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ = CancelMessageMoveTaskV1(r)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
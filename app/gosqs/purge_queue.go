@@ -3,10 +3,10 @@ package gosqs
 import (
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -24,16 +24,19 @@ func PurgeQueueV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	uriSegments := strings.Split(requestBody.QueueUrl, "/")
 	queueName := uriSegments[len(uriSegments)-1]
 
-	app.SyncQueues.Lock()
-	defer app.SyncQueues.Unlock()
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
 		log.Errorf("Purge Queue: %s, queue does not exist!!!", queueName)
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
 	}
 
 	log.Infof("Purging Queue: %s", queueName)
-	app.SyncQueues.Queues[queueName].Messages = nil
-	app.SyncQueues.Queues[queueName].Duplicates = make(map[string]time.Time)
+	queue.Lock()
+	queue.Messages.Clear()
+	queue.Duplicates = make(map[string]app.Deduplicated)
+	queue.FIFOMessages = nil
+	persistence.QueuePurged(queue)
+	queue.Unlock()
 
 	respStruct := models.PurgeQueueResponse{
 		Xmlns:    models.BASE_XMLNS,
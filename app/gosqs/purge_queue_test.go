@@ -36,9 +36,9 @@ func TestPurgeQueueV1_success(t *testing.T) {
 	// Put a message on the queue
 	targetQueue := app.SyncQueues.Queues["unit-queue1"]
 	app.SyncQueues.Lock()
-	targetQueue.Messages = []app.Message{app.Message{}}
-	targetQueue.Duplicates = map[string]time.Time{
-		"dedupe-id": time.Now(),
+	targetQueue.Messages = app.NewMessageStore(app.Message{})
+	targetQueue.Duplicates = map[string]app.Deduplicated{
+		"dedupe-id": {Sent: time.Now()},
 	}
 	app.SyncQueues.Unlock()
 
@@ -53,8 +53,8 @@ func TestPurgeQueueV1_success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, code)
 	assert.Equal(t, expectedResponse, response)
 
-	assert.Nil(t, targetQueue.Messages)
-	assert.Equal(t, map[string]time.Time{}, targetQueue.Duplicates)
+	assert.Equal(t, 0, targetQueue.Messages.Len())
+	assert.Equal(t, map[string]app.Deduplicated{}, targetQueue.Duplicates)
 }
 
 func TestPurgeQueueV1_success_no_messages_on_queue(t *testing.T) {
@@ -84,8 +84,8 @@ func TestPurgeQueueV1_success_no_messages_on_queue(t *testing.T) {
 	assert.Equal(t, expectedResponse, response)
 
 	targetQueue := app.SyncQueues.Queues["unit-queue1"]
-	assert.Nil(t, targetQueue.Messages)
-	assert.Equal(t, map[string]time.Time{}, targetQueue.Duplicates)
+	assert.Equal(t, 0, targetQueue.Messages.Len())
+	assert.Equal(t, map[string]app.Deduplicated{}, targetQueue.Duplicates)
 }
 
 func TestPurgeQueueV1_request_transformer_error(t *testing.T) {
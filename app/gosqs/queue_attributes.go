@@ -1,8 +1,11 @@
 package gosqs
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -11,11 +14,101 @@ import (
 	"github.com/Admiral-Piett/goaws/app"
 )
 
-// TODO - Support:
-//   - attr.MessageRetentionPeriod
-//   - attr.Policy
-//   - attr.RedriveAllowPolicy
+// AWS bounds for MessageRetentionPeriod, in seconds: 1 minute to 14 days.
+const (
+	minMessageRetentionPeriod = 60
+	maxMessageRetentionPeriod = 1209600
+)
+
+// NOTE: this takes the lock of `q` itself, everything is validated before anything is changed.
 func setQueueAttributesV1(q *app.Queue, attr models.QueueAttributes) error {
+	// The following 2 don't support zero values
+	if attr.MessageRetentionPeriod > 0 &&
+		(attr.MessageRetentionPeriod < minMessageRetentionPeriod || attr.MessageRetentionPeriod > maxMessageRetentionPeriod) {
+		log.Errorf("Invalid MessageRetentionPeriod Attribute: %d", attr.MessageRetentionPeriod)
+		return fmt.Errorf("InvalidRetentionPeriod")
+	}
+	// Negative values mean the attribute isn't being set, so only the upper bounds are checked.
+	if attr.DelaySeconds > maxDelaySeconds {
+		log.Errorf("Invalid DelaySeconds Attribute: %d", attr.DelaySeconds)
+		return fmt.Errorf("InvalidQueueDelaySeconds")
+	}
+	if attr.MaximumMessageSize > maxMessageSize {
+		log.Errorf("Invalid MaximumMessageSize Attribute: %d", attr.MaximumMessageSize)
+		return fmt.Errorf("InvalidMaximumMessageSize")
+	}
+	if attr.ReceiveMessageWaitTimeSeconds > maxWaitTimeSeconds {
+		log.Errorf("Invalid ReceiveMessageWaitTimeSeconds Attribute: %d", attr.ReceiveMessageWaitTimeSeconds)
+		return fmt.Errorf("InvalidReceiveMessageWaitTimeSeconds")
+	}
+	if attr.VisibilityTimeout > maxVisibilityTimeout {
+		log.Errorf("Invalid VisibilityTimeout Attribute: %d", attr.VisibilityTimeout)
+		return fmt.Errorf("InvalidQueueVisibilityTimeout")
+	}
+	// A queue is FIFO if and only if its name says so, and that can never change.
+	if attr.FifoQueue != nil && attr.FifoQueue.Bool() != q.IsFIFO {
+		log.Errorf("Invalid FifoQueue Attribute for queue: %s", q.Name)
+		return fmt.Errorf("InvalidFifoQueueName")
+	}
+	if (attr.ContentBasedDeduplication != nil || attr.DeduplicationScope != "" || attr.FifoThroughputLimit != "") && !q.IsFIFO {
+		log.Errorf("FIFO Attribute on standard queue: %s", q.Name)
+		return fmt.Errorf("InvalidAttributeName")
+	}
+	if attr.DeduplicationScope != "" &&
+		attr.DeduplicationScope != app.DeduplicationScopeQueue && attr.DeduplicationScope != app.DeduplicationScopeMessageGroup {
+		log.Errorf("Invalid DeduplicationScope Attribute: %s", attr.DeduplicationScope)
+		return fmt.Errorf("InvalidDeduplicationScope")
+	}
+	if attr.FifoThroughputLimit != "" &&
+		attr.FifoThroughputLimit != app.FifoThroughputLimitPerQueue && attr.FifoThroughputLimit != app.FifoThroughputLimitPerMessageGroupId {
+		log.Errorf("Invalid FifoThroughputLimit Attribute: %s", attr.FifoThroughputLimit)
+		return fmt.Errorf("InvalidFifoThroughputLimit")
+	}
+	policy, err := queuePolicy(attr.Policy)
+	if err != nil {
+		log.Errorf("Invalid Policy Attribute: %s", err)
+		return fmt.Errorf("InvalidPolicy")
+	}
+	redriveAllowPolicy, err := queueRedriveAllowPolicy(attr.RedriveAllowPolicy)
+	if err != nil {
+		log.Errorf("Invalid RedriveAllowPolicy Attribute: %s", err)
+		return fmt.Errorf("InvalidRedriveAllowPolicy")
+	}
+	var deadLetterQueue *app.Queue
+	if attr.RedrivePolicy != (models.RedrivePolicy{}) {
+		arnArray := strings.Split(attr.RedrivePolicy.DeadLetterTargetArn, ":")
+		queueName := arnArray[len(arnArray)-1]
+		dlq, ok := app.SyncQueues.Get(queueName)
+		if !ok {
+			log.Error("Invalid RedrivePolicy Attribute")
+			return fmt.Errorf("InvalidAttributeValue")
+		}
+		if dlq.IsFIFO != q.IsFIFO {
+			log.Errorf("Dead letter queue %s is not the same type of queue as %s", dlq.Name, q.Name)
+			return fmt.Errorf("InvalidDeadLetterQueueType")
+		}
+		if !dlq.AllowsRedriveFrom(q.Arn) {
+			log.Errorf("Dead letter queue %s doesn't allow redrive from %s", dlq.Name, q.Name)
+			return fmt.Errorf("RedriveNotAllowed")
+		}
+		deadLetterQueue = dlq
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	// Only a throughput limit per message group needs the deduplication to be per message group too.
+	deduplicationScope := q.DeduplicationScope
+	if attr.DeduplicationScope != "" {
+		deduplicationScope = attr.DeduplicationScope
+	}
+	fifoThroughputLimit := q.FifoThroughputLimit
+	if attr.FifoThroughputLimit != "" {
+		fifoThroughputLimit = attr.FifoThroughputLimit
+	}
+	if fifoThroughputLimit == app.FifoThroughputLimitPerMessageGroupId && deduplicationScope != app.DeduplicationScopeMessageGroup {
+		log.Errorf("FifoThroughputLimit %s with DeduplicationScope %s", fifoThroughputLimit, deduplicationScope)
+		return fmt.Errorf("InvalidFifoThroughputLimit")
+	}
 	// FIXME - are there better places to put these bottom-limit validations?
 	if attr.DelaySeconds >= 0 {
 		q.DelaySeconds = attr.DelaySeconds.Int()
@@ -23,8 +116,6 @@ func setQueueAttributesV1(q *app.Queue, attr models.QueueAttributes) error {
 	if attr.MaximumMessageSize >= 0 {
 		q.MaximumMessageSize = attr.MaximumMessageSize.Int()
 	}
-	// TODO - bottom limit should be the AWS limits
-	// The following 2 don't support zero values
 	if attr.MessageRetentionPeriod > 0 {
 		q.MessageRetentionPeriod = attr.MessageRetentionPeriod.Int()
 	}
@@ -34,16 +125,113 @@ func setQueueAttributesV1(q *app.Queue, attr models.QueueAttributes) error {
 	if attr.VisibilityTimeout >= 0 {
 		q.VisibilityTimeout = attr.VisibilityTimeout.Int()
 	}
-	if attr.RedrivePolicy != (models.RedrivePolicy{}) {
-		arnArray := strings.Split(attr.RedrivePolicy.DeadLetterTargetArn, ":")
-		queueName := arnArray[len(arnArray)-1]
-		deadLetterQueue, ok := app.SyncQueues.Queues[queueName]
-		if !ok {
-			log.Error("Invalid RedrivePolicy Attribute")
-			return fmt.Errorf("InvalidAttributeValue")
-		}
+	if attr.ContentBasedDeduplication != nil {
+		q.ContentBasedDeduplication = attr.ContentBasedDeduplication.Bool()
+	}
+	q.DeduplicationScope = deduplicationScope
+	q.FifoThroughputLimit = fifoThroughputLimit
+	if attr.Policy != nil {
+		q.Policy = policy
+	}
+	if attr.RedriveAllowPolicy != nil {
+		q.RedriveAllowPolicy = redriveAllowPolicy
+	}
+	if deadLetterQueue != nil {
 		q.DeadLetterQueue = deadLetterQueue
 		q.MaxReceiveCount = attr.RedrivePolicy.MaxReceiveCount.Int()
 	}
+	q.LastModified = time.Now()
 	return nil
 }
+
+// queueAttributesMatch tells if the given attributes, out of `attr`, are what `q` already has.  CreateQueue
+// only hands back an existing queue when they are, the attributes the request left out don't count.
+// NOTE: this takes the read lock of `q` itself.
+func queueAttributesMatch(q *app.Queue, attr models.QueueAttributes, given []string) bool {
+	q.RLock()
+	defer q.RUnlock()
+	for _, name := range given {
+		match := true
+		switch name {
+		case "DelaySeconds":
+			match = attr.DelaySeconds.Int() == q.DelaySeconds
+		case "MaximumMessageSize":
+			match = attr.MaximumMessageSize.Int() == q.MaximumMessageSize
+		case "MessageRetentionPeriod":
+			match = attr.MessageRetentionPeriod.Int() == q.MessageRetentionPeriod
+		case "ReceiveMessageWaitTimeSeconds":
+			match = attr.ReceiveMessageWaitTimeSeconds.Int() == q.ReceiveMessageWaitTimeSeconds
+		case "VisibilityTimeout":
+			match = attr.VisibilityTimeout.Int() == q.VisibilityTimeout
+		case "RedrivePolicy":
+			arnArray := strings.Split(attr.RedrivePolicy.DeadLetterTargetArn, ":")
+			match = q.DeadLetterQueue != nil && q.DeadLetterQueue.Name == arnArray[len(arnArray)-1] &&
+				attr.RedrivePolicy.MaxReceiveCount.Int() == q.MaxReceiveCount
+		case "Policy":
+			policy, err := queuePolicy(attr.Policy)
+			match = err == nil && samePolicy(policy, q.Policy)
+		case "RedriveAllowPolicy":
+			policy, err := queueRedriveAllowPolicy(attr.RedriveAllowPolicy)
+			match = err == nil && samePolicy(policy, q.RedriveAllowPolicy)
+		case "FifoQueue":
+			match = attr.FifoQueue != nil && attr.FifoQueue.Bool() == q.IsFIFO
+		case "ContentBasedDeduplication":
+			match = attr.ContentBasedDeduplication != nil && attr.ContentBasedDeduplication.Bool() == q.ContentBasedDeduplication
+		case "DeduplicationScope":
+			match = attr.DeduplicationScope == q.DeduplicationScope ||
+				(attr.DeduplicationScope == app.DeduplicationScopeQueue && q.DeduplicationScope == "")
+		case "FifoThroughputLimit":
+			match = attr.FifoThroughputLimit == q.FifoThroughputLimit ||
+				(attr.FifoThroughputLimit == app.FifoThroughputLimitPerQueue && q.FifoThroughputLimit == "")
+		}
+		if !match {
+			log.Errorf("Queue %s already exists with a different %s", q.Name, name)
+			return false
+		}
+	}
+	return true
+}
+
+// queuePolicy is the text of a policy document for a queue, once it's been checked to be one.  An empty
+// document is no policy at all.
+func queuePolicy(document map[string]interface{}) (string, error) {
+	if len(document) == 0 {
+		return "", nil
+	}
+	text, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	if _, err := app.ParsePolicy(string(text)); err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// queueRedriveAllowPolicy is the text of a redrive allow policy for a queue, once it's been checked to be
+// one.  An empty policy is none, which allows all.
+func queueRedriveAllowPolicy(document map[string]interface{}) (string, error) {
+	if len(document) == 0 {
+		return "", nil
+	}
+	text, err := json.Marshal(document)
+	if err != nil {
+		return "", err
+	}
+	if _, err := app.ParseRedriveAllowPolicy(string(text)); err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// samePolicy tells if two policy documents say the same, however they are laid out.
+func samePolicy(a string, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	var documentA, documentB interface{}
+	if json.Unmarshal([]byte(a), &documentA) != nil || json.Unmarshal([]byte(b), &documentB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(documentA, documentB)
+}
@@ -3,6 +3,7 @@ package gosqs
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/Admiral-Piett/goaws/app/test"
 	"github.com/stretchr/testify/assert"
@@ -18,7 +19,7 @@ func TestSetQueueAttributesV1_success_no_redrive_policy(t *testing.T) {
 	attrs := models.QueueAttributes{
 		DelaySeconds:                  1,
 		MaximumMessageSize:            2,
-		MessageRetentionPeriod:        3,
+		MessageRetentionPeriod:        60,
 		ReceiveMessageWaitTimeSeconds: 4,
 		VisibilityTimeout:             5,
 	}
@@ -27,7 +28,7 @@ func TestSetQueueAttributesV1_success_no_redrive_policy(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 1, q.DelaySeconds)
 	assert.Equal(t, 2, q.MaximumMessageSize)
-	assert.Equal(t, 3, q.MessageRetentionPeriod)
+	assert.Equal(t, 60, q.MessageRetentionPeriod)
 	assert.Equal(t, 4, q.ReceiveMessageWaitTimeSeconds)
 	assert.Equal(t, 5, q.VisibilityTimeout)
 	assert.Equal(t, emptyQueue, q.DeadLetterQueue)
@@ -55,7 +56,7 @@ func TestSetQueueAttributesV1_success_can_set_0_values_where_applicable(t *testi
 	q := &app.Queue{
 		DelaySeconds:                  1,
 		MaximumMessageSize:            2,
-		MessageRetentionPeriod:        3,
+		MessageRetentionPeriod:        60,
 		ReceiveMessageWaitTimeSeconds: 4,
 		VisibilityTimeout:             5,
 	}
@@ -65,7 +66,7 @@ func TestSetQueueAttributesV1_success_can_set_0_values_where_applicable(t *testi
 	assert.Nil(t, err)
 	assert.Equal(t, 0, q.DelaySeconds)
 	assert.Equal(t, 0, q.MaximumMessageSize)
-	assert.Equal(t, 3, q.MessageRetentionPeriod)
+	assert.Equal(t, 60, q.MessageRetentionPeriod)
 	assert.Equal(t, 4, q.ReceiveMessageWaitTimeSeconds)
 	assert.Equal(t, 0, q.VisibilityTimeout)
 	assert.Equal(t, emptyQueue, q.DeadLetterQueue)
@@ -85,7 +86,7 @@ func TestSetQueueAttributesV1_success_with_redrive_policy(t *testing.T) {
 	attrs := models.QueueAttributes{
 		DelaySeconds:                  1,
 		MaximumMessageSize:            2,
-		MessageRetentionPeriod:        3,
+		MessageRetentionPeriod:        60,
 		ReceiveMessageWaitTimeSeconds: 4,
 		VisibilityTimeout:             5,
 		RedrivePolicy: models.RedrivePolicy{
@@ -98,7 +99,7 @@ func TestSetQueueAttributesV1_success_with_redrive_policy(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, 1, q.DelaySeconds)
 	assert.Equal(t, 2, q.MaximumMessageSize)
-	assert.Equal(t, 3, q.MessageRetentionPeriod)
+	assert.Equal(t, 60, q.MessageRetentionPeriod)
 	assert.Equal(t, 4, q.ReceiveMessageWaitTimeSeconds)
 	assert.Equal(t, 5, q.VisibilityTimeout)
 	assert.Equal(t, existingQueue, q.DeadLetterQueue)
@@ -112,7 +113,7 @@ func TestSetQueueAttributesV1_error_redrive_policy_targets_missing_queue(t *test
 	attrs := models.QueueAttributes{
 		DelaySeconds:                  1,
 		MaximumMessageSize:            2,
-		MessageRetentionPeriod:        3,
+		MessageRetentionPeriod:        60,
 		ReceiveMessageWaitTimeSeconds: 4,
 		VisibilityTimeout:             5,
 		RedrivePolicy: models.RedrivePolicy{
@@ -124,3 +125,262 @@ func TestSetQueueAttributesV1_error_redrive_policy_targets_missing_queue(t *test
 
 	assert.Error(t, err)
 }
+
+func TestSetQueueAttributesV1_error_message_retention_period_out_of_bounds(t *testing.T) {
+	for _, period := range []models.StringToInt{59, 1209601} {
+		q := &app.Queue{MessageRetentionPeriod: 345600}
+		attrs := models.QueueAttributes{
+			MessageRetentionPeriod: period,
+		}
+		err := setQueueAttributesV1(q, attrs)
+
+		assert.EqualError(t, err, "InvalidRetentionPeriod")
+		assert.Equal(t, 345600, q.MessageRetentionPeriod)
+	}
+}
+
+func TestSetQueueAttributesV1_success_fifo_attributes(t *testing.T) {
+	q := &app.Queue{Name: "queue.fifo", IsFIFO: true}
+	fifoQueue := models.StringToBool(true)
+	contentBasedDeduplication := models.StringToBool(true)
+	attrs := models.QueueAttributes{
+		FifoQueue:                 &fifoQueue,
+		ContentBasedDeduplication: &contentBasedDeduplication,
+	}
+	err := setQueueAttributesV1(q, attrs)
+
+	assert.Nil(t, err)
+	assert.True(t, q.ContentBasedDeduplication)
+
+	contentBasedDeduplication = false
+	err = setQueueAttributesV1(q, models.QueueAttributes{ContentBasedDeduplication: &contentBasedDeduplication})
+
+	assert.Nil(t, err)
+	assert.False(t, q.ContentBasedDeduplication)
+}
+
+func TestSetQueueAttributesV1_error_fifo_queue_does_not_match_name(t *testing.T) {
+	fifoQueue := models.StringToBool(true)
+	err := setQueueAttributesV1(&app.Queue{Name: "queue"}, models.QueueAttributes{FifoQueue: &fifoQueue})
+	assert.Equal(t, fmt.Errorf("InvalidFifoQueueName"), err)
+
+	fifoQueue = false
+	err = setQueueAttributesV1(&app.Queue{Name: "queue.fifo", IsFIFO: true}, models.QueueAttributes{FifoQueue: &fifoQueue})
+	assert.Equal(t, fmt.Errorf("InvalidFifoQueueName"), err)
+}
+
+func TestSetQueueAttributesV1_error_content_based_deduplication_on_standard_queue(t *testing.T) {
+	contentBasedDeduplication := models.StringToBool(true)
+	q := &app.Queue{Name: "queue"}
+	err := setQueueAttributesV1(q, models.QueueAttributes{ContentBasedDeduplication: &contentBasedDeduplication})
+
+	assert.Equal(t, fmt.Errorf("InvalidAttributeName"), err)
+	assert.False(t, q.ContentBasedDeduplication)
+}
+
+func TestSetQueueAttributesV1_success_high_throughput_fifo_attributes(t *testing.T) {
+	q := &app.Queue{Name: "queue.fifo", IsFIFO: true}
+	err := setQueueAttributesV1(q, models.QueueAttributes{
+		DeduplicationScope:  "messageGroup",
+		FifoThroughputLimit: "perMessageGroupId",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, app.DeduplicationScopeMessageGroup, q.DeduplicationScope)
+	assert.Equal(t, app.FifoThroughputLimitPerMessageGroupId, q.FifoThroughputLimit)
+
+	// Only the throughput limit can go back on its own.
+	err = setQueueAttributesV1(q, models.QueueAttributes{DeduplicationScope: "queue"})
+	assert.Equal(t, fmt.Errorf("InvalidFifoThroughputLimit"), err)
+	assert.Equal(t, app.DeduplicationScopeMessageGroup, q.DeduplicationScope)
+
+	err = setQueueAttributesV1(q, models.QueueAttributes{FifoThroughputLimit: "perQueue"})
+	assert.Nil(t, err)
+	assert.Equal(t, app.FifoThroughputLimitPerQueue, q.FifoThroughputLimit)
+}
+
+func TestSetQueueAttributesV1_error_high_throughput_fifo_attributes(t *testing.T) {
+	cases := []struct {
+		queue *app.Queue
+		attrs models.QueueAttributes
+		err   string
+	}{
+		{&app.Queue{Name: "queue"}, models.QueueAttributes{DeduplicationScope: "queue"}, "InvalidAttributeName"},
+		{&app.Queue{Name: "queue"}, models.QueueAttributes{FifoThroughputLimit: "perQueue"}, "InvalidAttributeName"},
+		{&app.Queue{Name: "queue.fifo", IsFIFO: true}, models.QueueAttributes{DeduplicationScope: "group"}, "InvalidDeduplicationScope"},
+		{&app.Queue{Name: "queue.fifo", IsFIFO: true}, models.QueueAttributes{FifoThroughputLimit: "perGroup"}, "InvalidFifoThroughputLimit"},
+		{&app.Queue{Name: "queue.fifo", IsFIFO: true}, models.QueueAttributes{FifoThroughputLimit: "perMessageGroupId"}, "InvalidFifoThroughputLimit"},
+	}
+	for _, c := range cases {
+		err := setQueueAttributesV1(c.queue, c.attrs)
+
+		assert.Equal(t, fmt.Errorf(c.err), err)
+		assert.Equal(t, "", c.queue.DeduplicationScope)
+		assert.Equal(t, "", c.queue.FifoThroughputLimit)
+	}
+}
+
+func TestSetQueueAttributesV1_error_out_of_range_values(t *testing.T) {
+	cases := []struct {
+		attrs models.QueueAttributes
+		err   string
+	}{
+		{models.QueueAttributes{DelaySeconds: 901}, "InvalidQueueDelaySeconds"},
+		{models.QueueAttributes{MaximumMessageSize: 262145}, "InvalidMaximumMessageSize"},
+		{models.QueueAttributes{ReceiveMessageWaitTimeSeconds: 21}, "InvalidReceiveMessageWaitTimeSeconds"},
+		{models.QueueAttributes{VisibilityTimeout: 43201}, "InvalidQueueVisibilityTimeout"},
+	}
+	for _, c := range cases {
+		q := &app.Queue{Name: "queue"}
+		err := setQueueAttributesV1(q, c.attrs)
+
+		assert.Equal(t, fmt.Errorf(c.err), err)
+		assert.Equal(t, &app.Queue{Name: "queue"}, q)
+	}
+}
+
+func TestQueueAttributesMatch(t *testing.T) {
+	dlq := &app.Queue{Name: "dead-letters"}
+	q := &app.Queue{
+		Name:              "queue.fifo",
+		VisibilityTimeout: 30,
+		IsFIFO:            true,
+		DeadLetterQueue:   dlq,
+		MaxReceiveCount:   3,
+	}
+	contentBasedDeduplication := models.StringToBool(false)
+	attrs := models.QueueAttributes{
+		VisibilityTimeout:         30,
+		DelaySeconds:              10,
+		RedrivePolicy:             models.RedrivePolicy{MaxReceiveCount: 3, DeadLetterTargetArn: "arn:aws:sqs:region:accountID:dead-letters"},
+		ContentBasedDeduplication: &contentBasedDeduplication,
+		DeduplicationScope:        "queue",
+	}
+
+	assert.True(t, queueAttributesMatch(q, attrs, []string{"VisibilityTimeout", "RedrivePolicy", "ContentBasedDeduplication", "DeduplicationScope"}))
+	assert.False(t, queueAttributesMatch(q, attrs, []string{"VisibilityTimeout", "DelaySeconds"}))
+
+	attrs.RedrivePolicy.MaxReceiveCount = 4
+	assert.False(t, queueAttributesMatch(q, attrs, []string{"RedrivePolicy"}))
+}
+
+func TestSetQueueAttributesV1_updates_last_modified(t *testing.T) {
+	q := &app.Queue{Name: "queue"}
+
+	err := setQueueAttributesV1(q, models.QueueAttributes{VisibilityTimeout: 10})
+
+	assert.Nil(t, err)
+	assert.WithinDuration(t, time.Now(), q.LastModified, time.Second)
+}
+
+func TestSetQueueAttributesV1_policy(t *testing.T) {
+	q := &app.Queue{Name: "queue"}
+
+	err := setQueueAttributesV1(q, models.QueueAttributes{Policy: map[string]interface{}{
+		"Version":   "2012-10-17",
+		"Statement": map[string]interface{}{"Effect": "Allow", "Principal": "*", "Action": "sqs:SendMessage"},
+	}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Statement":{"Action":"sqs:SendMessage","Effect":"Allow","Principal":"*"},"Version":"2012-10-17"}`, q.Policy)
+
+	err = setQueueAttributesV1(q, models.QueueAttributes{VisibilityTimeout: 10})
+
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", q.Policy)
+
+	err = setQueueAttributesV1(q, models.QueueAttributes{Policy: map[string]interface{}{}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", q.Policy)
+}
+
+func TestSetQueueAttributesV1_error_invalid_policy(t *testing.T) {
+	q := &app.Queue{Name: "queue"}
+
+	err := setQueueAttributesV1(q, models.QueueAttributes{Policy: map[string]interface{}{"this-is": "the-policy"}})
+
+	assert.Equal(t, fmt.Errorf("InvalidPolicy"), err)
+	assert.Equal(t, &app.Queue{Name: "queue"}, q)
+}
+
+func TestQueueAttributesMatch_policy(t *testing.T) {
+	q := &app.Queue{Name: "queue", Policy: `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"sqs:*"}]}`}
+
+	same := models.QueueAttributes{Policy: map[string]interface{}{
+		"Statement": []interface{}{map[string]interface{}{"Action": "sqs:*", "Effect": "Allow", "Principal": "*"}},
+		"Version":   "2012-10-17",
+	}}
+	assert.True(t, queueAttributesMatch(q, same, []string{"Policy"}))
+
+	different := models.QueueAttributes{Policy: map[string]interface{}{
+		"Statement": []interface{}{map[string]interface{}{"Action": "sqs:*", "Effect": "Deny", "Principal": "*"}},
+	}}
+	assert.False(t, queueAttributesMatch(q, different, []string{"Policy"}))
+}
+
+func TestSetQueueAttributesV1_redrive_allow_policy(t *testing.T) {
+	q := &app.Queue{Name: "queue"}
+
+	err := setQueueAttributesV1(q, models.QueueAttributes{RedriveAllowPolicy: map[string]interface{}{
+		"redrivePermission": "byQueue",
+		"sourceQueueArns":   []interface{}{"arn:aws:sqs:region:accountID:source"},
+	}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `{"redrivePermission":"byQueue","sourceQueueArns":["arn:aws:sqs:region:accountID:source"]}`, q.RedriveAllowPolicy)
+
+	err = setQueueAttributesV1(q, models.QueueAttributes{RedriveAllowPolicy: map[string]interface{}{}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", q.RedriveAllowPolicy)
+
+	err = setQueueAttributesV1(q, models.QueueAttributes{RedriveAllowPolicy: map[string]interface{}{"redrivePermission": "byQueue"}})
+
+	assert.Equal(t, fmt.Errorf("InvalidRedriveAllowPolicy"), err)
+	assert.Equal(t, "", q.RedriveAllowPolicy)
+}
+
+func TestSetQueueAttributesV1_error_redrive_not_allowed(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+	}()
+	app.SyncQueues.Queues["dlq"] = &app.Queue{
+		Name:               "dlq",
+		RedriveAllowPolicy: `{"redrivePermission":"byQueue","sourceQueueArns":["arn:aws:sqs:region:accountID:allowed"]}`,
+	}
+	redrivePolicy := models.RedrivePolicy{MaxReceiveCount: 3, DeadLetterTargetArn: "arn:aws:sqs:region:accountID:dlq"}
+
+	q := &app.Queue{Name: "denied", Arn: "arn:aws:sqs:region:accountID:denied"}
+	err := setQueueAttributesV1(q, models.QueueAttributes{RedrivePolicy: redrivePolicy})
+
+	assert.Equal(t, fmt.Errorf("RedriveNotAllowed"), err)
+	var emptyQueue *app.Queue
+	assert.Equal(t, emptyQueue, q.DeadLetterQueue)
+
+	q = &app.Queue{Name: "allowed", Arn: "arn:aws:sqs:region:accountID:allowed"}
+	err = setQueueAttributesV1(q, models.QueueAttributes{RedrivePolicy: redrivePolicy})
+
+	assert.Nil(t, err)
+	assert.Equal(t, app.SyncQueues.Queues["dlq"], q.DeadLetterQueue)
+}
+
+func TestSetQueueAttributesV1_error_dead_letter_queue_type_mismatch(t *testing.T) {
+	defer func() {
+		test.ResetApp()
+	}()
+	app.SyncQueues.Queues["dlq"] = &app.Queue{Name: "dlq"}
+	app.SyncQueues.Queues["dlq.fifo"] = &app.Queue{Name: "dlq.fifo", IsFIFO: true}
+
+	q := &app.Queue{Name: "queue.fifo", IsFIFO: true}
+	err := setQueueAttributesV1(q, models.QueueAttributes{
+		RedrivePolicy: models.RedrivePolicy{MaxReceiveCount: 3, DeadLetterTargetArn: "arn:aws:sqs:region:accountID:dlq"},
+	})
+	assert.Equal(t, fmt.Errorf("InvalidDeadLetterQueueType"), err)
+
+	q = &app.Queue{Name: "queue"}
+	err = setQueueAttributesV1(q, models.QueueAttributes{
+		RedrivePolicy: models.RedrivePolicy{MaxReceiveCount: 3, DeadLetterTargetArn: "arn:aws:sqs:region:accountID:dlq.fifo"},
+	})
+	assert.Equal(t, fmt.Errorf("InvalidDeadLetterQueueType"), err)
+}
@@ -0,0 +1,48 @@
+package gosqs
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/Admiral-Piett/goaws/app"
+)
+
+// AWS limits for queue tags.
+const (
+	maxQueueTags        = 50
+	maxQueueTagKeyLen   = 128
+	maxQueueTagValueLen = 256
+)
+
+// tagQueue adds `tags` to the queue, overwriting the values of keys it already has.
+// NOTE: this takes the lock of `q` itself, nothing is changed unless all the tags are valid.
+func tagQueue(q *app.Queue, tags map[string]string) error {
+	for key, value := range tags {
+		if key == "" || utf8.RuneCountInString(key) > maxQueueTagKeyLen || utf8.RuneCountInString(value) > maxQueueTagValueLen {
+			log.Errorf("Invalid queue tag: %s", key)
+			return fmt.Errorf("InvalidTag")
+		}
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	count := len(q.Tags)
+	for key := range tags {
+		if _, ok := q.Tags[key]; !ok {
+			count++
+		}
+	}
+	if count > maxQueueTags {
+		log.Errorf("Too many tags for queue %s: %d", q.Name, count)
+		return fmt.Errorf("TooManyTags")
+	}
+	if q.Tags == nil {
+		q.Tags = make(map[string]string, len(tags))
+	}
+	for key, value := range tags {
+		q.Tags[key] = value
+	}
+	return nil
+}
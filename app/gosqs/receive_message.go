@@ -3,6 +3,7 @@ package gosqs
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,14 +11,12 @@ import (
 	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
 )
 
-// TODO - Admiral-Piett - could we refactor the way we hide messages?  Change data structure to a queue
-// organized by "reveal time" or a map with the key being a timestamp of when it could be shown?
-// Ordered Map - https://github.com/elliotchance/orderedmap
 func ReceiveMessageV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 	requestBody := models.NewReceiveMessageRequest()
 	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
@@ -30,6 +29,12 @@ func ReceiveMessageV1(req *http.Request) (int, interfaces.AbstractResponseBody)
 	if maxNumberOfMessages == 0 {
 		maxNumberOfMessages = 1
 	}
+	if maxNumberOfMessages < 0 || maxNumberOfMessages > maxNumberOfMessagesLimit {
+		return utils.CreateErrorResponseV1("InvalidMaxNumberOfMessages", true)
+	}
+	if requestBody.WaitTimeSeconds < 0 || requestBody.WaitTimeSeconds > maxWaitTimeSeconds {
+		return utils.CreateErrorResponseV1("InvalidWaitTimeSeconds", true)
+	}
 
 	queueName := ""
 	if requestBody.QueueUrl == "" {
@@ -40,89 +45,141 @@ func ReceiveMessageV1(req *http.Request) (int, interfaces.AbstractResponseBody)
 		queueName = uriSegments[len(uriSegments)-1]
 	}
 
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
 	}
 
+	if requestBody.VisibilityTimeout != nil && (*requestBody.VisibilityTimeout < 0 || *requestBody.VisibilityTimeout > maxVisibilityTimeout) {
+		return utils.CreateErrorResponseV1("InvalidVisibilityTimeout", true)
+	}
+	attributeNames := append(requestBody.AttributeNames, requestBody.MessageSystemAttributeNames...)
+
 	var messages []*models.ResultMessage
 	respStruct := models.ReceiveMessageResponse{}
 
+	// NOTE: the caller holds the queue's lock.
+	visibilityTimeout := func() int {
+		if requestBody.VisibilityTimeout != nil {
+			return *requestBody.VisibilityTimeout
+		}
+		return queue.VisibilityTimeout
+	}
+
+	// A retried FIFO receive gets what the first attempt got straight away, as long as none of it changed.
+	attemptId := requestBody.ReceiveRequestAttemptId
+	if attemptId != "" {
+		queue.Lock()
+		retried, ok := queue.RetryReceiveAttempt(attemptId, time.Duration(visibilityTimeout())*time.Second, time.Now())
+		if ok {
+			messages = make([]*models.ResultMessage, 0, len(retried))
+			for _, msg := range retried {
+				persistence.MessageUpdated(queue, msg)
+				messages = append(messages, getMessageResult(msg, attributeNames, requestBody.MessageAttributeNames))
+			}
+		}
+		queue.Unlock()
+		if ok {
+			log.Debugf("Retried receive attempt [%s] on Queue: %s", attemptId, queueName)
+			return http.StatusOK, models.ReceiveMessageResponse{
+				Xmlns:    models.BASE_XMLNS,
+				Result:   models.ReceiveMessageResult{Messages: messages},
+				Metadata: models.BASE_RESPONSE_METADATA,
+			}
+		}
+	}
+
 	waitTimeSeconds := requestBody.WaitTimeSeconds
 	if waitTimeSeconds == 0 {
-		app.SyncQueues.RLock()
-		waitTimeSeconds = app.SyncQueues.Queues[queueName].ReceiveMessageWaitTimeSeconds
-		app.SyncQueues.RUnlock()
+		queue.RLock()
+		waitTimeSeconds = queue.ReceiveMessageWaitTimeSeconds
+		queue.RUnlock()
 	}
 
-	loops := waitTimeSeconds * 10
-	for loops > 0 {
-		app.SyncQueues.RLock()
-		_, queueFound := app.SyncQueues.Queues[queueName]
-		if !queueFound {
-			app.SyncQueues.RUnlock()
+	// Long polling - sleep until a message may have become visible (the queue is signalled on sends,
+	// visibility expiry and dead letter moves), the next delayed message is due or we run out of time.
+	deadline := time.Now().Add(time.Duration(waitTimeSeconds) * time.Second)
+	for waitTimeSeconds > 0 {
+		if _, queueFound := app.SyncQueues.Get(queueName); !queueFound {
 			return utils.CreateErrorResponseV1("QueueNotFound", true)
 		}
-		messageFound := len(app.SyncQueues.Queues[queueName].Messages)-numberOfHiddenMessagesInQueue(*app.SyncQueues.Queues[queueName]) != 0
-		app.SyncQueues.RUnlock()
-		if !messageFound {
-			continueTimer := time.NewTimer(100 * time.Millisecond)
-			select {
-			case <-req.Context().Done():
-				continueTimer.Stop()
-				return http.StatusOK, models.ReceiveMessageResponse{
-					Xmlns:    models.BASE_XMLNS,
-					Result:   models.ReceiveMessageResult{},
-					Metadata: models.BASE_RESPONSE_METADATA,
-				}
-			case <-continueTimer.C:
-				continueTimer.Stop()
-			}
-			loops--
-		} else {
+		queue.RLock()
+		changed := queue.Changed()
+		messageFound, nextReveal := nextMessageReveal(queue, time.Now())
+		queue.RUnlock()
+		if messageFound {
 			break
 		}
 
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			break
+		}
+		if !nextReveal.IsZero() && time.Until(nextReveal) < wait {
+			wait = time.Until(nextReveal)
+		}
+		waitTimer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			waitTimer.Stop()
+			return http.StatusOK, models.ReceiveMessageResponse{
+				Xmlns:    models.BASE_XMLNS,
+				Result:   models.ReceiveMessageResult{},
+				Metadata: models.BASE_RESPONSE_METADATA,
+			}
+		case <-changed:
+			waitTimer.Stop()
+		case <-waitTimer.C:
+		}
 	}
 	log.Debugf("Getting Message from Queue:%s", queueName)
 
-	app.SyncQueues.Lock()         // Lock the Queues
-	defer app.SyncQueues.Unlock() // Unlock the Queues
+	if _, ok := app.SyncQueues.Get(queueName); !ok {
+		return utils.CreateErrorResponseV1("QueueNotFound", true)
+	}
+
+	queue.Lock()         // Lock the Queue
+	defer queue.Unlock() // Unlock the Queue
 
-	if len(app.SyncQueues.Queues[queueName].Messages) > 0 {
-		numMsg := 0
+	if queue.Messages.Len() > 0 {
 		messages = make([]*models.ResultMessage, 0)
-		for i := range app.SyncQueues.Queues[queueName].Messages {
-			if numMsg >= maxNumberOfMessages {
-				break
+		received := make([]*app.Message, 0)
+		// A FIFO group's messages are handed out strictly in order: none of them while an earlier one is
+		// in flight from a previous receive, or once one has been passed over in this one.  Several of
+		// them can go out together though.
+		receivingGroups := map[string]bool{}
+		skippedGroups := map[string]bool{}
+		queue.Messages.Receive(func(msg *app.Message) bool {
+			if queue.IsFIFO && (skippedGroups[msg.GroupID] || (queue.IsLocked(msg.GroupID) && !receivingGroups[msg.GroupID])) {
+				skippedGroups[msg.GroupID] = true
+				return true
+			}
+			if !msg.IsReadyForReceipt() {
+				skippedGroups[msg.GroupID] = true
+				return true
 			}
 
-			if app.SyncQueues.Queues[queueName].Messages[i].ReceiptHandle != "" {
-				continue
+			if queue.IsFIFO {
+				queue.LockGroup(msg.GroupID)
+				receivingGroups[msg.GroupID] = true
 			}
 
 			uuid, _ := common.NewUUID()
-
-			msg := &app.SyncQueues.Queues[queueName].Messages[i]
-			if !msg.IsReadyForReceipt() {
-				continue
-			}
 			msg.ReceiptHandle = msg.Uuid + "#" + uuid
 			msg.ReceiptTime = time.Now().UTC()
-			msg.VisibilityTimeout = time.Now().Add(time.Duration(app.SyncQueues.Queues[queueName].VisibilityTimeout) * time.Second)
-
-			if app.SyncQueues.Queues[queueName].IsFIFO {
-				// If we got messages here it means we have not processed it yet, so get next
-				if app.SyncQueues.Queues[queueName].IsLocked(msg.GroupID) {
-					continue
-				}
-				// Otherwise lock messages for group ID
-				app.SyncQueues.Queues[queueName].LockGroup(msg.GroupID)
+			if msg.FirstReceiveTime.IsZero() {
+				msg.FirstReceiveTime = msg.ReceiptTime
 			}
+			msg.NumberOfReceives++
+			msg.VisibilityTimeout = time.Now().Add(time.Duration(visibilityTimeout()) * time.Second)
 
-			messages = append(messages, getMessageResult(msg))
+			persistence.MessageUpdated(queue, msg)
+			received = append(received, msg)
+			messages = append(messages, getMessageResult(msg, attributeNames, requestBody.MessageAttributeNames))
 
-			numMsg++
-		}
+			return len(messages) < maxNumberOfMessages
+		})
+		queue.RememberReceiveAttempt(attemptId, received, time.Now())
 
 		respStruct = models.ReceiveMessageResponse{
 			"http://queue.amazonaws.com/doc/2012-11-05/",
@@ -141,34 +198,92 @@ func ReceiveMessageV1(req *http.Request) (int, interfaces.AbstractResponseBody)
 	return http.StatusOK, respStruct
 }
 
-func getMessageResult(m *app.Message) *models.ResultMessage {
+// getMessageResult shapes a received message, with only the system attributes named in `attributeNames`
+// and the message attributes matching `messageAttributeNames`.
+func getMessageResult(m *app.Message, attributeNames []string, messageAttributeNames []string) *models.ResultMessage {
 	msgMttrs := []*models.ResultMessageAttribute{}
-	for _, attr := range m.MessageAttributes {
-		msgMttrs = append(msgMttrs, getMessageAttributeResult(&attr))
+	returnedAttributes := map[string]app.MessageAttributeValue{}
+	names := []string{}
+	for name, attr := range m.MessageAttributes {
+		if wantsMessageAttribute(messageAttributeNames, name) {
+			returnedAttributes[name] = attr
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		attr := returnedAttributes[name]
+		msgMttrs = append(msgMttrs, getMessageAttributeResult(name, &attr))
+	}
+	// The digest covers what we return, that's what the SDKs check it against.
+	md5OfMessageAttributes := ""
+	if len(returnedAttributes) > 0 {
+		md5OfMessageAttributes = common.HashAttributes(returnedAttributes)
 	}
 
 	attrsMap := map[string]string{
-		"ApproximateFirstReceiveTimestamp": fmt.Sprintf("%d", m.ReceiptTime.UnixNano()/int64(time.Millisecond)),
+		"ApproximateFirstReceiveTimestamp": fmt.Sprintf("%d", m.FirstReceiveTime.UnixMilli()),
 		"SenderId":                         app.CurrentEnvironment.AccountID,
-		"ApproximateReceiveCount":          fmt.Sprintf("%d", m.NumberOfReceives+1),
-		"SentTimestamp":                    fmt.Sprintf("%d", time.Now().UTC().UnixNano()/int64(time.Millisecond)),
+		"ApproximateReceiveCount":          fmt.Sprintf("%d", m.NumberOfReceives),
+		"SentTimestamp":                    fmt.Sprintf("%d", m.SentTime.UnixMilli()),
+	}
+	// The rest only exist for some messages.
+	for name, value := range map[string]string{
+		"MessageGroupId":           m.GroupID,
+		"MessageDeduplicationId":   m.DeduplicationID,
+		"SequenceNumber":           m.SequenceNumber,
+		"AWSTraceHeader":           m.AWSTraceHeader,
+		"DeadLetterQueueSourceArn": m.DeadLetterQueueSourceArn,
+	} {
+		if value != "" {
+			attrsMap[name] = value
+		}
 	}
 
 	var attrs []*models.ResultAttribute
 	for k, v := range attrsMap {
-		attrs = append(attrs, &models.ResultAttribute{
-			Name:  k,
-			Value: v,
-		})
+		if wantsAttribute(attributeNames, k) {
+			attrs = append(attrs, &models.ResultAttribute{
+				Name:  k,
+				Value: v,
+			})
+		}
 	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Name < attrs[j].Name
+	})
 
 	return &models.ResultMessage{
 		MessageId:              m.Uuid,
 		Body:                   m.MessageBody,
 		ReceiptHandle:          m.ReceiptHandle,
 		MD5OfBody:              common.GetMD5Hash(string(m.MessageBody)),
-		MD5OfMessageAttributes: m.MD5OfMessageAttributes,
+		MD5OfMessageAttributes: md5OfMessageAttributes,
 		MessageAttributes:      msgMttrs,
 		Attributes:             attrs,
 	}
 }
+
+// wantsAttribute reports whether the system attribute `name` was asked for, by name or with `All`.
+func wantsAttribute(requested []string, name string) bool {
+	for _, r := range requested {
+		if r == "All" || r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// wantsMessageAttribute reports whether the message attribute `name` was asked for, by name, with `All`
+// or `.*`, or with a prefix like `foo.*`, which matches every attribute starting with `foo.`.
+func wantsMessageAttribute(requested []string, name string) bool {
+	for _, r := range requested {
+		if r == "All" || r == ".*" || r == name {
+			return true
+		}
+		if strings.HasSuffix(r, ".*") && strings.HasPrefix(name, strings.TrimSuffix(r, "*")) {
+			return true
+		}
+	}
+	return false
+}
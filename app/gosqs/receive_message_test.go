@@ -2,6 +2,7 @@ package gosqs
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"sync"
 	"testing"
@@ -45,7 +46,7 @@ func TestReceiveMessageWaitTimeEnforcedV1(t *testing.T) {
 	}
 
 	// mock sending a message
-	q.Messages = append(q.Messages, app.Message{MessageBody: []byte("1")})
+	q.Messages.Add(app.Message{MessageBody: []byte("1")})
 
 	// receive message
 	_, r = test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
@@ -135,6 +136,72 @@ func TestReceiveMessage_CanceledByClientV1(t *testing.T) {
 	}
 }
 
+func TestReceiveMessage_WokenBySendV1(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{
+		Name:                          "wake-queue",
+		ReceiveMessageWaitTimeSeconds: 20,
+	}
+	app.SyncQueues.Queues["wake-queue"] = q
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageRequest{
+			QueueUrl:    "http://localhost:4100/queue/wake-queue",
+			MessageBody: "wake up",
+		}, true)
+		SendMessageV1(r)
+	}()
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+		QueueUrl: "http://localhost:4100/queue/wake-queue",
+	}, true)
+	start := time.Now()
+	status, resp := ReceiveMessageV1(r)
+	elapsed := time.Since(start)
+
+	assert.Equal(t, http.StatusOK, status)
+	assert.Less(t, elapsed, 1*time.Second)
+	result := resp.GetResult().(models.ReceiveMessageResult)
+	assert.Len(t, result.Messages, 1)
+	assert.Equal(t, "wake up", string(result.Messages[0].Body))
+}
+
+func TestReceiveMessage_QueueDeletedWhileWaitingV1(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{
+		Name:                          "deleted-queue",
+		ReceiveMessageWaitTimeSeconds: 20,
+	}
+	app.SyncQueues.Queues["deleted-queue"] = q
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		_, r := test.GenerateRequestInfo("POST", "/", models.DeleteQueueRequest{
+			QueueUrl: "http://localhost:4100/queue/deleted-queue",
+		}, true)
+		DeleteQueueV1(r)
+	}()
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+		QueueUrl: "http://localhost:4100/queue/deleted-queue",
+	}, true)
+	start := time.Now()
+	status, _ := ReceiveMessageV1(r)
+	elapsed := time.Since(start)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Less(t, elapsed, 1*time.Second)
+}
+
 func TestReceiveMessageDelaySecondsV1(t *testing.T) {
 	// create a queue
 	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
@@ -191,19 +258,21 @@ func TestReceiveMessageAttributesV1(t *testing.T) {
 	app.SyncQueues.Queues["waiting-queue"] = q
 
 	// send a message
-	q.Messages = append(q.Messages, app.Message{
+	q.Messages.Add(app.Message{
 		MessageBody: []byte("1"),
 		MessageAttributes: map[string]app.MessageAttributeValue{
 			"TestMessageAttrName": {
-				Name:     "TestMessageAttrName",
-				DataType: "String",
-				Value:    "TestMessageAttrValue",
+				DataType:    "String",
+				StringValue: "TestMessageAttrValue",
 			},
 		},
 	})
 
 	// receive message
-	_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{QueueUrl: "http://localhost:4100/queue/waiting-queue"}, true)
+	_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+		QueueUrl:              "http://localhost:4100/queue/waiting-queue",
+		MessageAttributeNames: []string{"All"},
+	}, true)
 	status, resp := ReceiveMessageV1(r)
 	result := resp.GetResult().(models.ReceiveMessageResult)
 
@@ -214,3 +283,274 @@ func TestReceiveMessageAttributesV1(t *testing.T) {
 	assert.Equal(t, "String", result.Messages[0].MessageAttributes[0].Value.DataType)
 	assert.Equal(t, "TestMessageAttrValue", result.Messages[0].MessageAttributes[0].Value.StringValue)
 }
+
+func TestReceiveMessageV1_filters_attributes(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "waiting-queue"}
+	app.SyncQueues.Queues["waiting-queue"] = q
+	attributes := map[string]app.MessageAttributeValue{}
+	for _, name := range []string{"foo.bar", "foo.baz", "food", "other"} {
+		attributes[name] = app.MessageAttributeValue{DataType: "String", StringValue: "value"}
+	}
+	for _, id := range []string{"message-1", "message-2", "message-3"} {
+		q.Messages.Add(app.Message{Uuid: id, MessageBody: []byte("1"), MessageAttributes: attributes})
+	}
+
+	receive := func(request models.ReceiveMessageRequest) *models.ResultMessage {
+		request.QueueUrl = "http://localhost:4100/queue/waiting-queue"
+		_, r := test.GenerateRequestInfo("POST", "/", request, true)
+		_, resp := ReceiveMessageV1(r)
+		return resp.GetResult().(models.ReceiveMessageResult).Messages[0]
+	}
+	names := func(message *models.ResultMessage) ([]string, []string) {
+		attributeNames, messageAttributeNames := []string{}, []string{}
+		for _, attr := range message.Attributes {
+			attributeNames = append(attributeNames, attr.Name)
+		}
+		for _, attr := range message.MessageAttributes {
+			messageAttributeNames = append(messageAttributeNames, attr.Name)
+		}
+		return attributeNames, messageAttributeNames
+	}
+
+	message := receive(models.ReceiveMessageRequest{})
+	attributeNames, messageAttributeNames := names(message)
+	assert.Empty(t, attributeNames)
+	assert.Empty(t, messageAttributeNames)
+	assert.Equal(t, "", message.MD5OfMessageAttributes)
+
+	message = receive(models.ReceiveMessageRequest{
+		AttributeNames:              []string{"SentTimestamp"},
+		MessageSystemAttributeNames: []string{"ApproximateReceiveCount"},
+		MessageAttributeNames:       []string{"foo.*", "other"},
+	})
+	attributeNames, messageAttributeNames = names(message)
+	assert.Equal(t, []string{"ApproximateReceiveCount", "SentTimestamp"}, attributeNames)
+	assert.Equal(t, []string{"foo.bar", "foo.baz", "other"}, messageAttributeNames)
+	assert.NotEqual(t, "", message.MD5OfMessageAttributes)
+
+	message = receive(models.ReceiveMessageRequest{
+		AttributeNames:        []string{"All"},
+		MessageAttributeNames: []string{".*"},
+	})
+	attributeNames, messageAttributeNames = names(message)
+	assert.Contains(t, attributeNames, "SenderId")
+	assert.Contains(t, attributeNames, "ApproximateFirstReceiveTimestamp")
+	assert.Equal(t, []string{"foo.bar", "foo.baz", "food", "other"}, messageAttributeNames)
+}
+
+func TestReceiveMessageV1_visibility_timeout_override(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "waiting-queue", VisibilityTimeout: 30}
+	app.SyncQueues.Queues["waiting-queue"] = q
+	q.Messages.Add(app.Message{Uuid: "message-1", MessageBody: []byte("1")})
+
+	visibilityTimeout := 600
+	_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+		QueueUrl:          "http://localhost:4100/queue/waiting-queue",
+		VisibilityTimeout: &visibilityTimeout,
+	}, true)
+	status, _ := ReceiveMessageV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	received := q.Messages.All()[0]
+	assert.True(t, received.VisibilityTimeout.After(time.Now().Add(9*time.Minute)))
+
+	visibilityTimeout = 43201
+	_, r = test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+		QueueUrl:          "http://localhost:4100/queue/waiting-queue",
+		VisibilityTimeout: &visibilityTimeout,
+	}, true)
+	status, _ = ReceiveMessageV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestReceiveMessageV1_system_attributes(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	sentTime := time.Now().Add(-time.Minute)
+	q := &app.Queue{Name: "waiting-queue.fifo", IsFIFO: true}
+	app.SyncQueues.Queues["waiting-queue.fifo"] = q
+	q.Messages.Add(app.Message{
+		Uuid:                     "message-1",
+		MessageBody:              []byte("1"),
+		GroupID:                  "group-1",
+		DeduplicationID:          "dedup-1",
+		SequenceNumber:           "7",
+		AWSTraceHeader:           "Root=1-5759e988-bd862e3fe1be46a994272793",
+		DeadLetterQueueSourceArn: "arn:aws:sqs:us-east-1:100010001000:source-queue",
+		SentTime:                 sentTime,
+	})
+
+	receive := func() map[string]string {
+		_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+			QueueUrl:       "http://localhost:4100/queue/waiting-queue.fifo",
+			AttributeNames: []string{"All"},
+		}, true)
+		_, resp := ReceiveMessageV1(r)
+		attributes := map[string]string{}
+		for _, attr := range resp.GetResult().(models.ReceiveMessageResult).Messages[0].Attributes {
+			attributes[attr.Name] = attr.Value
+		}
+		return attributes
+	}
+
+	first := receive()
+	assert.Equal(t, fmt.Sprintf("%d", sentTime.UnixMilli()), first["SentTimestamp"])
+	assert.Equal(t, "1", first["ApproximateReceiveCount"])
+	assert.Equal(t, "group-1", first["MessageGroupId"])
+	assert.Equal(t, "dedup-1", first["MessageDeduplicationId"])
+	assert.Equal(t, "7", first["SequenceNumber"])
+	assert.Equal(t, "Root=1-5759e988-bd862e3fe1be46a994272793", first["AWSTraceHeader"])
+	assert.Equal(t, "arn:aws:sqs:us-east-1:100010001000:source-queue", first["DeadLetterQueueSourceArn"])
+
+	// Make it visible again, as an expired visibility timeout would.
+	q.Lock()
+	msg, _ := q.Messages.GetByReceiptHandle(q.Messages.All()[0].ReceiptHandle)
+	msg.ReceiptHandle = ""
+	q.Messages.Update(msg)
+	q.UnlockGroup("group-1")
+	q.Unlock()
+	time.Sleep(5 * time.Millisecond)
+
+	second := receive()
+	assert.Equal(t, "2", second["ApproximateReceiveCount"])
+	assert.Equal(t, first["ApproximateFirstReceiveTimestamp"], second["ApproximateFirstReceiveTimestamp"])
+	assert.Equal(t, first["SentTimestamp"], second["SentTimestamp"])
+}
+
+func TestReceiveMessageV1_fifo_queue_keeps_group_order(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "waiting-queue.fifo", IsFIFO: true, VisibilityTimeout: 30}
+	app.SyncQueues.Queues["waiting-queue.fifo"] = q
+	for i, group := range []string{"a", "b", "a", "a", "b"} {
+		q.Messages.Add(app.Message{
+			Uuid:        fmt.Sprintf("message-%d", i+1),
+			MessageBody: []byte(fmt.Sprintf("%s%d", group, i+1)),
+			GroupID:     group,
+		})
+	}
+
+	receive := func(maxNumberOfMessages int) []string {
+		_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+			QueueUrl:            "http://localhost:4100/queue/waiting-queue.fifo",
+			MaxNumberOfMessages: maxNumberOfMessages,
+		}, true)
+		_, resp := ReceiveMessageV1(r)
+		bodies := []string{}
+		for _, msg := range resp.GetResult().(models.ReceiveMessageResult).Messages {
+			bodies = append(bodies, string(msg.Body))
+		}
+		return bodies
+	}
+
+	// Only the first of group "a" while it is in flight, "b" is free to go.
+	assert.Equal(t, []string{"a1"}, receive(1))
+	assert.Equal(t, []string{"b2", "b5"}, receive(10))
+	assert.Empty(t, receive(10))
+
+	// Deleting "a1" frees up the rest of its group, in order.
+	q.Lock()
+	msg, _ := q.Messages.Get("message-1")
+	receiptHandle := msg.ReceiptHandle
+	q.Unlock()
+	_, r := test.GenerateRequestInfo("POST", "/", models.DeleteMessageRequest{
+		QueueUrl:      "http://localhost:4100/queue/waiting-queue.fifo",
+		ReceiptHandle: receiptHandle,
+	}, true)
+	status, _ := DeleteMessageV1(r)
+	assert.Equal(t, http.StatusOK, status)
+
+	assert.Equal(t, []string{"a3", "a4"}, receive(10))
+	assert.Equal(t, map[string]int{"a": 2, "b": 2}, q.FIFOMessages)
+}
+
+func TestReceiveMessageV1_fifo_queue_receive_request_attempt_id(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "attempt-queue.fifo", IsFIFO: true, VisibilityTimeout: 30}
+	app.SyncQueues.Queues["attempt-queue.fifo"] = q
+	for i, group := range []string{"a", "a", "b"} {
+		q.Messages.Add(app.Message{
+			Uuid:        fmt.Sprintf("message-%d", i+1),
+			MessageBody: []byte(fmt.Sprintf("%s%d", group, i+1)),
+			GroupID:     group,
+		})
+	}
+
+	receive := func(attemptId string) []*models.ResultMessage {
+		_, r := test.GenerateRequestInfo("POST", "/", models.ReceiveMessageRequest{
+			QueueUrl:                "http://localhost:4100/queue/attempt-queue.fifo",
+			MaxNumberOfMessages:     2,
+			ReceiveRequestAttemptId: attemptId,
+		}, true)
+		_, resp := ReceiveMessageV1(r)
+		return resp.GetResult().(models.ReceiveMessageResult).Messages
+	}
+
+	first := receive("attempt-1")
+	assert.Len(t, first, 2)
+	retried := receive("attempt-1")
+	assert.Equal(t, first, retried)
+	msg, _ := q.Messages.Get("message-1")
+	assert.Equal(t, 1, msg.NumberOfReceives)
+
+	// A new attempt gets what is left.
+	other := receive("attempt-2")
+	assert.Len(t, other, 1)
+	assert.Equal(t, "b3", string(other[0].Body))
+
+	// Once a message of the attempt is deleted, a retry is a new receive.
+	_, r := test.GenerateRequestInfo("POST", "/", models.DeleteMessageRequest{
+		QueueUrl:      "http://localhost:4100/queue/attempt-queue.fifo",
+		ReceiptHandle: first[0].ReceiptHandle,
+	}, true)
+	status, _ := DeleteMessageV1(r)
+	assert.Equal(t, http.StatusOK, status)
+
+	assert.Empty(t, receive("attempt-1"))
+	assert.Empty(t, q.ReceiveAttempts["attempt-1"].Messages)
+}
+
+func TestReceiveMessageV1_out_of_range_parameters(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "waiting-queue"}
+	app.SyncQueues.Queues["waiting-queue"] = q
+	q.Messages.Add(app.Message{Uuid: "message-1", MessageBody: []byte("1")})
+
+	cases := map[string]models.ReceiveMessageRequest{
+		"InvalidMaxNumberOfMessages": {QueueUrl: "http://localhost:4100/queue/waiting-queue", MaxNumberOfMessages: 11},
+		"InvalidWaitTimeSeconds":     {QueueUrl: "http://localhost:4100/queue/waiting-queue", WaitTimeSeconds: 21},
+	}
+	for errKey, request := range cases {
+		_, r := test.GenerateRequestInfo("POST", "/", request, true)
+		status, response := ReceiveMessageV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, models.SqsErrors[errKey].Response(), response.(models.ErrorResponse).Result)
+	}
+	assert.True(t, q.Messages.All()[0].VisibilityTimeout.IsZero())
+}
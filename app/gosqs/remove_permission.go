@@ -0,0 +1,82 @@
+package gosqs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+// RemovePermissionV1 removes the statement with the label for its Sid from the queue's policy, the policy
+// goes away along with its last statement.
+//
+//	https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_RemovePermission.html
+func RemovePermissionV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewRemovePermissionRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok || requestBody.Label == "" {
+		log.Error("Invalid Request - RemovePermissionV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+
+	uriSegments := strings.Split(requestBody.QueueUrl, "/")
+	queueName := uriSegments[len(uriSegments)-1]
+
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
+		log.Errorf("Remove Permission: %s, queue does not exist!!!", queueName)
+		return utils.CreateErrorResponseV1("QueueNotFound", true)
+	}
+
+	queue.Lock()
+	defer queue.Unlock()
+	var policy *app.PolicyDocument
+	if queue.Policy != "" {
+		var err error
+		if policy, err = app.ParsePolicy(queue.Policy); err != nil {
+			log.Errorf("Invalid policy of queue %s: %s", queue.Name, err)
+			return utils.CreateErrorResponseV1("InvalidPolicy", true)
+		}
+	}
+	found := false
+	statements := make([]app.PolicyStatement, 0)
+	if policy != nil {
+		for _, statement := range policy.Statement {
+			if statement.Sid == requestBody.Label {
+				found = true
+				continue
+			}
+			statements = append(statements, statement)
+		}
+	}
+	if !found {
+		log.Errorf("Remove Permission: %s, label %s not found", queueName, requestBody.Label)
+		return utils.CreateErrorResponseV1("PermissionLabelNotFound", true)
+	}
+
+	queue.Policy = ""
+	if len(statements) > 0 {
+		policy.Statement = statements
+		text, err := json.Marshal(policy)
+		if err != nil {
+			log.Errorf("Remove Permission: %s, %s", queueName, err)
+			return utils.CreateErrorResponseV1("InvalidPolicy", true)
+		}
+		queue.Policy = string(text)
+	}
+	queue.LastModified = time.Now()
+	persistence.QueueUpdated(queue)
+
+	respStruct := models.RemovePermissionResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
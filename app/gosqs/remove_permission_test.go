@@ -0,0 +1,116 @@
+package gosqs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemovePermissionV1_success(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].Policy = `{"Version":"2012-10-17","Statement":[
+		{"Sid":"keep","Effect":"Allow","Principal":"*","Action":"sqs:SendMessage"},
+		{"Sid":"remove","Effect":"Allow","Principal":"*","Action":"sqs:ReceiveMessage"}
+	]}`
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.RemovePermissionRequest)
+		*v = models.RemovePermissionRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Label:    "remove",
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := RemovePermissionV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.RemovePermissionResponse{Xmlns: models.BASE_XMLNS, Metadata: models.BASE_RESPONSE_METADATA}, response)
+	assert.JSONEq(t,
+		`{"Version":"2012-10-17","Statement":[{"Sid":"keep","Effect":"Allow","Principal":"*","Action":"sqs:SendMessage"}]}`,
+		app.SyncQueues.Queues["unit-queue1"].Policy,
+	)
+}
+
+func TestRemovePermissionV1_last_statement_removes_policy(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].Policy = `{"Statement":[{"Sid":"only","Effect":"Allow","Principal":"*","Action":"sqs:*"}]}`
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.RemovePermissionRequest)
+		*v = models.RemovePermissionRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Label:    "only",
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := RemovePermissionV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "", app.SyncQueues.Queues["unit-queue1"].Policy)
+}
+
+func TestRemovePermissionV1_error_label_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.RemovePermissionRequest)
+		*v = models.RemovePermissionRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Label:    "missing",
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := RemovePermissionV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, models.SqsErrors["PermissionLabelNotFound"].Response(), response.(models.ErrorResponse).Result)
+}
+
+func TestRemovePermissionV1_error_queue_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.RemovePermissionRequest)
+		*v = models.RemovePermissionRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "not-a-queue"),
+			Label:    "any",
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := RemovePermissionV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+}
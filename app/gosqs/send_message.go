@@ -7,6 +7,7 @@ import (
 
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 
 	"github.com/Admiral-Piett/goaws/app/utils"
 
@@ -41,50 +42,61 @@ func SendMessageV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 		queueName = uriSegments[len(uriSegments)-1]
 	}
 
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
 		// Queue does not exist
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
 	}
 
-	if app.SyncQueues.Queues[queueName].MaximumMessageSize > 0 &&
-		len(messageBody) > app.SyncQueues.Queues[queueName].MaximumMessageSize {
-		// Message size is too big
-		return utils.CreateErrorResponseV1("MessageTooBig", true)
+	queue.RLock()
+	maximumMessageSize := queue.MaximumMessageSize
+	delaySecs := queue.DelaySeconds
+	isFIFO := queue.IsFIFO
+	fifoThroughputLimit := queue.FifoThroughputLimit
+	queue.RUnlock()
+
+	if isFIFO && messageGroupID == "" {
+		log.Errorf("Missing MessageGroupId for FIFO queue: %s", queueName)
+		return utils.CreateErrorResponseV1("MissingMessageGroupId", true)
+	}
+
+	if errKey := validateMessage(messageBody, messageAttributes, maximumMessageSize); errKey != "" {
+		log.Errorf("Invalid message for queue: %s, %s", queueName, errKey)
+		return utils.CreateErrorResponseV1(errKey, true)
+	}
+
+	if errKey := validateDelaySeconds(requestBody.DelaySeconds, isFIFO); errKey != "" {
+		return utils.CreateErrorResponseV1(errKey, true)
+	}
+
+	if isFIFO && fifoSendThrottled(queueName, fifoThroughputLimit, messageGroupID) {
+		log.Errorf("Throttled send to FIFO queue: %s", queueName)
+		return utils.CreateErrorResponseV1("Throttling", true)
 	}
 
-	delaySecs := app.SyncQueues.Queues[queueName].DelaySeconds
 	if requestBody.DelaySeconds != 0 {
 		delaySecs = requestBody.DelaySeconds
 	}
 
 	log.Debugf("Putting Message in Queue: [%s]", queueName)
-	msg := app.Message{MessageBody: []byte(messageBody)}
-	if len(messageAttributes) > 0 {
-		oldStyleMessageAttributes := utils.ConvertToOldMessageAttributeValueStructure(messageAttributes)
-		msg.MessageAttributes = oldStyleMessageAttributes
-		msg.MD5OfMessageAttributes = common.HashAttributes(oldStyleMessageAttributes)
-	}
-	msg.MD5OfMessageBody = common.GetMD5Hash(messageBody)
-	msg.Uuid, _ = common.NewUUID()
-	msg.GroupID = messageGroupID
-	msg.DeduplicationID = messageDeduplicationID
-	msg.SentTime = time.Now()
-	msg.DelaySecs = delaySecs
-
-	app.SyncQueues.Lock()
-	fifoSeqNumber := ""
-	if app.SyncQueues.Queues[queueName].IsFIFO {
-		fifoSeqNumber = app.SyncQueues.Queues[queueName].NextSequenceNumber(messageGroupID)
+	msg := newMessage(messageBody, messageAttributes, messageGroupID, requestBody.MessageSystemAttributes["AWSTraceHeader"].StringValue, delaySecs)
+
+	queue.Lock()
+	msg.DeduplicationID = queue.DeduplicationId(messageDeduplicationID, messageBody)
+	if queue.IsFIFO && msg.DeduplicationID == "" {
+		queue.Unlock()
+		log.Errorf("Missing MessageDeduplicationId for FIFO queue: %s", queueName)
+		return utils.CreateErrorResponseV1("MissingDeduplicationId", true)
 	}
 
-	if !app.SyncQueues.Queues[queueName].IsDuplicate(messageDeduplicationID) {
-		app.SyncQueues.Queues[queueName].Messages = append(app.SyncQueues.Queues[queueName].Messages, msg)
+	stored, duplicate := queue.Enqueue(msg)
+	if duplicate {
+		log.Debugf("Message with deduplicationId [%s] in queue [%s] is duplicate ", msg.DeduplicationID, queueName)
 	} else {
-		log.Debugf("Message with deduplicationId [%s] in queue [%s] is duplicate ", messageDeduplicationID, queueName)
+		persistence.MessageUpdated(queue, stored)
 	}
-
-	app.SyncQueues.Queues[queueName].InitDuplicatation(messageDeduplicationID)
-	app.SyncQueues.Unlock()
+	messageId, sequenceNumber := stored.Uuid, stored.SequenceNumber
+	queue.Unlock()
 	log.Infof("%s: Queue: %s, Message: %s\n", time.Now().Format("2006-01-02 15:04:05"), queueName, msg.MessageBody)
 
 	respStruct := models.SendMessageResponse{
@@ -92,11 +104,27 @@ func SendMessageV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
 		Result: models.SendMessageResult{
 			MD5OfMessageAttributes: msg.MD5OfMessageAttributes,
 			MD5OfMessageBody:       msg.MD5OfMessageBody,
-			MessageId:              msg.Uuid,
-			SequenceNumber:         fifoSeqNumber,
+			MessageId:              messageId,
+			SequenceNumber:         sequenceNumber,
 		},
 		Metadata: models.BASE_RESPONSE_METADATA,
 	}
 
 	return http.StatusOK, respStruct
 }
+
+// newMessage builds a message being sent to a queue, with the ID and hashes AWS gives it.
+func newMessage(body string, attributes map[string]app.MessageAttributeValue, groupId string, traceHeader string, delaySecs int) app.Message {
+	msg := app.Message{MessageBody: []byte(body)}
+	if len(attributes) > 0 {
+		msg.MessageAttributes = attributes
+		msg.MD5OfMessageAttributes = common.HashAttributes(attributes)
+	}
+	msg.MD5OfMessageBody = common.GetMD5Hash(body)
+	msg.Uuid, _ = common.NewUUID()
+	msg.GroupID = groupId
+	msg.AWSTraceHeader = traceHeader
+	msg.SentTime = time.Now()
+	msg.DelaySecs = delaySecs
+	return msg
+}
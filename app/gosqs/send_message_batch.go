@@ -9,6 +9,7 @@ import (
 	"github.com/Admiral-Piett/goaws/app/common"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 	"github.com/gorilla/mux"
 	log "github.com/sirupsen/logrus"
@@ -36,62 +37,97 @@ func SendMessageBatchV1(req *http.Request) (int, interfaces.AbstractResponseBody
 		queueName = uriSegments[len(uriSegments)-1]
 	}
 
-	if _, ok := app.SyncQueues.Queues[queueName]; !ok {
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
 	}
 
 	sendEntries := requestBody.Entries
 
-	if len(sendEntries) == 0 {
-		return utils.CreateErrorResponseV1("EmptyBatchRequest", true)
+	ids := make([]string, 0, len(sendEntries))
+	batchSize := 0
+	for _, v := range sendEntries {
+		ids = append(ids, v.Id)
+		batchSize += common.MessageSize(v.MessageBody, v.MessageAttributes)
 	}
-
-	if len(sendEntries) > 10 {
-		return utils.CreateErrorResponseV1("TooManyEntriesInBatchRequest", true)
+	if errKey := common.ValidateBatchEntryIds(ids); errKey != "" {
+		return utils.CreateErrorResponseV1(errKey, true)
 	}
-	ids := map[string]struct{}{}
-	for _, v := range sendEntries {
-		if _, ok := ids[v.Id]; ok {
-			return utils.CreateErrorResponseV1("BatchEntryIdsNotDistinct", true)
+	if batchSize > maxMessageSize {
+		return utils.CreateErrorResponseV1("BatchRequestTooLong", true)
+	}
+
+	queue.RLock()
+	maximumMessageSize := queue.MaximumMessageSize
+	queueDelaySecs := queue.DelaySeconds
+	isFIFO := queue.IsFIFO
+	fifoThroughputLimit := queue.FifoThroughputLimit
+	queue.RUnlock()
+	if isFIFO {
+		groupIds := make([]string, 0, len(sendEntries))
+		for _, v := range sendEntries {
+			groupIds = append(groupIds, v.MessageGroupId)
+		}
+		if fifoSendThrottled(queueName, fifoThroughputLimit, groupIds...) {
+			log.Errorf("Throttled send to FIFO queue: %s", queueName)
+			return utils.CreateErrorResponseV1("Throttling", true)
 		}
-		ids[v.Id] = struct{}{}
 	}
 
 	sentEntries := make([]models.SendMessageBatchResultEntry, 0)
+	failedEntries := make([]models.BatchResultErrorEntry, 0)
+	failEntry := func(id string, errKey string) {
+		er := models.SqsErrors[errKey]
+		failedEntries = append(failedEntries, models.BatchResultErrorEntry{
+			Code:        er.Code,
+			Id:          id,
+			Message:     er.Message,
+			SenderFault: true,
+		})
+	}
 	log.Debug("Putting Message in Queue:", queueName)
 	for _, sendEntry := range sendEntries {
-		msg := app.Message{MessageBody: []byte(sendEntry.MessageBody)}
-		if len(sendEntry.MessageAttributes) > 0 {
-			oldStyleMessageAttributes := utils.ConvertToOldMessageAttributeValueStructure(sendEntry.MessageAttributes)
-			msg.MessageAttributes = oldStyleMessageAttributes
-			msg.MD5OfMessageAttributes = common.HashAttributes(oldStyleMessageAttributes)
+		if isFIFO && sendEntry.MessageGroupId == "" {
+			failEntry(sendEntry.Id, "MissingMessageGroupId")
+			continue
 		}
-		msg.MD5OfMessageBody = common.GetMD5Hash(sendEntry.MessageBody)
-		msg.GroupID = sendEntry.MessageGroupId
-		msg.DeduplicationID = sendEntry.MessageDeduplicationId
-		msg.Uuid, _ = common.NewUUID()
-		msg.SentTime = time.Now()
-		app.SyncQueues.Lock()
-		fifoSeqNumber := ""
-		if app.SyncQueues.Queues[queueName].IsFIFO {
-			fifoSeqNumber = app.SyncQueues.Queues[queueName].NextSequenceNumber(sendEntry.MessageGroupId)
+		if errKey := validateMessage(sendEntry.MessageBody, sendEntry.MessageAttributes, maximumMessageSize); errKey != "" {
+			failEntry(sendEntry.Id, errKey)
+			continue
 		}
-
-		if !app.SyncQueues.Queues[queueName].IsDuplicate(sendEntry.MessageDeduplicationId) {
-			app.SyncQueues.Queues[queueName].Messages = append(app.SyncQueues.Queues[queueName].Messages, msg)
-		} else {
-			log.Debugf("Message with deduplicationId [%s] in queue [%s] is duplicate ", sendEntry.MessageDeduplicationId, queueName)
+		if errKey := validateDelaySeconds(sendEntry.DelaySeconds, isFIFO); errKey != "" {
+			failEntry(sendEntry.Id, errKey)
+			continue
+		}
+		delaySecs := queueDelaySecs
+		if sendEntry.DelaySeconds != 0 {
+			delaySecs = sendEntry.DelaySeconds
 		}
 
-		app.SyncQueues.Queues[queueName].InitDuplicatation(sendEntry.MessageDeduplicationId)
+		msg := newMessage(sendEntry.MessageBody, sendEntry.MessageAttributes, sendEntry.MessageGroupId,
+			sendEntry.MessageSystemAttributes["AWSTraceHeader"].StringValue, delaySecs)
+		queue.Lock()
+		msg.DeduplicationID = queue.DeduplicationId(sendEntry.MessageDeduplicationId, sendEntry.MessageBody)
+		if queue.IsFIFO && msg.DeduplicationID == "" {
+			queue.Unlock()
+			failEntry(sendEntry.Id, "MissingDeduplicationId")
+			continue
+		}
 
-		app.SyncQueues.Unlock()
+		stored, duplicate := queue.Enqueue(msg)
+		if duplicate {
+			log.Debugf("Message with deduplicationId [%s] in queue [%s] is duplicate ", msg.DeduplicationID, queueName)
+		} else {
+			persistence.MessageUpdated(queue, stored)
+		}
+		messageId, sequenceNumber := stored.Uuid, stored.SequenceNumber
+		queue.Unlock()
 		se := models.SendMessageBatchResultEntry{
 			Id:                     sendEntry.Id,
-			MessageId:              msg.Uuid,
+			MessageId:              messageId,
 			MD5OfMessageBody:       msg.MD5OfMessageBody,
 			MD5OfMessageAttributes: msg.MD5OfMessageAttributes,
-			SequenceNumber:         fifoSeqNumber,
+			SequenceNumber:         sequenceNumber,
 		}
 		sentEntries = append(sentEntries, se)
 		log.Infof("%s: Queue: %s, Message: %s\n", time.Now().Format("2006-01-02 15:04:05"), queueName, msg.MessageBody)
@@ -99,7 +135,7 @@ func SendMessageBatchV1(req *http.Request) (int, interfaces.AbstractResponseBody
 
 	respStruct := models.SendMessageBatchResponse{
 		Xmlns:    models.BASE_XMLNS,
-		Result:   models.SendMessageBatchResult{Entry: sentEntries},
+		Result:   models.SendMessageBatchResult{Successful: sentEntries, Failed: failedEntries},
 		Metadata: models.BASE_RESPONSE_METADATA,
 	}
 
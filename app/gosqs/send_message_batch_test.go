@@ -3,6 +3,7 @@ package gosqs
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -33,8 +34,7 @@ func TestSendMessageBatchV1_Success(t *testing.T) {
 				MessageBody: "test%20message%20body%202",
 				MessageAttributes: map[string]models.MessageAttributeValue{
 					"my-attribute-name": {
-						BinaryValue: "base64-encoded-value",
-						DataType:    "hogehoge",
+						DataType:    "String",
 						StringValue: "my-attribute-string-value",
 					},
 				},
@@ -44,13 +44,11 @@ func TestSendMessageBatchV1_Success(t *testing.T) {
 				MessageBody: "test%20message%20body%203",
 				MessageAttributes: map[string]models.MessageAttributeValue{
 					"my-attribute-name-1": {
-						BinaryValue: "base64-encoded-value-1",
-						DataType:    "hogehoge",
+						DataType:    "String",
 						StringValue: "my-attribute-string-value-1",
 					},
 					"my-attribute-name-2": {
-						BinaryValue: "base64-encoded-value-2",
-						DataType:    "hogehoge",
+						DataType:    "String",
 						StringValue: "my-attribute-string-value-2",
 					},
 				},
@@ -71,7 +69,7 @@ func TestSendMessageBatchV1_Success(t *testing.T) {
 	assert.Equal(t, http.StatusOK, status)
 	assert.True(t, ok)
 
-	resultEntry := sendMessageBatchResponse.Result.Entry
+	resultEntry := sendMessageBatchResponse.Result.Successful
 	assert.Equal(t, 3, len(resultEntry))
 	assert.Contains(t, resultEntry[0].Id, "test-msg-with-non-attribute")
 	assert.Contains(t, resultEntry[1].Id, "test-msg-with-single-attirbute")
@@ -92,16 +90,22 @@ func TestSendMessageBatchV1_Success_Fifo_Queue(t *testing.T) {
 	sendMessageRequest_success := models.SendMessageBatchRequest{
 		Entries: []models.SendMessageBatchRequestEntry{
 			{
-				Id:          "test_msg_001",
-				MessageBody: "test%20message%20body%201",
+				Id:                     "test_msg_001",
+				MessageBody:            "test%20message%20body%201",
+				MessageGroupId:         "group-1",
+				MessageDeduplicationId: "dedup-1",
 			},
 			{
-				Id:          "test_msg_002",
-				MessageBody: "test%20message%20body%202",
+				Id:                     "test_msg_002",
+				MessageBody:            "test%20message%20body%202",
+				MessageGroupId:         "group-1",
+				MessageDeduplicationId: "dedup-2",
 			},
 			{
-				Id:          "test_msg_003",
-				MessageBody: "test%20message%20body%203",
+				Id:                     "test_msg_003",
+				MessageBody:            "test%20message%20body%203",
+				MessageGroupId:         "group-1",
+				MessageDeduplicationId: "dedup-3",
 			},
 		},
 		QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "fifo-queue-1"),
@@ -126,7 +130,7 @@ func TestSendMessageBatchV1_Success_Fifo_Queue(t *testing.T) {
 	assert.Equal(t, http.StatusOK, status)
 	assert.True(t, ok)
 
-	resultEntry := sendMessageBatchResponse.Result.Entry
+	resultEntry := sendMessageBatchResponse.Result.Successful
 	assert.Equal(t, 3, len(resultEntry))
 	assert.Contains(t, resultEntry[0].Id, "test_msg_001")
 	assert.NotEmpty(t, resultEntry[0].SequenceNumber)
@@ -136,6 +140,40 @@ func TestSendMessageBatchV1_Success_Fifo_Queue(t *testing.T) {
 	assert.NotEmpty(t, resultEntry[2].SequenceNumber)
 }
 
+func TestSendMessageBatchV1_fifo_queue_duplicates_get_the_original_ids(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.SendMessageBatchRequest)
+		*v = models.SendMessageBatchRequest{
+			Entries: []models.SendMessageBatchRequestEntry{
+				{Id: "first", MessageBody: "body-1", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-1"},
+				{Id: "second", MessageBody: "body-2", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-2"},
+				{Id: "again", MessageBody: "body-1", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-1"},
+			},
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "fifo-queue-1"),
+		}
+		return true
+	}
+	q := &app.Queue{Name: "fifo-queue-1", MaximumMessageSize: 1024, IsFIFO: true}
+	app.SyncQueues.Queues["fifo-queue-1"] = q
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	status, response := SendMessageBatchV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	successful := response.(models.SendMessageBatchResponse).Result.Successful
+	assert.Len(t, successful, 3)
+	assert.Equal(t, successful[0].MessageId, successful[2].MessageId)
+	assert.Equal(t, successful[0].SequenceNumber, successful[2].SequenceNumber)
+	assert.NotEqual(t, successful[0].MessageId, successful[1].MessageId)
+	assert.Equal(t, 2, q.Messages.Len())
+}
+
 func TestSendMessageBatchV1_Error_QueueNotFound(t *testing.T) {
 	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
 	defer func() {
@@ -348,3 +386,148 @@ func TestSendMessageBatchV1_Error_transformer(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, code)
 
 }
+
+func TestSendMessageBatchV1_fifo_queue_entries_without_message_group_id_fail(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "fifo-queue-1.fifo", IsFIFO: true}
+	app.SyncQueues.Queues["fifo-queue-1.fifo"] = q
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageBatchRequest{
+		QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "fifo-queue-1.fifo"),
+		Entries: []models.SendMessageBatchRequestEntry{
+			{Id: "test_msg_001", MessageBody: "1", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-1"},
+			{Id: "test_msg_002", MessageBody: "2", MessageDeduplicationId: "dedup-2"},
+			{Id: "test_msg_003", MessageBody: "3", MessageGroupId: "group-1"},
+		},
+	}, true)
+	status, response := SendMessageBatchV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	result := response.(models.SendMessageBatchResponse).Result
+	assert.Len(t, result.Successful, 1)
+	assert.Equal(t, "test_msg_001", result.Successful[0].Id)
+	assert.Len(t, result.Failed, 2)
+	assert.Equal(t, "test_msg_002", result.Failed[0].Id)
+	assert.Equal(t, "MissingParameter", result.Failed[0].Code)
+	assert.Equal(t, "test_msg_003", result.Failed[1].Id)
+	assert.Equal(t, "InvalidParameterValue", result.Failed[1].Code)
+	assert.Equal(t, 1, q.Messages.Len())
+}
+
+func TestSendMessageBatchV1_entries_delay_and_system_attributes(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "delay-queue", DelaySeconds: 5}
+	app.SyncQueues.Queues["delay-queue"] = q
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageBatchRequest{
+		QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "delay-queue"),
+		Entries: []models.SendMessageBatchRequestEntry{
+			{Id: "test_msg_001", MessageBody: "1"},
+			{
+				Id:           "test_msg_002",
+				MessageBody:  "2",
+				DelaySeconds: 60,
+				MessageSystemAttributes: map[string]models.MessageAttributeValue{
+					"AWSTraceHeader": {DataType: "String", StringValue: "Root=1-5759e988-bd862e3fe1be46a994272793"},
+				},
+			},
+		},
+	}, true)
+	status, response := SendMessageBatchV1(r)
+
+	assert.Equal(t, http.StatusOK, status)
+	result := response.(models.SendMessageBatchResponse).Result
+	assert.Len(t, result.Successful, 2)
+	assert.Empty(t, result.Failed)
+
+	msg, _ := q.Messages.Get(result.Successful[0].MessageId)
+	assert.Equal(t, 5, msg.DelaySecs)
+	assert.Equal(t, "", msg.AWSTraceHeader)
+	msg, _ = q.Messages.Get(result.Successful[1].MessageId)
+	assert.Equal(t, 60, msg.DelaySecs)
+	assert.Equal(t, "Root=1-5759e988-bd862e3fe1be46a994272793", msg.AWSTraceHeader)
+}
+
+func TestSendMessageBatchV1_invalid_entries_fail_on_their_own(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	app.SyncQueues.Queues["small-queue"] = &app.Queue{Name: "small-queue", MaximumMessageSize: 5}
+	app.SyncQueues.Queues["small-queue.fifo"] = &app.Queue{Name: "small-queue.fifo", IsFIFO: true, MaximumMessageSize: 5}
+
+	send := func(queueName string, entries []models.SendMessageBatchRequestEntry) models.SendMessageBatchResult {
+		_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageBatchRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, queueName),
+			Entries:  entries,
+		}, true)
+		status, response := SendMessageBatchV1(r)
+		assert.Equal(t, http.StatusOK, status)
+		return response.(models.SendMessageBatchResponse).Result
+	}
+
+	result := send("small-queue", []models.SendMessageBatchRequestEntry{
+		{Id: "test_msg_001", MessageBody: "1"},
+		{Id: "test_msg_002", MessageBody: "too big"},
+		{Id: "test_msg_003", MessageBody: "3", DelaySeconds: 901},
+		{Id: "test_msg_004", MessageBody: "4", DelaySeconds: -1},
+	})
+	assert.Len(t, result.Successful, 1)
+	assert.Equal(t, "test_msg_001", result.Successful[0].Id)
+	assert.Equal(t, []models.BatchResultErrorEntry{
+		{Code: "InvalidParameterValue", Id: "test_msg_002", Message: models.SqsErrors["MessageTooBig"].Message, SenderFault: true},
+		{Code: "InvalidParameterValue", Id: "test_msg_003", Message: models.SqsErrors["InvalidDelaySeconds"].Message, SenderFault: true},
+		{Code: "InvalidParameterValue", Id: "test_msg_004", Message: models.SqsErrors["InvalidDelaySeconds"].Message, SenderFault: true},
+	}, result.Failed)
+
+	result = send("small-queue.fifo", []models.SendMessageBatchRequestEntry{
+		{Id: "test_msg_001", MessageBody: "1", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-1", DelaySeconds: 10},
+		{Id: "test_msg_002", MessageBody: "2", MessageGroupId: "group-1", MessageDeduplicationId: "dedup-2"},
+	})
+	assert.Len(t, result.Successful, 1)
+	assert.Equal(t, "test_msg_002", result.Successful[0].Id)
+	assert.Len(t, result.Failed, 1)
+	assert.Equal(t, "test_msg_001", result.Failed[0].Id)
+	assert.Equal(t, models.SqsErrors["InvalidFifoDelaySeconds"].Message, result.Failed[0].Message)
+}
+
+func TestSendMessageBatchV1_Error_invalid_entry_id_and_batch_too_long(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "new-queue-1"}
+	app.SyncQueues.Queues["new-queue-1"] = q
+
+	cases := map[string][]models.SendMessageBatchRequestEntry{
+		"InvalidBatchEntryId": {
+			{Id: "test msg 001", MessageBody: "1"},
+		},
+		// Each entry is below the limit, all of them together aren't.
+		"BatchRequestTooLong": {
+			{Id: "test_msg_001", MessageBody: strings.Repeat("1", 200000)},
+			{Id: "test_msg_002", MessageBody: strings.Repeat("2", 200000)},
+		},
+	}
+	for errKey, entries := range cases {
+		_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageBatchRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "new-queue-1"),
+			Entries:  entries,
+		}, true)
+		status, response := SendMessageBatchV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, status)
+		assert.Equal(t, models.SqsErrors[errKey].Response(), response.(models.ErrorResponse).Result)
+	}
+	assert.Equal(t, 0, q.Messages.Len())
+}
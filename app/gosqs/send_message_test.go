@@ -2,8 +2,8 @@ package gosqs
 
 import (
 	"net/http"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/Admiral-Piett/goaws/app/test"
 
@@ -42,8 +42,8 @@ func TestSendMessageV1_Success(t *testing.T) {
 	status, response := SendMessageV1(r)
 
 	// Check the queue
-	assert.Equal(t, 1, len(q.Messages))
-	msg := q.Messages[0]
+	assert.Equal(t, 1, q.Messages.Len())
+	msg := q.Messages.All()[0]
 	assert.Equal(t, "Test Message", string(msg.MessageBody))
 
 	// Check the response
@@ -63,8 +63,10 @@ func TestSendMessageV1_Success_FIFOQueue(t *testing.T) {
 	}()
 
 	sendMessageRequest_success := models.SendMessageRequest{
-		QueueUrl:    "http://localhost:4200/new-queue-1",
-		MessageBody: "Test Message",
+		QueueUrl:               "http://localhost:4200/new-queue-1",
+		MessageBody:            "Test Message",
+		MessageGroupId:         "group-1",
+		MessageDeduplicationId: "dedup-1",
 	}
 	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
 		v := resultingStruct.(*models.SendMessageRequest)
@@ -83,8 +85,8 @@ func TestSendMessageV1_Success_FIFOQueue(t *testing.T) {
 	status, response := SendMessageV1(r)
 
 	// Check the queue
-	assert.Equal(t, 1, len(q.Messages))
-	msg := q.Messages[0]
+	assert.Equal(t, 1, q.Messages.Len())
+	msg := q.Messages.All()[0]
 	assert.Equal(t, "Test Message", string(msg.MessageBody))
 
 	// Check the response
@@ -107,6 +109,7 @@ func TestSendMessageV1_Success_Deduplication(t *testing.T) {
 		QueueUrl:               "http://localhost:4200/new-queue-1",
 		MessageBody:            "Test Message",
 		MessageDeduplicationId: "1",
+		MessageGroupId:         "group-1",
 	}
 	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
 		v := resultingStruct.(*models.SendMessageRequest)
@@ -118,25 +121,31 @@ func TestSendMessageV1_Success_Deduplication(t *testing.T) {
 		Name:               "new-queue-1",
 		MaximumMessageSize: 1024,
 		IsFIFO:             true,
-		EnableDuplicates:   true,
-		Duplicates:         make(map[string]time.Time),
+		Duplicates:         make(map[string]app.Deduplicated),
 	}
 	app.SyncQueues.Queues["new-queue-1"] = q
 
 	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
-	status, _ := SendMessageV1(r)
+	status, response := SendMessageV1(r)
 
 	// Check the queue
-	assert.Equal(t, 1, len(q.Messages))
+	assert.Equal(t, 1, q.Messages.Len())
 	// Check the response
 	assert.Equal(t, http.StatusOK, status)
+	first := response.(models.SendMessageResponse).Result
+	assert.NotEmpty(t, first.MessageId)
+	assert.NotEmpty(t, first.SequenceNumber)
 
 	// Send the same message (have DeduplicationId)
-	status, _ = SendMessageV1(r)
+	status, response = SendMessageV1(r)
 	// Response is "success"
 	assert.Equal(t, http.StatusOK, status)
 	// Only 1 message should be in the queue
-	assert.Equal(t, 1, len(q.Messages))
+	assert.Equal(t, 1, q.Messages.Len())
+	// and the duplicate gets the original's ID and sequence number back
+	second := response.(models.SendMessageResponse).Result
+	assert.Equal(t, first.MessageId, second.MessageId)
+	assert.Equal(t, first.SequenceNumber, second.SequenceNumber)
 }
 
 func TestSendMessageV1_request_transformer_error(t *testing.T) {
@@ -219,3 +228,104 @@ func TestSendMessageV1_POST_QueueNonExistant(t *testing.T) {
 	assert.True(t, ok)
 	assert.Equal(t, "Not Found", errorResponse.Result.Type)
 }
+
+func TestSendMessageV1_fifo_queue_requires_message_group_id(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "new-queue-1.fifo", IsFIFO: true}
+	app.SyncQueues.Queues["new-queue-1.fifo"] = q
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageRequest{
+		QueueUrl:               "http://localhost:4200/new-queue-1.fifo",
+		MessageBody:            "Test Message",
+		MessageDeduplicationId: "dedup-1",
+	}, true)
+	status, response := SendMessageV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "MissingParameter", response.(models.ErrorResponse).Result.Code)
+	assert.Equal(t, 0, q.Messages.Len())
+}
+
+func TestSendMessageV1_fifo_queue_requires_deduplication_id(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "new-queue-1.fifo", IsFIFO: true}
+	app.SyncQueues.Queues["new-queue-1.fifo"] = q
+
+	_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageRequest{
+		QueueUrl:       "http://localhost:4200/new-queue-1.fifo",
+		MessageBody:    "Test Message",
+		MessageGroupId: "group-1",
+	}, true)
+	status, _ := SendMessageV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, 0, q.Messages.Len())
+}
+
+func TestSendMessageV1_fifo_queue_content_based_deduplication(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "new-queue-1.fifo", IsFIFO: true, ContentBasedDeduplication: true}
+	app.SyncQueues.Queues["new-queue-1.fifo"] = q
+
+	sequenceNumbers := []string{}
+	for _, body := range []string{"Test Message", "Test Message", "Other Message"} {
+		_, r := test.GenerateRequestInfo("POST", "/", models.SendMessageRequest{
+			QueueUrl:       "http://localhost:4200/new-queue-1.fifo",
+			MessageBody:    body,
+			MessageGroupId: "group-1",
+		}, true)
+		status, response := SendMessageV1(r)
+		assert.Equal(t, http.StatusOK, status)
+		sequenceNumbers = append(sequenceNumbers, response.(models.SendMessageResponse).Result.SequenceNumber)
+	}
+
+	assert.Equal(t, 2, q.Messages.Len())
+	// The duplicate doesn't use up a sequence number, it gets the original's.
+	assert.Equal(t, sequenceNumbers[0], sequenceNumbers[1])
+	assert.True(t, sequenceNumbers[2] > sequenceNumbers[0])
+}
+
+func TestSendMessageV1_validation_errors(t *testing.T) {
+	app.CurrentEnvironment = fixtures.LOCAL_ENVIRONMENT
+	defer func() {
+		test.ResetApp()
+	}()
+
+	q := &app.Queue{Name: "new-queue-1"}
+	app.SyncQueues.Queues["new-queue-1"] = q
+	app.SyncQueues.Queues["new-queue-1.fifo"] = &app.Queue{Name: "new-queue-1.fifo", IsFIFO: true, ContentBasedDeduplication: true}
+
+	cases := []struct {
+		request models.SendMessageRequest
+		errKey  string
+	}{
+		{models.SendMessageRequest{QueueUrl: "new-queue-1"}, "MissingMessageBody"},
+		{models.SendMessageRequest{QueueUrl: "new-queue-1", MessageBody: "Test\x00Message"}, "InvalidMessageContents"},
+		{models.SendMessageRequest{QueueUrl: "new-queue-1", MessageBody: strings.Repeat("m", 262145)}, "MessageTooBig"},
+		{models.SendMessageRequest{QueueUrl: "new-queue-1", MessageBody: "Test Message", DelaySeconds: 901}, "InvalidDelaySeconds"},
+		{models.SendMessageRequest{QueueUrl: "new-queue-1.fifo", MessageBody: "Test Message", MessageGroupId: "group-1", DelaySeconds: 10}, "InvalidFifoDelaySeconds"},
+		{models.SendMessageRequest{QueueUrl: "new-queue-1", MessageBody: "Test Message", MessageAttributes: map[string]models.MessageAttributeValue{
+			"AWS.attribute": {DataType: "String", StringValue: "value"},
+		}}, "InvalidMessageAttribute"},
+	}
+	for _, c := range cases {
+		_, r := test.GenerateRequestInfo("POST", "/", c.request, true)
+		status, response := SendMessageV1(r)
+
+		assert.Equal(t, http.StatusBadRequest, status, c.errKey)
+		assert.Equal(t, models.SqsErrors[c.errKey].Response(), response.(models.ErrorResponse).Result, c.errKey)
+	}
+	assert.Equal(t, 0, q.Messages.Len())
+}
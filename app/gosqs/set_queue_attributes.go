@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
 	"github.com/Admiral-Piett/goaws/app/utils"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -30,9 +31,7 @@ func SetQueueAttributesV1(req *http.Request) (int, interfaces.AbstractResponseBo
 	queueName := uriSegments[len(uriSegments)-1]
 
 	log.Infof("Set Queue QueueAttributes: %s", queueName)
-	app.SyncQueues.Lock()
-	defer app.SyncQueues.Unlock()
-	queue, ok := app.SyncQueues.Queues[queueName]
+	queue, ok := app.SyncQueues.Get(queueName)
 	if !ok {
 		log.Warningf("Get Queue URL: %s, queue does not exist!!!", queueName)
 		return utils.CreateErrorResponseV1("QueueNotFound", true)
@@ -40,6 +39,9 @@ func SetQueueAttributesV1(req *http.Request) (int, interfaces.AbstractResponseBo
 	if err := setQueueAttributesV1(queue, requestBody.Attributes); err != nil {
 		return utils.CreateErrorResponseV1(err.Error(), true)
 	}
+	queue.RLock()
+	persistence.QueueUpdated(queue)
+	queue.RUnlock()
 
 	respStruct := models.SetQueueAttributesResponse{
 		Xmlns:    models.BASE_XMLNS,
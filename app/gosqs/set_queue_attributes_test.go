@@ -44,7 +44,7 @@ func TestSetQueueAttributesV1_success_multiple_attributes(t *testing.T) {
 	assert.Equal(t, 4, actualQueue.ReceiveMessageWaitTimeSeconds)
 	assert.Equal(t, 1, actualQueue.DelaySeconds)
 	assert.Equal(t, 2, actualQueue.MaximumMessageSize)
-	assert.Equal(t, 3, actualQueue.MessageRetentionPeriod)
+	assert.Equal(t, 60, actualQueue.MessageRetentionPeriod)
 }
 
 func TestSetQueueAttributesV1_success_single_attribute(t *testing.T) {
@@ -0,0 +1,84 @@
+package gosqs
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/utils"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func StartMessageMoveTaskV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewStartMessageMoveTaskRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok {
+		log.Error("Invalid Request - StartMessageMoveTaskV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+	rate := requestBody.MaxNumberOfMessagesPerSecond
+	if rate == 0 {
+		rate = maxMessageMoveRate
+	}
+	if rate < 1 || rate > maxMessageMoveRate {
+		log.Errorf("Invalid MaxNumberOfMessagesPerSecond: %d", rate)
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+
+	source, ok := queueFromArn(requestBody.SourceArn)
+	if !ok {
+		log.Errorf("Start Message Move Task: %s, queue does not exist!!!", requestBody.SourceArn)
+		return utils.CreateErrorResponseV1("ResourceNotFound", true)
+	}
+	if len(deadLetterSourceQueues(source)) == 0 {
+		log.Errorf("Start Message Move Task: %s, is not a dead letter queue", requestBody.SourceArn)
+		return utils.CreateErrorResponseV1("NotADeadLetterQueue", true)
+	}
+	if requestBody.DestinationArn != "" {
+		destination, ok := queueFromArn(requestBody.DestinationArn)
+		if !ok {
+			log.Errorf("Start Message Move Task: %s, queue does not exist!!!", requestBody.DestinationArn)
+			return utils.CreateErrorResponseV1("ResourceNotFound", true)
+		}
+		if destination.IsFIFO != source.IsFIFO {
+			log.Errorf("Start Message Move Task: %s, is not the same type of queue as %s", requestBody.DestinationArn, requestBody.SourceArn)
+			return utils.CreateErrorResponseV1("InvalidMessageMoveDestination", true)
+		}
+	}
+
+	source.RLock()
+	toMove := source.Messages.Len()
+	source.RUnlock()
+
+	task := &messageMoveTask{
+		handle:         newMessageMoveTaskHandle(source.Arn),
+		source:         source,
+		sourceArn:      source.Arn,
+		destinationArn: requestBody.DestinationArn,
+		rate:           rate,
+		status:         messageMoveTaskRunning,
+		toMove:         int64(toMove),
+		started:        time.Now(),
+	}
+
+	messageMoveTasks.Lock()
+	for _, existing := range messageMoveTasks.tasks {
+		if existing.source == source && existing.status == messageMoveTaskRunning {
+			messageMoveTasks.Unlock()
+			log.Errorf("Start Message Move Task: %s, already has a running task", requestBody.SourceArn)
+			return utils.CreateErrorResponseV1("MessageMoveTaskRunning", true)
+		}
+	}
+	addMessageMoveTask(task)
+	messageMoveTasks.Unlock()
+
+	log.Infof("Started moving %d messages out of %s", toMove, source.Name)
+	respStruct := models.StartMessageMoveTaskResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Result:   models.StartMessageMoveTaskResult{TaskHandle: task.handle},
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
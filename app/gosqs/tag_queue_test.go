@@ -0,0 +1,92 @@
+package gosqs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagQueueV1_success_merges_tags(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].Tags = map[string]string{"team": "platform", "env": "dev"}
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.TagQueueRequest)
+		*v = models.TagQueueRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Tags:     map[string]string{"env": "prod", "cost-center": "42"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := TagQueueV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.TagQueueResponse{Xmlns: models.BASE_XMLNS, Metadata: models.BASE_RESPONSE_METADATA}, response)
+	assert.Equal(t, map[string]string{"team": "platform", "env": "prod", "cost-center": "42"}, app.SyncQueues.Queues["unit-queue1"].Tags)
+}
+
+func TestTagQueueV1_error_too_many_tags(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	existing := map[string]string{}
+	for i := 0; i < 49; i++ {
+		existing[fmt.Sprintf("key-%d", i)] = "value"
+	}
+	app.SyncQueues.Queues["unit-queue1"].Tags = existing
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.TagQueueRequest)
+		*v = models.TagQueueRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			Tags:     map[string]string{"key-0": "overwritten", "new-1": "value", "new-2": "value"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := TagQueueV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Len(t, app.SyncQueues.Queues["unit-queue1"].Tags, 49)
+	assert.Equal(t, "value", app.SyncQueues.Queues["unit-queue1"].Tags["key-0"])
+}
+
+func TestTagQueueV1_error_queue_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.TagQueueRequest)
+		*v = models.TagQueueRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "not-a-queue"),
+			Tags:     map[string]string{"team": "platform"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := TagQueueV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,44 @@
+package gosqs
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/persistence"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	log "github.com/sirupsen/logrus"
+)
+
+func UntagQueueV1(req *http.Request) (int, interfaces.AbstractResponseBody) {
+	requestBody := models.NewUntagQueueRequest()
+	ok := utils.REQUEST_TRANSFORMER(requestBody, req, false)
+	if !ok || len(requestBody.TagKeys) == 0 {
+		log.Error("Invalid Request - UntagQueueV1")
+		return utils.CreateErrorResponseV1("InvalidParameterValue", true)
+	}
+
+	uriSegments := strings.Split(requestBody.QueueUrl, "/")
+	queueName := uriSegments[len(uriSegments)-1]
+
+	queue, ok := app.SyncQueues.Get(queueName)
+	if !ok {
+		log.Errorf("Untag Queue: %s, queue does not exist!!!", queueName)
+		return utils.CreateErrorResponseV1("QueueNotFound", true)
+	}
+
+	queue.Lock()
+	for _, key := range requestBody.TagKeys {
+		delete(queue.Tags, key)
+	}
+	persistence.QueueUpdated(queue)
+	queue.Unlock()
+
+	respStruct := models.UntagQueueResponse{
+		Xmlns:    models.BASE_XMLNS,
+		Metadata: models.BASE_RESPONSE_METADATA,
+	}
+	return http.StatusOK, respStruct
+}
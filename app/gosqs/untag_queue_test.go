@@ -0,0 +1,63 @@
+package gosqs
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/fixtures"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/Admiral-Piett/goaws/app/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUntagQueueV1_success(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+	app.SyncQueues.Queues["unit-queue1"].Tags = map[string]string{"team": "platform", "env": "dev"}
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.UntagQueueRequest)
+		*v = models.UntagQueueRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "unit-queue1"),
+			TagKeys:  []string{"env", "missing"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, response := UntagQueueV1(r)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, models.UntagQueueResponse{Xmlns: models.BASE_XMLNS, Metadata: models.BASE_RESPONSE_METADATA}, response)
+	assert.Equal(t, map[string]string{"team": "platform"}, app.SyncQueues.Queues["unit-queue1"].Tags)
+}
+
+func TestUntagQueueV1_error_queue_not_found(t *testing.T) {
+	conf.LoadYamlConfig("../conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		test.ResetApp()
+		utils.REQUEST_TRANSFORMER = utils.TransformRequest
+	}()
+
+	utils.REQUEST_TRANSFORMER = func(resultingStruct interfaces.AbstractRequestBody, req *http.Request, emptyRequestValid bool) (success bool) {
+		v := resultingStruct.(*models.UntagQueueRequest)
+		*v = models.UntagQueueRequest{
+			QueueUrl: fmt.Sprintf("%s/%s", fixtures.BASE_URL, "not-a-queue"),
+			TagKeys:  []string{"env"},
+		}
+		return true
+	}
+
+	_, r := test.GenerateRequestInfo("POST", "/", nil, true)
+	code, _ := UntagQueueV1(r)
+
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,134 @@
+package gosqs
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/common"
+)
+
+// AWS limits of SQS, see https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/quotas-messages.html
+const (
+	maxQueueNameLength            = 80
+	maxDelaySeconds               = 900
+	maxMessageSize                = 262144
+	maxMessageAttributes          = 10
+	maxMessageAttributeNameLength = 256
+	maxVisibilityTimeout          = 43200
+	maxWaitTimeSeconds            = 20
+	maxNumberOfMessagesLimit      = 10
+	maxListQueuesResults          = 1000
+)
+
+// Queue names and permission labels share the same alphabet as batch entry IDs.
+var queueNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,80}$`)
+
+// validQueueName tells if the name is one AWS accepts, a FIFO queue's `.fifo` suffix counts towards its length.
+func validQueueName(name string) bool {
+	if len(name) > maxQueueNameLength {
+		return false
+	}
+	return queueNameRegexp.MatchString(strings.TrimSuffix(name, ".fifo"))
+}
+
+// validMessageCharacters tells if s only has the characters SQS allows in messages:
+// #x9 | #xA | #xD | #x20 to #xD7FF | #xE000 to #xFFFD | #x10000 to #x10FFFF
+func validMessageCharacters(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r == 0x9 || r == 0xA || r == 0xD:
+		case r >= 0x20 && r <= 0xD7FF:
+		case r >= 0xE000 && r <= 0xFFFD:
+		case r >= 0x10000 && r <= 0x10FFFF:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validMessageAttribute tells if the name, data type and value of a message attribute are ones AWS accepts.
+func validMessageAttribute(name string, attr app.MessageAttributeValue) bool {
+	if name == "" || len(name) > maxMessageAttributeNameLength ||
+		strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") || strings.Contains(name, "..") {
+		return false
+	}
+	lowerName := strings.ToLower(name)
+	if strings.HasPrefix(lowerName, "aws.") || strings.HasPrefix(lowerName, "amazon.") {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || r == '-' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	if len(attr.DataType) > maxMessageAttributeNameLength {
+		return false
+	}
+
+	switch attr.BaseType() {
+	case "String", "Number":
+		if attr.StringValue == "" && len(attr.StringListValues) == 0 {
+			return false
+		}
+		if !validMessageCharacters(attr.StringValue) {
+			return false
+		}
+		for _, v := range attr.StringListValues {
+			if !validMessageCharacters(v) {
+				return false
+			}
+		}
+	case "Binary":
+		if attr.BinaryValue == "" && len(attr.BinaryListValues) == 0 {
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// validateMessage returns the key of the error for a message about to be sent to a queue, if there is one.
+// A maximumMessageSize of 0 means the queue has no limit of its own.
+func validateMessage(body string, attributes map[string]app.MessageAttributeValue, maximumMessageSize int) string {
+	if body == "" {
+		return "MissingMessageBody"
+	}
+	if !validMessageCharacters(body) {
+		return "InvalidMessageContents"
+	}
+	if len(attributes) > maxMessageAttributes {
+		return "TooManyMessageAttributes"
+	}
+	for name, attr := range attributes {
+		if !validMessageAttribute(name, attr) {
+			return "InvalidMessageAttribute"
+		}
+	}
+	limit := maxMessageSize
+	if maximumMessageSize > 0 && maximumMessageSize < limit {
+		limit = maximumMessageSize
+	}
+	if common.MessageSize(body, attributes) > limit {
+		return "MessageTooBig"
+	}
+	return ""
+}
+
+// validateDelaySeconds returns the key of the error for the DelaySeconds of a message, if there is one.
+// FIFO queues only have a delay for the whole queue.
+func validateDelaySeconds(delaySeconds int, isFIFO bool) string {
+	if delaySeconds < 0 || delaySeconds > maxDelaySeconds {
+		return "InvalidDelaySeconds"
+	}
+	if isFIFO && delaySeconds != 0 {
+		return "InvalidFifoDelaySeconds"
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package gosqs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidQueueName(t *testing.T) {
+	assert.True(t, validQueueName("my-queue_1"))
+	assert.True(t, validQueueName("my-queue.fifo"))
+	assert.True(t, validQueueName(strings.Repeat("q", 80)))
+	assert.True(t, validQueueName(strings.Repeat("q", 75)+".fifo"))
+
+	assert.False(t, validQueueName(""))
+	assert.False(t, validQueueName(".fifo"))
+	assert.False(t, validQueueName("my queue"))
+	assert.False(t, validQueueName("my.queue"))
+	assert.False(t, validQueueName(strings.Repeat("q", 81)))
+	assert.False(t, validQueueName(strings.Repeat("q", 76)+".fifo"))
+}
+
+func TestValidMessageCharacters(t *testing.T) {
+	assert.True(t, validMessageCharacters("tab\tnew line\ncarriage return\r ünïcödé 😀"))
+	assert.False(t, validMessageCharacters("null\x00"))
+	assert.False(t, validMessageCharacters("bell\x07"))
+	assert.False(t, validMessageCharacters("\xff\xfe"))
+	assert.False(t, validMessageCharacters("￿"))
+}
+
+func TestValidateMessage(t *testing.T) {
+	stringAttribute := app.MessageAttributeValue{DataType: "String", StringValue: "value"}
+
+	assert.Equal(t, "", validateMessage("body", map[string]app.MessageAttributeValue{"attr": stringAttribute}, 0))
+	assert.Equal(t, "", validateMessage("body", map[string]app.MessageAttributeValue{
+		"attr.1": {DataType: "Number.float", StringValue: "1.5"},
+		"attr-2": {DataType: "Binary", BinaryValue: "AQI="},
+		"attr_3": {DataType: "String", StringListValues: []string{"a"}},
+	}, 0))
+	assert.Equal(t, "MissingMessageBody", validateMessage("", nil, 0))
+	assert.Equal(t, "InvalidMessageContents", validateMessage("body\x00", nil, 0))
+
+	tooMany := map[string]app.MessageAttributeValue{}
+	for _, name := range strings.Split("a b c d e f g h i j k", " ") {
+		tooMany[name] = stringAttribute
+	}
+	assert.Equal(t, "TooManyMessageAttributes", validateMessage("body", tooMany, 0))
+
+	for _, name := range []string{"", ".attr", "attr.", "at..tr", "AWS.attr", "amazon.attr", "at tr"} {
+		assert.Equal(t, "InvalidMessageAttribute", validateMessage("body", map[string]app.MessageAttributeValue{name: stringAttribute}, 0), name)
+	}
+	for _, attr := range []app.MessageAttributeValue{
+		{DataType: "Custom", StringValue: "value"},
+		{DataType: "String"},
+		{DataType: "Binary", StringValue: "value"},
+		{DataType: "String", StringValue: "\x00"},
+	} {
+		assert.Equal(t, "InvalidMessageAttribute", validateMessage("body", map[string]app.MessageAttributeValue{"attr": attr}, 0), attr.DataType)
+	}
+
+	// The attributes count towards the size, as does the limit of the queue.
+	assert.Equal(t, "", validateMessage(strings.Repeat("b", maxMessageSize), nil, 0))
+	assert.Equal(t, "MessageTooBig", validateMessage(strings.Repeat("b", maxMessageSize), map[string]app.MessageAttributeValue{"attr": stringAttribute}, 0))
+	assert.Equal(t, "MessageTooBig", validateMessage(strings.Repeat("b", maxMessageSize+1), nil, 0))
+	assert.Equal(t, "MessageTooBig", validateMessage(strings.Repeat("b", 1020), map[string]app.MessageAttributeValue{"attr": stringAttribute}, 1024))
+}
+
+func TestValidateDelaySeconds(t *testing.T) {
+	assert.Equal(t, "", validateDelaySeconds(0, false))
+	assert.Equal(t, "", validateDelaySeconds(maxDelaySeconds, false))
+	assert.Equal(t, "InvalidDelaySeconds", validateDelaySeconds(-1, false))
+	assert.Equal(t, "InvalidDelaySeconds", validateDelaySeconds(maxDelaySeconds+1, true))
+	assert.Equal(t, "", validateDelaySeconds(0, true))
+	assert.Equal(t, "InvalidFifoDelaySeconds", validateDelaySeconds(1, true))
+}
@@ -0,0 +1,356 @@
+package app
+
+import (
+	"container/heap"
+	"sort"
+	"time"
+)
+
+// The heaps a stored message can sit in.  A message is in exactly one of the first three at any time
+// (unless it was handed out by `ExpiredInFlight`), and in the retention heap as long as it has a SentTime.
+const (
+	visibleHeap = iota
+	delayedHeap
+	inFlightHeap
+	retentionHeap
+	heapCount
+)
+
+type storedMessage struct {
+	msg           *Message
+	seq           uint64
+	due           time.Time // when a delayed or in flight message becomes visible
+	receiptHandle string    // the handle the message is indexed by
+	index         [heapCount]int
+}
+
+// messageHeap is a `container/heap` of stored messages that keeps each message's position up to date
+// in `storedMessage.index[slot]`, so that any message can be removed from it in O(log n).
+type messageHeap struct {
+	slot    int
+	less    func(a, b *storedMessage) bool
+	entries []*storedMessage
+}
+
+func (h *messageHeap) Len() int           { return len(h.entries) }
+func (h *messageHeap) Less(i, j int) bool { return h.less(h.entries[i], h.entries[j]) }
+
+func (h *messageHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.entries[i].index[h.slot] = i
+	h.entries[j].index[h.slot] = j
+}
+
+func (h *messageHeap) Push(x interface{}) {
+	e := x.(*storedMessage)
+	e.index[h.slot] = len(h.entries)
+	h.entries = append(h.entries, e)
+}
+
+func (h *messageHeap) Pop() interface{} {
+	n := len(h.entries)
+	e := h.entries[n-1]
+	h.entries[n-1] = nil
+	h.entries = h.entries[:n-1]
+	e.index[h.slot] = -1
+	return e
+}
+
+func (h *messageHeap) peek() *storedMessage {
+	if len(h.entries) == 0 {
+		return nil
+	}
+	return h.entries[0]
+}
+
+func (h *messageHeap) add(e *storedMessage) {
+	heap.Push(h, e)
+}
+
+func (h *messageHeap) pop() *storedMessage {
+	return heap.Pop(h).(*storedMessage)
+}
+
+func (h *messageHeap) remove(e *storedMessage) {
+	if i := e.index[h.slot]; i >= 0 {
+		heap.Remove(h, i)
+	}
+}
+
+// MessageStore holds the messages of a queue.  Messages are indexed by ID and receipt handle, the visible
+// ones are kept in the order they were sent and the delayed and in flight ones in the order they become
+// visible again, so none of the queue operations have to scan every message.
+// The zero value is an empty store.
+// NOTE: `*Message` pointers handed out by the store stay valid until the message is removed, but after
+// changing a message's ReceiptHandle, VisibilityTimeout or delay it must be passed back to `Update`.
+type MessageStore struct {
+	nextSeq  uint64
+	byId     map[string]*storedMessage
+	byHandle map[string]*storedMessage
+	heaps    [heapCount]*messageHeap
+}
+
+// NewMessageStore returns a store holding `messages`, in the given order.
+func NewMessageStore(messages ...Message) MessageStore {
+	s := MessageStore{}
+	for _, msg := range messages {
+		s.Add(msg)
+	}
+	return s
+}
+
+func (s *MessageStore) init() {
+	if s.byId != nil {
+		return
+	}
+	s.byId = make(map[string]*storedMessage)
+	s.byHandle = make(map[string]*storedMessage)
+	bySeq := func(a, b *storedMessage) bool { return a.seq < b.seq }
+	byDue := func(a, b *storedMessage) bool {
+		if a.due.Equal(b.due) {
+			return a.seq < b.seq
+		}
+		return a.due.Before(b.due)
+	}
+	bySent := func(a, b *storedMessage) bool {
+		if a.msg.SentTime.Equal(b.msg.SentTime) {
+			return a.seq < b.seq
+		}
+		return a.msg.SentTime.Before(b.msg.SentTime)
+	}
+	s.heaps[visibleHeap] = &messageHeap{slot: visibleHeap, less: bySeq}
+	s.heaps[delayedHeap] = &messageHeap{slot: delayedHeap, less: byDue}
+	s.heaps[inFlightHeap] = &messageHeap{slot: inFlightHeap, less: byDue}
+	s.heaps[retentionHeap] = &messageHeap{slot: retentionHeap, less: bySent}
+}
+
+// Len is the number of messages in the store, visible or not.
+func (s *MessageStore) Len() int {
+	return len(s.byId)
+}
+
+// Add stores a copy of `msg`, replacing any message with the same ID, and returns the stored message.
+func (s *MessageStore) Add(msg Message) *Message {
+	s.init()
+	if existing, ok := s.byId[msg.Uuid]; ok {
+		s.remove(existing)
+	}
+	e := &storedMessage{msg: &msg, seq: s.nextSeq}
+	for i := range e.index {
+		e.index[i] = -1
+	}
+	s.nextSeq++
+	s.byId[msg.Uuid] = e
+	if !msg.SentTime.IsZero() {
+		s.heaps[retentionHeap].add(e)
+	}
+	s.place(e, time.Now())
+	return e.msg
+}
+
+// Get looks a message up by ID.
+func (s *MessageStore) Get(messageId string) (*Message, bool) {
+	e, ok := s.byId[messageId]
+	if !ok {
+		return nil, false
+	}
+	return e.msg, true
+}
+
+// GetByReceiptHandle looks an in flight message up by its current receipt handle.
+func (s *MessageStore) GetByReceiptHandle(receiptHandle string) (*Message, bool) {
+	e, ok := s.byHandle[receiptHandle]
+	if !ok {
+		return nil, false
+	}
+	return e.msg, true
+}
+
+// Update re-indexes a stored message after its ReceiptHandle, VisibilityTimeout or delay changed.
+func (s *MessageStore) Update(msg *Message) {
+	e, ok := s.byId[msg.Uuid]
+	if !ok || e.msg != msg {
+		return
+	}
+	s.unplace(e)
+	s.place(e, time.Now())
+}
+
+// Remove deletes a message by ID and returns it.
+func (s *MessageStore) Remove(messageId string) (Message, bool) {
+	e, ok := s.byId[messageId]
+	if !ok {
+		return Message{}, false
+	}
+	s.remove(e)
+	return *e.msg, true
+}
+
+// Clear deletes every message.
+func (s *MessageStore) Clear() {
+	*s = MessageStore{}
+}
+
+// Receive hands the visible messages to `fn` in the order they were sent, until `fn` returns false or
+// there are none left.  Every message `fn` gives a ReceiptHandle to is moved in flight, until its
+// VisibilityTimeout.
+func (s *MessageStore) Receive(fn func(*Message) bool) {
+	s.init()
+	s.promote(time.Now())
+	visible := s.heaps[visibleHeap]
+	skipped := []*storedMessage{}
+	for visible.Len() > 0 {
+		e := visible.pop()
+		more := fn(e.msg)
+		if e.msg.ReceiptHandle != "" {
+			s.place(e, time.Now())
+		} else {
+			skipped = append(skipped, e)
+		}
+		if !more {
+			break
+		}
+	}
+	for _, e := range skipped {
+		visible.add(e)
+	}
+}
+
+// ExpiredInFlight takes every in flight message whose VisibilityTimeout is before `now` out of the
+// in flight index and returns them, in the order they expired.
+// NOTE: each of them must then be either passed to `Update` (i.e. with its ReceiptHandle cleared) or
+// removed, until then they can't be received.
+func (s *MessageStore) ExpiredInFlight(now time.Time) []*Message {
+	s.init()
+	expired := []*Message{}
+	inFlight := s.heaps[inFlightHeap]
+	for inFlight.Len() > 0 && inFlight.peek().due.Before(now) {
+		expired = append(expired, inFlight.pop().msg)
+	}
+	return expired
+}
+
+// RemoveSentBefore deletes every message with a SentTime before `cutoff` and returns them, oldest first.
+func (s *MessageStore) RemoveSentBefore(cutoff time.Time) []Message {
+	s.init()
+	removed := []Message{}
+	retention := s.heaps[retentionHeap]
+	for retention.Len() > 0 && retention.peek().msg.SentTime.Before(cutoff) {
+		e := retention.peek()
+		s.remove(e)
+		removed = append(removed, *e.msg)
+	}
+	return removed
+}
+
+// HasVisible reports whether there is a message that could be received at `now`.
+func (s *MessageStore) HasVisible(now time.Time) bool {
+	if s.byId == nil {
+		return false
+	}
+	if s.heaps[visibleHeap].Len() > 0 {
+		return true
+	}
+	next := s.heaps[delayedHeap].peek()
+	return next != nil && !now.Before(next.due)
+}
+
+// NextReveal is when the earliest delayed message becomes visible, zero if there are no delayed messages.
+func (s *MessageStore) NextReveal() time.Time {
+	if s.byId == nil {
+		return time.Time{}
+	}
+	if next := s.heaps[delayedHeap].peek(); next != nil {
+		return next.due
+	}
+	return time.Time{}
+}
+
+// CountInFlight is the number of received messages that haven't been deleted or made visible again.
+func (s *MessageStore) CountInFlight() int {
+	if s.byId == nil {
+		return 0
+	}
+	return s.heaps[inFlightHeap].Len()
+}
+
+// CountDelayed is the number of messages that are still delayed at `now`.
+func (s *MessageStore) CountDelayed(now time.Time) int {
+	if s.byId == nil {
+		return 0
+	}
+	delayed := s.heaps[delayedHeap]
+	// Only the messages that are already due need visiting, they are all at the top of the heap.
+	due := 0
+	pending := []int{0}
+	for len(pending) > 0 {
+		i := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if i >= delayed.Len() || now.Before(delayed.entries[i].due) {
+			continue
+		}
+		due++
+		pending = append(pending, 2*i+1, 2*i+2)
+	}
+	return delayed.Len() - due
+}
+
+// All returns a copy of every message, in the order they were sent.
+func (s *MessageStore) All() []Message {
+	entries := make([]*storedMessage, 0, len(s.byId))
+	for _, e := range s.byId {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].seq < entries[j].seq
+	})
+	messages := make([]Message, 0, len(entries))
+	for _, e := range entries {
+		messages = append(messages, *e.msg)
+	}
+	return messages
+}
+
+// place indexes a message according to its current state.
+func (s *MessageStore) place(e *storedMessage, now time.Time) {
+	m := e.msg
+	if m.ReceiptHandle != "" {
+		e.receiptHandle = m.ReceiptHandle
+		s.byHandle[m.ReceiptHandle] = e
+		e.due = m.VisibilityTimeout
+		s.heaps[inFlightHeap].add(e)
+		return
+	}
+	revealAt := m.SentTime.Add(time.Duration(m.DelaySecs) * time.Second)
+	if m.DelaySecs > 0 && now.Before(revealAt) {
+		e.due = revealAt
+		s.heaps[delayedHeap].add(e)
+		return
+	}
+	s.heaps[visibleHeap].add(e)
+}
+
+// unplace undoes `place`.
+func (s *MessageStore) unplace(e *storedMessage) {
+	s.heaps[visibleHeap].remove(e)
+	s.heaps[delayedHeap].remove(e)
+	s.heaps[inFlightHeap].remove(e)
+	if e.receiptHandle != "" && s.byHandle[e.receiptHandle] == e {
+		delete(s.byHandle, e.receiptHandle)
+	}
+	e.receiptHandle = ""
+}
+
+func (s *MessageStore) remove(e *storedMessage) {
+	s.unplace(e)
+	s.heaps[retentionHeap].remove(e)
+	delete(s.byId, e.msg.Uuid)
+}
+
+// promote makes the delayed messages that are due visible.
+func (s *MessageStore) promote(now time.Time) {
+	delayed := s.heaps[delayedHeap]
+	for delayed.Len() > 0 && !now.Before(delayed.peek().due) {
+		s.heaps[visibleHeap].add(delayed.pop())
+	}
+}
@@ -0,0 +1,133 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receiveAll(s *MessageStore, visibility time.Duration) []string {
+	received := []string{}
+	s.Receive(func(msg *Message) bool {
+		msg.ReceiptHandle = msg.Uuid + "#handle"
+		msg.VisibilityTimeout = time.Now().Add(visibility)
+		received = append(received, msg.Uuid)
+		return true
+	})
+	return received
+}
+
+func TestMessageStore_Receive_in_sent_order(t *testing.T) {
+	s := NewMessageStore()
+	for i := 0; i < 5; i++ {
+		s.Add(Message{Uuid: fmt.Sprintf("id-%d", i)})
+	}
+
+	received := []string{}
+	s.Receive(func(msg *Message) bool {
+		if msg.Uuid == "id-1" {
+			// skipped messages stay visible
+			return true
+		}
+		msg.ReceiptHandle = "handle-" + msg.Uuid
+		received = append(received, msg.Uuid)
+		return len(received) < 2
+	})
+
+	assert.Equal(t, []string{"id-0", "id-2"}, received)
+	assert.Equal(t, 2, s.CountInFlight())
+	assert.Equal(t, []string{"id-1", "id-3", "id-4"}, receiveAll(&s, time.Minute))
+}
+
+func TestMessageStore_lookup_by_receipt_handle(t *testing.T) {
+	s := NewMessageStore(Message{Uuid: "id-1"}, Message{Uuid: "id-2"})
+	receiveAll(&s, time.Minute)
+
+	msg, ok := s.GetByReceiptHandle("id-2#handle")
+	assert.True(t, ok)
+	assert.Equal(t, "id-2", msg.Uuid)
+
+	removed, ok := s.Remove("id-2")
+	assert.True(t, ok)
+	assert.Equal(t, "id-2", removed.Uuid)
+	_, ok = s.GetByReceiptHandle("id-2#handle")
+	assert.False(t, ok)
+	assert.Equal(t, 1, s.Len())
+}
+
+func TestMessageStore_Update_returns_message_to_its_place(t *testing.T) {
+	s := NewMessageStore(Message{Uuid: "id-1"}, Message{Uuid: "id-2"})
+	receiveAll(&s, time.Minute)
+
+	msg, _ := s.GetByReceiptHandle("id-1#handle")
+	msg.ReceiptHandle = ""
+	s.Update(msg)
+
+	_, ok := s.GetByReceiptHandle("id-1#handle")
+	assert.False(t, ok)
+	assert.Equal(t, 1, s.CountInFlight())
+	assert.Equal(t, []string{"id-1"}, receiveAll(&s, time.Minute))
+}
+
+func TestMessageStore_ExpiredInFlight_by_visibility_timeout(t *testing.T) {
+	s := NewMessageStore(Message{Uuid: "id-1"}, Message{Uuid: "id-2"})
+	receiveAll(&s, time.Minute)
+	msg, _ := s.GetByReceiptHandle("id-2#handle")
+	msg.VisibilityTimeout = time.Now().Add(-time.Second)
+	s.Update(msg)
+
+	expired := s.ExpiredInFlight(time.Now())
+
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "id-2", expired[0].Uuid)
+	assert.Empty(t, s.ExpiredInFlight(time.Now()))
+}
+
+func TestMessageStore_delayed_messages(t *testing.T) {
+	now := time.Now()
+	s := NewMessageStore(
+		Message{Uuid: "delayed", SentTime: now, DelaySecs: 10},
+		Message{Uuid: "due", SentTime: now.Add(-20 * time.Second), DelaySecs: 10},
+	)
+
+	assert.True(t, s.HasVisible(now))
+	assert.Equal(t, 1, s.CountDelayed(now))
+	assert.Equal(t, []string{"due"}, receiveAll(&s, time.Minute))
+	assert.False(t, s.HasVisible(now))
+	assert.Equal(t, now.Add(10*time.Second), s.NextReveal())
+	assert.True(t, s.HasVisible(now.Add(10*time.Second)))
+	assert.Equal(t, 0, s.CountDelayed(now.Add(10*time.Second)))
+}
+
+func TestMessageStore_RemoveSentBefore(t *testing.T) {
+	now := time.Now()
+	s := NewMessageStore(
+		Message{Uuid: "new", SentTime: now},
+		Message{Uuid: "old", SentTime: now.Add(-time.Hour)},
+		Message{Uuid: "no-sent-time"},
+	)
+
+	removed := s.RemoveSentBefore(now.Add(-time.Minute))
+
+	assert.Len(t, removed, 1)
+	assert.Equal(t, "old", removed[0].Uuid)
+	all := s.All()
+	assert.Len(t, all, 2)
+	assert.Equal(t, "new", all[0].Uuid)
+	assert.Equal(t, "no-sent-time", all[1].Uuid)
+}
+
+func TestMessageStore_zero_value_and_Clear(t *testing.T) {
+	s := MessageStore{}
+	assert.Equal(t, 0, s.Len())
+	assert.False(t, s.HasVisible(time.Now()))
+	assert.Empty(t, s.All())
+
+	s.Add(Message{Uuid: "id-1"})
+	s.Clear()
+
+	assert.Equal(t, 0, s.Len())
+	assert.Empty(t, receiveAll(&s, time.Minute))
+}
@@ -34,3 +34,31 @@ func (s *StringToInt) UnmarshalJSON(data []byte) error {
 func (s *StringToInt) Int() int {
 	return int(*s)
 }
+
+// StringToBool is the `bool` counterpart of StringToInt, AWS sends boolean attributes as "true"/"false".
+type StringToBool bool
+
+func (s *StringToBool) UnmarshalJSON(data []byte) error {
+	var b bool
+	err := json.Unmarshal(data, &b)
+	if err == nil {
+		*s = StringToBool(b)
+		return nil
+	}
+
+	var str string
+	err = json.Unmarshal(data, &str)
+	if err != nil {
+		return err
+	}
+	tmp, err := strconv.ParseBool(str)
+	if err != nil {
+		return err
+	}
+	*s = StringToBool(tmp)
+	return nil
+}
+
+func (s *StringToBool) Bool() bool {
+	return bool(*s)
+}
@@ -73,3 +73,26 @@ func TestStringToInt_int_returns_int_type(t *testing.T) {
 
 	assert.Equal(t, int(1), s.Int())
 }
+
+func TestStringToBool_unmarshalJSON_bool_and_string(t *testing.T) {
+	result := &struct {
+		Field1 StringToBool  `json:"Field1"`
+		Field2 *StringToBool `json:"Field2"`
+		Field3 *StringToBool `json:"Field3"`
+	}{}
+	err := json.Unmarshal([]byte(`{"Field1": true, "Field2": "false"}`), result)
+
+	assert.Nil(t, err)
+	assert.True(t, result.Field1.Bool())
+	assert.False(t, result.Field2.Bool())
+	assert.Nil(t, result.Field3)
+}
+
+func TestStringToBool_unmarshalJSON_invalid_value_returns_error(t *testing.T) {
+	result := &struct {
+		Field1 StringToBool `json:"Field1"`
+	}{}
+	err := json.Unmarshal([]byte(`{"Field1": "yes please"}`), result)
+
+	assert.Error(t, err)
+}
@@ -4,25 +4,86 @@ import "net/http"
 
 func init() {
 	SqsErrors = map[string]SqsErrorType{
-		"QueueNotFound":                {HttpError: http.StatusBadRequest, Type: "Not Found", Code: "AWS.SimpleQueueService.NonExistentQueue", Message: "The specified queue does not exist for this wsdl version."},
-		"QueueExists":                  {HttpError: http.StatusBadRequest, Type: "Duplicate", Code: "AWS.SimpleQueueService.QueueExists", Message: "The specified queue already exists."},
-		"MessageDoesNotExist":          {HttpError: http.StatusNotFound, Type: "Not Found", Code: "AWS.SimpleQueueService.QueueExists", Message: "The specified queue does not contain the message specified."},
-		"GeneralError":                 {HttpError: http.StatusBadRequest, Type: "GeneralError", Code: "AWS.SimpleQueueService.GeneralError", Message: "General Error."},
-		"TooManyEntriesInBatchRequest": {HttpError: http.StatusBadRequest, Type: "TooManyEntriesInBatchRequest", Code: "AWS.SimpleQueueService.TooManyEntriesInBatchRequest", Message: "Maximum number of entries per request are 10."},
-		"BatchEntryIdsNotDistinct":     {HttpError: http.StatusBadRequest, Type: "BatchEntryIdsNotDistinct", Code: "AWS.SimpleQueueService.BatchEntryIdsNotDistinct", Message: "Two or more batch entries in the request have the same Id."},
-		"EmptyBatchRequest":            {HttpError: http.StatusBadRequest, Type: "EmptyBatchRequest", Code: "AWS.SimpleQueueService.EmptyBatchRequest", Message: "The batch request doesn't contain any entries."},
-		"InvalidVisibilityTimeout":     {HttpError: http.StatusBadRequest, Type: "ValidationError", Code: "AWS.SimpleQueueService.ValidationError", Message: "The visibility timeout is incorrect"},
-		"MessageNotInFlight":           {HttpError: http.StatusBadRequest, Type: "MessageNotInFlight", Code: "AWS.SimpleQueueService.MessageNotInFlight", Message: "The message referred to isn't in flight."},
-		"MessageTooBig":                {HttpError: http.StatusBadRequest, Type: "MessageTooBig", Code: "InvalidParameterValue", Message: "The message size exceeds the limit."},
-		"InvalidParameterValue":        {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "AWS.SimpleQueueService.InvalidParameterValue", Message: "An invalid or out-of-range value was supplied for the input parameter."},
-		"InvalidAttributeValue":        {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "AWS.SimpleQueueService.InvalidAttributeValue", Message: "Invalid Value for the parameter RedrivePolicy."},
+		"QueueNotFound":                        {HttpError: http.StatusBadRequest, Type: "Not Found", Code: "AWS.SimpleQueueService.NonExistentQueue", Message: "The specified queue does not exist for this wsdl version."},
+		"QueueAlreadyExists":                   {HttpError: http.StatusBadRequest, Type: "QueueAlreadyExists", Code: "QueueAlreadyExists", Message: "A queue already exists with the same name and a different value for one or more attributes."},
+		"QueueExists":                          {HttpError: http.StatusBadRequest, Type: "Duplicate", Code: "AWS.SimpleQueueService.QueueExists", Message: "The specified queue already exists."},
+		"MessageDoesNotExist":                  {HttpError: http.StatusNotFound, Type: "Not Found", Code: "AWS.SimpleQueueService.QueueExists", Message: "The specified queue does not contain the message specified."},
+		"GeneralError":                         {HttpError: http.StatusBadRequest, Type: "GeneralError", Code: "AWS.SimpleQueueService.GeneralError", Message: "General Error."},
+		"TooManyEntriesInBatchRequest":         {HttpError: http.StatusBadRequest, Type: "TooManyEntriesInBatchRequest", Code: "AWS.SimpleQueueService.TooManyEntriesInBatchRequest", Message: "Maximum number of entries per request are 10."},
+		"BatchEntryIdsNotDistinct":             {HttpError: http.StatusBadRequest, Type: "BatchEntryIdsNotDistinct", Code: "AWS.SimpleQueueService.BatchEntryIdsNotDistinct", Message: "Two or more batch entries in the request have the same Id."},
+		"EmptyBatchRequest":                    {HttpError: http.StatusBadRequest, Type: "EmptyBatchRequest", Code: "AWS.SimpleQueueService.EmptyBatchRequest", Message: "The batch request doesn't contain any entries."},
+		"InvalidVisibilityTimeout":             {HttpError: http.StatusBadRequest, Type: "ValidationError", Code: "AWS.SimpleQueueService.ValidationError", Message: "The visibility timeout is incorrect"},
+		"MessageNotInFlight":                   {HttpError: http.StatusBadRequest, Type: "MessageNotInFlight", Code: "AWS.SimpleQueueService.MessageNotInFlight", Message: "The message referred to isn't in flight."},
+		"InvalidDelaySeconds":                  {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter DelaySeconds is invalid. Reason: Must be between 0 and 900, if provided."},
+		"InvalidFifoDelaySeconds":              {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter DelaySeconds is invalid. Reason: The request include parameter that is not valid for this queue type."},
+		"MessageTooBig":                        {HttpError: http.StatusBadRequest, Type: "MessageTooBig", Code: "InvalidParameterValue", Message: "The message size exceeds the limit."},
+		"InvalidParameterValue":                {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "AWS.SimpleQueueService.InvalidParameterValue", Message: "An invalid or out-of-range value was supplied for the input parameter."},
+		"InvalidAttributeValue":                {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "AWS.SimpleQueueService.InvalidAttributeValue", Message: "Invalid Value for the parameter RedrivePolicy."},
+		"InvalidRetentionPeriod":               {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "AWS.SimpleQueueService.InvalidAttributeValue", Message: "Invalid value for the parameter MessageRetentionPeriod."},
+		"SignatureDoesNotMatch":                {HttpError: http.StatusForbidden, Type: "Sender", Code: "SignatureDoesNotMatch", Message: "The request signature we calculated does not match the signature you provided."},
+		"InvalidClientTokenId":                 {HttpError: http.StatusForbidden, Type: "Sender", Code: "InvalidClientTokenId", Message: "The security token included in the request is invalid."},
+		"MissingAuthenticationToken":           {HttpError: http.StatusForbidden, Type: "Sender", Code: "MissingAuthenticationToken", Message: "Request is missing Authentication Token"},
+		"SignatureExpired":                     {HttpError: http.StatusForbidden, Type: "Sender", Code: "SignatureDoesNotMatch", Message: "Signature expired: the request time is more than 15 minutes away from the current time."},
+		"TooManyTags":                          {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Too many tags added for queue, a queue can have at most 50 tags."},
+		"ResourceNotFound":                     {HttpError: http.StatusBadRequest, Type: "ResourceNotFoundException", Code: "ResourceNotFoundException", Message: "One or more specified resources don't exist."},
+		"NotADeadLetterQueue":                  {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Source queue must be configured as a Dead Letter Queue."},
+		"InvalidMessageMoveDestination":        {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter DestinationArn is invalid. Reason: Destination queue must be the same type of queue as the source queue."},
+		"MessageMoveTaskRunning":               {HttpError: http.StatusBadRequest, Type: "UnsupportedOperation", Code: "AWS.SimpleQueueService.UnsupportedOperation", Message: "There is already a message move task running for the source queue."},
+		"MessageMoveTaskNotRunning":            {HttpError: http.StatusBadRequest, Type: "UnsupportedOperation", Code: "AWS.SimpleQueueService.UnsupportedOperation", Message: "Only a running message move task can be cancelled."},
+		"InvalidTag":                           {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Tag keys must be 1 to 128 characters and values at most 256 characters."},
+		"InvalidFifoQueueName":                 {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "The name of a FIFO queue can only include alphanumeric characters, hyphens, or underscores, must end with .fifo suffix."},
+		"InvalidAttributeName":                 {HttpError: http.StatusBadRequest, Type: "InvalidAttributeName", Code: "InvalidAttributeName", Message: "The specified attribute only exists for FIFO queues."},
+		"InvalidDeduplicationScope":            {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter DeduplicationScope, it must be queue or messageGroup."},
+		"InvalidFifoThroughputLimit":           {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter FifoThroughputLimit, it must be perQueue or perMessageGroupId, which needs a DeduplicationScope of messageGroup."},
+		"Throttling":                           {HttpError: http.StatusBadRequest, Type: "Sender", Code: "ThrottlingException", Message: "Rate exceeded."},
+		"MissingMessageGroupId":                {HttpError: http.StatusBadRequest, Type: "MissingParameter", Code: "MissingParameter", Message: "The request must contain the parameter MessageGroupId."},
+		"InvalidQueueName":                     {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Can only include alphanumeric characters, hyphens, or underscores. 1 to 80 in length."},
+		"InvalidBatchEntryId":                  {HttpError: http.StatusBadRequest, Type: "InvalidBatchEntryId", Code: "AWS.SimpleQueueService.InvalidBatchEntryId", Message: "A batch entry id can only contain alphanumeric characters, hyphens and underscores. It can be at most 80 letters long."},
+		"BatchRequestTooLong":                  {HttpError: http.StatusBadRequest, Type: "BatchRequestTooLong", Code: "AWS.SimpleQueueService.BatchRequestTooLong", Message: "Batch requests cannot be longer than 262144 bytes."},
+		"MissingMessageBody":                   {HttpError: http.StatusBadRequest, Type: "MissingParameter", Code: "MissingParameter", Message: "The request must contain the parameter MessageBody."},
+		"InvalidMessageContents":               {HttpError: http.StatusBadRequest, Type: "InvalidMessageContents", Code: "InvalidMessageContents", Message: "Invalid binary character in the message. Only #x9 | #xA | #xD | #x20 to #xD7FF | #xE000 to #xFFFD | #x10000 to #x10FFFF are allowed."},
+		"TooManyMessageAttributes":             {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Number of message attributes exceeds the allowed maximum [10]."},
+		"InvalidMessageAttribute":              {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Message attribute names, data types and values must follow the SQS naming rules and must not be empty."},
+		"InvalidMaxNumberOfMessages":           {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter MaxNumberOfMessages is invalid. Reason: Must be between 1 and 10, if provided."},
+		"InvalidWaitTimeSeconds":               {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter WaitTimeSeconds is invalid. Reason: Must be >= 0 and <= 20, if provided."},
+		"InvalidQueueDelaySeconds":             {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter DelaySeconds."},
+		"InvalidMaximumMessageSize":            {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter MaximumMessageSize."},
+		"InvalidReceiveMessageWaitTimeSeconds": {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter ReceiveMessageWaitTimeSeconds."},
+		"InvalidQueueVisibilityTimeout":        {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter VisibilityTimeout."},
+		"InvalidMaxResults":                    {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter MaxResults is invalid. Reason: Must be between 1 and 1000, if provided."},
+		"InvalidNextToken":                     {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter NextToken is invalid."},
+		"InvalidPolicy":                        {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter Policy."},
+		"InvalidRedriveAllowPolicy":            {HttpError: http.StatusBadRequest, Type: "InvalidAttributeValue", Code: "InvalidAttributeValue", Message: "Invalid value for the parameter RedriveAllowPolicy."},
+		"RedriveNotAllowed":                    {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter RedrivePolicy is invalid. Reason: The dead-letter queue's RedriveAllowPolicy doesn't allow this source queue."},
+		"InvalidDeadLetterQueueType":           {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter RedrivePolicy is invalid. Reason: Dead-letter queue must be the same type of queue as the source queue."},
+		"PermissionLabelExists":                {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter Label is invalid. Reason: Already exists."},
+		"PermissionLabelNotFound":              {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "Value for parameter Label is invalid. Reason: can't find label on existing policy."},
+		"AccessDenied":                         {HttpError: http.StatusForbidden, Type: "Sender", Code: "AccessDenied", Message: "Access to the resource is denied."},
+		"MissingDeduplicationId":               {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "InvalidParameterValue", Message: "The queue should either have ContentBasedDeduplication enabled or MessageDeduplicationId provided explicitly."},
 	}
 	SnsErrors = map[string]SnsErrorType{
-		"InvalidParameterValue": {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "AWS.SimpleNotificationService.InvalidParameterValue", Message: "An invalid or out-of-range value was supplied for the input parameter."},
-		"TopicNotFound":         {HttpError: http.StatusBadRequest, Type: "Not Found", Code: "AWS.SimpleNotificationService.NonExistentTopic", Message: "The specified topic does not exist for this wsdl version."},
-		"SubscriptionNotFound":  {HttpError: http.StatusNotFound, Type: "Not Found", Code: "AWS.SimpleNotificationService.NonExistentSubscription", Message: "The specified subscription does not exist for this wsdl version."},
-		"TopicExists":           {HttpError: http.StatusBadRequest, Type: "Duplicate", Code: "AWS.SimpleNotificationService.TopicAlreadyExists", Message: "The specified topic already exists."},
-		"ValidationError":       {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "AWS.SimpleNotificationService.ValidationError", Message: "The input fails to satisfy the constraints specified by an AWS service."},
+		"InvalidParameterValue":        {HttpError: http.StatusBadRequest, Type: "InvalidParameterValue", Code: "AWS.SimpleNotificationService.InvalidParameterValue", Message: "An invalid or out-of-range value was supplied for the input parameter."},
+		"TopicNotFound":                {HttpError: http.StatusBadRequest, Type: "Not Found", Code: "AWS.SimpleNotificationService.NonExistentTopic", Message: "The specified topic does not exist for this wsdl version."},
+		"SubscriptionNotFound":         {HttpError: http.StatusNotFound, Type: "Not Found", Code: "AWS.SimpleNotificationService.NonExistentSubscription", Message: "The specified subscription does not exist for this wsdl version."},
+		"TopicExists":                  {HttpError: http.StatusBadRequest, Type: "Duplicate", Code: "AWS.SimpleNotificationService.TopicAlreadyExists", Message: "The specified topic already exists."},
+		"ValidationError":              {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "AWS.SimpleNotificationService.ValidationError", Message: "The input fails to satisfy the constraints specified by an AWS service."},
+		"SignatureDoesNotMatch":        {HttpError: http.StatusForbidden, Type: "Sender", Code: "SignatureDoesNotMatch", Message: "The request signature we calculated does not match the signature you provided."},
+		"InvalidClientTokenId":         {HttpError: http.StatusForbidden, Type: "Sender", Code: "InvalidClientTokenId", Message: "The security token included in the request is invalid."},
+		"MissingAuthenticationToken":   {HttpError: http.StatusForbidden, Type: "Sender", Code: "MissingAuthenticationToken", Message: "Request is missing Authentication Token"},
+		"SignatureExpired":             {HttpError: http.StatusForbidden, Type: "Sender", Code: "SignatureDoesNotMatch", Message: "Signature expired: the request time is more than 15 minutes away from the current time."},
+		"InvalidNextToken":             {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: NextToken"},
+		"EmptyBatchRequest":            {HttpError: http.StatusBadRequest, Type: "Sender", Code: "EmptyBatchRequest", Message: "The batch request doesn't contain any entries."},
+		"TooManyEntriesInBatchRequest": {HttpError: http.StatusBadRequest, Type: "Sender", Code: "TooManyEntriesInBatchRequest", Message: "The batch request contains more entries than permissible."},
+		"BatchEntryIdsNotDistinct":     {HttpError: http.StatusBadRequest, Type: "Sender", Code: "BatchEntryIdsNotDistinct", Message: "Two or more batch entries in the request have the same Id."},
+		"InvalidBatchEntryId":          {HttpError: http.StatusBadRequest, Type: "Sender", Code: "InvalidBatchEntryId", Message: "The Id of a batch entry in a batch request doesn't abide by the specification."},
+		"BatchRequestTooLong":          {HttpError: http.StatusBadRequest, Type: "Sender", Code: "BatchRequestTooLong", Message: "The length of all the messages put together is more than the limit."},
+		"InvalidMessageStructure":      {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: Message Structure - No default entry in JSON message body"},
+		"MissingMessageGroupId":        {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: The MessageGroupId parameter is required for FIFO topics"},
+		"MissingDeduplicationId":       {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: The topic should either have ContentBasedDeduplication enabled or MessageDeduplicationId provided explicitly"},
+		"EmptyMessage":                 {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: Empty message"},
+		"InvalidFifoTopicName":         {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: Fifo Topic names must end with .fifo and must be made up of only uppercase and lowercase ASCII letters, numbers, underscores, and hyphens, and must be between 1 and 256 characters long."},
+		"InvalidFifoEndpoint":          {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: Endpoint Reason: FIFO SNS Topics currently only support FIFO SQS Queues as subscription endpoints"},
+		"InvalidStandardEndpoint":      {HttpError: http.StatusBadRequest, Type: "InvalidParameter", Code: "InvalidParameter", Message: "Invalid parameter: Endpoint Reason: Please use FIFO SNS topic"},
 	}
 }
 
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"net/url"
+
 	"github.com/Admiral-Piett/goaws/app"
 )
 
@@ -22,13 +24,32 @@ var AVAILABLE_QUEUE_ATTRIBUTES = map[string]bool{
 	"CreatedTimestamp":                      true,
 	"LastModifiedTimestamp":                 true,
 	"QueueArn":                              true,
+	"FifoQueue":                             true,
+	"ContentBasedDeduplication":             true,
+	"DeduplicationScope":                    true,
+	"FifoThroughputLimit":                   true,
 }
 
-// TODO - reconcile this with app.MessageAttributeValue - deal with ConvertToOldMessageAttributeValueStructure
-type MessageAttributeValue struct {
-	BinaryListValues []string `json:"BinaryListValues"` // currently unsupported by AWS
-	BinaryValue      string   `json:"BinaryValue"`
-	DataType         string   `json:"DataType"`
-	StringListValues []string `json:"StringListValues"` // currently unsupported by AWS
-	StringValue      string   `json:"StringValue"`
+// MessageAttributeValue is what requests carry message attributes as, the same model messages keep them in.
+type MessageAttributeValue = app.MessageAttributeValue
+
+// messageAttributeValueFromForm reads a message attribute value of the query protocol, found under `prefix`,
+// e.g. `MessageAttribute.1.Value`.  False if it has no DataType.
+func messageAttributeValueFromForm(values url.Values, prefix string) (MessageAttributeValue, bool) {
+	dataType := values.Get(prefix + ".DataType")
+	if dataType == "" {
+		return MessageAttributeValue{}, false
+	}
+	value := MessageAttributeValue{
+		DataType:    dataType,
+		StringValue: values.Get(prefix + ".StringValue"),
+		BinaryValue: values.Get(prefix + ".BinaryValue"),
+	}
+	if list := listFromForm(values, prefix+".StringListValue"); len(list) > 0 {
+		value.StringListValues = list
+	}
+	if list := listFromForm(values, prefix+".BinaryListValue"); len(list) > 0 {
+		value.BinaryListValues = list
+	}
+	return value, true
 }
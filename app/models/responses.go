@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/base64"
 	"encoding/json"
 
 	"github.com/Admiral-Piett/goaws/app"
@@ -79,21 +80,34 @@ func (r *ResultMessage) MarshalJSON() ([]byte, error) {
 		m.Attributes[attr.Name] = attr.Value
 	}
 
+	// Binary values are kept base64 encoded, which is how the JSON protocol carries the SDK's raw bytes too.
 	for _, attr := range r.MessageAttributes {
-		m.MessageAttributes[attr.Name] = sqstypes.MessageAttributeValue{
-			DataType:    &attr.Value.DataType,
-			StringValue: &attr.Value.StringValue,
-			BinaryValue: []byte(attr.Value.BinaryValue),
+		value := sqstypes.MessageAttributeValue{
+			DataType:         aws.String(attr.Value.DataType),
+			StringListValues: attr.Value.StringListValues,
 		}
+		if attr.Value.StringValue != "" {
+			value.StringValue = aws.String(attr.Value.StringValue)
+		}
+		if attr.Value.BinaryValue != "" {
+			value.BinaryValue, _ = base64.StdEncoding.DecodeString(attr.Value.BinaryValue)
+		}
+		for _, binaryValue := range attr.Value.BinaryListValues {
+			decoded, _ := base64.StdEncoding.DecodeString(binaryValue)
+			value.BinaryListValues = append(value.BinaryListValues, decoded)
+		}
+		m.MessageAttributes[attr.Name] = value
 	}
 
 	return json.Marshal(m)
 }
 
 type ResultMessageAttributeValue struct {
-	DataType    string `xml:"DataType,omitempty"`
-	StringValue string `xml:"StringValue,omitempty"`
-	BinaryValue string `xml:"BinaryValue,omitempty"`
+	DataType         string   `xml:"DataType,omitempty"`
+	StringValue      string   `xml:"StringValue,omitempty"`
+	BinaryValue      string   `xml:"BinaryValue,omitempty"`
+	StringListValues []string `xml:"StringListValue,omitempty"`
+	BinaryListValues []string `xml:"BinaryListValue,omitempty"`
 }
 
 type ResultMessageAttribute struct {
@@ -119,6 +133,30 @@ func (r ChangeMessageVisibilityResult) GetRequestId() string {
 	return r.Metadata.RequestId
 }
 
+/*** Change Message Visibility Batch Response */
+type ChangeMessageVisibilityBatchResultEntry struct {
+	Id string `xml:"Id"`
+}
+
+type ChangeMessageVisibilityBatchResult struct {
+	Successful []ChangeMessageVisibilityBatchResultEntry `xml:"ChangeMessageVisibilityBatchResultEntry"`
+	Failed     []BatchResultErrorEntry                   `xml:"BatchResultErrorEntry,omitempty"`
+}
+
+type ChangeMessageVisibilityBatchResponse struct {
+	Xmlns    string                             `xml:"xmlns,attr,omitempty"`
+	Result   ChangeMessageVisibilityBatchResult `xml:"ChangeMessageVisibilityBatchResult"`
+	Metadata app.ResponseMetadata               `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r ChangeMessageVisibilityBatchResponse) GetResult() interface{} {
+	return r.Result
+}
+
+func (r ChangeMessageVisibilityBatchResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
 /*** Create Queue Response */
 type CreateQueueResult struct {
 	QueueUrl string `json:"QueueUrl" xml:"QueueUrl"`
@@ -142,6 +180,7 @@ func (r CreateQueueResponse) GetRequestId() string {
 type ListQueuesResult struct {
 	// NOTE: the old XML sdks depend on QueueUrl, and the new JSON ones need QueueUrls
 	QueueUrls []string `json:"QueueUrls" xml:"QueueUrl"`
+	NextToken string   `json:"NextToken,omitempty" xml:"NextToken,omitempty"`
 }
 
 type ListQueuesResponse struct {
@@ -259,8 +298,8 @@ type SendMessageBatchResponse struct {
 }
 
 type SendMessageBatchResult struct {
-	Entry []SendMessageBatchResultEntry `xml:"SendMessageBatchResultEntry"`
-	Error []BatchResultErrorEntry       `xml:"BatchResultErrorEntry,omitempty"`
+	Successful []SendMessageBatchResultEntry `xml:"SendMessageBatchResultEntry"`
+	Failed     []BatchResultErrorEntry       `xml:"BatchResultErrorEntry,omitempty"`
 }
 
 func (r SendMessageBatchResponse) GetResult() interface{} {
@@ -414,9 +453,184 @@ func (r DeleteMessageBatchResponse) GetRequestId() string {
 	return r.Metadata.RequestId
 }
 
+/*** Tag Queue Response */
+type TagQueueResponse struct {
+	Xmlns    string               `xml:"xmlns,attr,omitempty"`
+	Metadata app.ResponseMetadata `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r TagQueueResponse) GetResult() interface{} {
+	return nil
+}
+
+func (r TagQueueResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** Untag Queue Response */
+type UntagQueueResponse struct {
+	Xmlns    string               `xml:"xmlns,attr,omitempty"`
+	Metadata app.ResponseMetadata `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r UntagQueueResponse) GetResult() interface{} {
+	return nil
+}
+
+func (r UntagQueueResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** Add Permission Response */
+type AddPermissionResponse struct {
+	Xmlns    string               `xml:"xmlns,attr,omitempty"`
+	Metadata app.ResponseMetadata `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r AddPermissionResponse) GetResult() interface{} {
+	return nil
+}
+
+func (r AddPermissionResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** Remove Permission Response */
+type RemovePermissionResponse struct {
+	Xmlns    string               `xml:"xmlns,attr,omitempty"`
+	Metadata app.ResponseMetadata `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r RemovePermissionResponse) GetResult() interface{} {
+	return nil
+}
+
+func (r RemovePermissionResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** List Queue Tags Response */
+type QueueTag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+type ListQueueTagsResult struct {
+	Tags []QueueTag `xml:"Tag,omitempty"`
+}
+
+type ListQueueTagsResponse struct {
+	Xmlns    string               `xml:"xmlns,attr,omitempty"`
+	Result   ListQueueTagsResult  `xml:"ListQueueTagsResult"`
+	Metadata app.ResponseMetadata `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r ListQueueTagsResponse) GetResult() interface{} {
+	result := map[string]string{}
+	for _, tag := range r.Result.Tags {
+		result[tag.Key] = tag.Value
+	}
+	return map[string]map[string]string{"Tags": result}
+}
+
+func (r ListQueueTagsResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** List Dead Letter Source Queues Response */
+type ListDeadLetterSourceQueuesResult struct {
+	// NOTE: the JSON sdks expect this one in camel case
+	QueueUrls []string `json:"queueUrls" xml:"QueueUrl"`
+	NextToken string   `json:"NextToken,omitempty" xml:"NextToken,omitempty"`
+}
+
+type ListDeadLetterSourceQueuesResponse struct {
+	Xmlns    string                           `xml:"xmlns,attr,omitempty"`
+	Result   ListDeadLetterSourceQueuesResult `xml:"ListDeadLetterSourceQueuesResult"`
+	Metadata app.ResponseMetadata             `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r ListDeadLetterSourceQueuesResponse) GetResult() interface{} {
+	return r.Result
+}
+
+func (r ListDeadLetterSourceQueuesResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** Start Message Move Task Response */
+type StartMessageMoveTaskResult struct {
+	TaskHandle string `json:"TaskHandle" xml:"TaskHandle"`
+}
+
+type StartMessageMoveTaskResponse struct {
+	Xmlns    string                     `xml:"xmlns,attr,omitempty"`
+	Result   StartMessageMoveTaskResult `xml:"StartMessageMoveTaskResult"`
+	Metadata app.ResponseMetadata       `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r StartMessageMoveTaskResponse) GetResult() interface{} {
+	return r.Result
+}
+
+func (r StartMessageMoveTaskResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** List Message Move Tasks Response */
+type MessageMoveTaskResultEntry struct {
+	TaskHandle                        string `json:"TaskHandle,omitempty" xml:"TaskHandle,omitempty"`
+	Status                            string `json:"Status" xml:"Status"`
+	SourceArn                         string `json:"SourceArn" xml:"SourceArn"`
+	DestinationArn                    string `json:"DestinationArn,omitempty" xml:"DestinationArn,omitempty"`
+	MaxNumberOfMessagesPerSecond      int    `json:"MaxNumberOfMessagesPerSecond,omitempty" xml:"MaxNumberOfMessagesPerSecond,omitempty"`
+	ApproximateNumberOfMessagesMoved  int64  `json:"ApproximateNumberOfMessagesMoved" xml:"ApproximateNumberOfMessagesMoved"`
+	ApproximateNumberOfMessagesToMove int64  `json:"ApproximateNumberOfMessagesToMove" xml:"ApproximateNumberOfMessagesToMove"`
+	FailureReason                     string `json:"FailureReason,omitempty" xml:"FailureReason,omitempty"`
+	StartedTimestamp                  int64  `json:"StartedTimestamp" xml:"StartedTimestamp"`
+}
+
+type ListMessageMoveTasksResult struct {
+	Results []MessageMoveTaskResultEntry `json:"Results" xml:"ListMessageMoveTasksResultEntry"`
+}
+
+type ListMessageMoveTasksResponse struct {
+	Xmlns    string                     `xml:"xmlns,attr,omitempty"`
+	Result   ListMessageMoveTasksResult `xml:"ListMessageMoveTasksResult"`
+	Metadata app.ResponseMetadata       `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r ListMessageMoveTasksResponse) GetResult() interface{} {
+	return r.Result
+}
+
+func (r ListMessageMoveTasksResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** Cancel Message Move Task Response */
+type CancelMessageMoveTaskResult struct {
+	ApproximateNumberOfMessagesMoved int64 `json:"ApproximateNumberOfMessagesMoved" xml:"ApproximateNumberOfMessagesMoved"`
+}
+
+type CancelMessageMoveTaskResponse struct {
+	Xmlns    string                      `xml:"xmlns,attr,omitempty"`
+	Result   CancelMessageMoveTaskResult `xml:"CancelMessageMoveTaskResult"`
+	Metadata app.ResponseMetadata        `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r CancelMessageMoveTaskResponse) GetResult() interface{} {
+	return r.Result
+}
+
+func (r CancelMessageMoveTaskResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
 /*** Publish ***/
 type PublishResult struct {
-	MessageId string `xml:"MessageId"`
+	MessageId      string `xml:"MessageId"`
+	SequenceNumber string `xml:"SequenceNumber,omitempty"`
 }
 
 type PublishResponse struct {
@@ -433,6 +647,32 @@ func (r PublishResponse) GetRequestId() string {
 	return r.Metadata.RequestId
 }
 
+/*** Publish Batch ***/
+type PublishBatchResultEntry struct {
+	Id             string `xml:"Id"`
+	MessageId      string `xml:"MessageId"`
+	SequenceNumber string `xml:"SequenceNumber,omitempty"`
+}
+
+type PublishBatchResult struct {
+	Successful []PublishBatchResultEntry `xml:"Successful>member"`
+	Failed     []BatchResultErrorEntry   `xml:"Failed>member"`
+}
+
+type PublishBatchResponse struct {
+	Xmlns    string               `xml:"xmlns,attr"`
+	Result   PublishBatchResult   `xml:"PublishBatchResult"`
+	Metadata app.ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+func (r PublishBatchResponse) GetResult() interface{} {
+	return r.Result
+}
+
+func (r PublishBatchResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
 /*** List Topics ***/
 type TopicArnResult struct {
 	TopicArn  string `xml:"TopicArn"`
@@ -443,7 +683,8 @@ type TopicNamestype struct {
 }
 
 type ListTopicsResult struct {
-	Topics TopicNamestype `xml:"Topics"`
+	Topics    TopicNamestype `xml:"Topics"`
+	NextToken string         `xml:"NextToken,omitempty"`
 }
 
 type ListTopicsResponse struct {
@@ -490,6 +731,7 @@ type TopicSubscriptions struct {
 
 type ListSubscriptionsResult struct {
 	Subscriptions TopicSubscriptions `xml:"Subscriptions"`
+	NextToken     string             `xml:"NextToken,omitempty"`
 }
 
 type ListSubscriptionsResponse struct {
@@ -549,9 +791,51 @@ func (r SetSubscriptionAttributesResponse) GetRequestId() string {
 	return r.Metadata.RequestId
 }
 
+/*** Get Topic Attributes ***/
+type GetTopicAttributesResult struct {
+	Attributes GetTopicAttributes `xml:"Attributes,omitempty"`
+}
+
+type GetTopicAttributes struct {
+	Entries []TopicAttributeEntry `xml:"entry,omitempty"`
+}
+
+type TopicAttributeEntry struct {
+	Key   string `xml:"key,omitempty"`
+	Value string `xml:"value,omitempty"`
+}
+
+type GetTopicAttributesResponse struct {
+	Xmlns    string                   `xml:"xmlns,attr,omitempty"`
+	Result   GetTopicAttributesResult `xml:"GetTopicAttributesResult"`
+	Metadata app.ResponseMetadata     `xml:"ResponseMetadata,omitempty"`
+}
+
+func (r GetTopicAttributesResponse) GetResult() interface{} {
+	return r.Result
+}
+
+func (r GetTopicAttributesResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
+/*** Set Topic Attributes ***/
+type SetTopicAttributesResponse struct {
+	Xmlns    string               `xml:"xmlns,attr"`
+	Metadata app.ResponseMetadata `xml:"ResponseMetadata"`
+}
+
+func (r SetTopicAttributesResponse) GetResult() interface{} {
+	return nil
+}
+
+func (r SetTopicAttributesResponse) GetRequestId() string {
+	return r.Metadata.RequestId
+}
+
 /*** List Subscriptions By Topic Response */
 type ListSubscriptionsByTopicResult struct {
-	NextToken     string             `xml:"NextToken"` // not implemented
+	NextToken     string             `xml:"NextToken"`
 	Subscriptions TopicSubscriptions `xml:"Subscriptions"`
 }
 
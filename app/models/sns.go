@@ -33,87 +33,91 @@ type CreateTopicRequest struct {
 
 // Ref: https://docs.aws.amazon.com/sns/latest/api/API_CreateTopic.html
 type TopicAttributes struct {
-	DeliveryPolicy            map[string]interface{} `json:"DeliveryPolicy"`            // NOTE: not implemented
-	DisplayName               string                 `json:"DisplayName"`               // NOTE: not implemented
-	FifoTopic                 bool                   `json:"FifoTopic"`                 // NOTE: not implemented
-	Policy                    map[string]interface{} `json:"Policy"`                    // NOTE: not implemented
-	SignatureVersion          StringToInt            `json:"SignatureVersion"`          // NOTE: not implemented
-	TracingConfig             string                 `json:"TracingConfig"`             // NOTE: not implemented
-	KmsMasterKeyId            string                 `json:"KmsMasterKeyId"`            // NOTE: not implemented
-	ArchivePolicy             map[string]interface{} `json:"ArchivePolicy"`             // NOTE: not implemented
-	BeginningArchiveTime      string                 `json:"BeginningArchiveTime"`      // NOTE: not implemented
-	ContentBasedDeduplication bool                   `json:"ContentBasedDeduplication"` // NOTE: not implemented
+	DeliveryPolicy            map[string]interface{} `json:"DeliveryPolicy"`
+	DisplayName               string                 `json:"DisplayName"`
+	FifoTopic                 bool                   `json:"FifoTopic"`
+	Policy                    map[string]interface{} `json:"Policy"`
+	SignatureVersion          StringToInt            `json:"SignatureVersion"`
+	TracingConfig             string                 `json:"TracingConfig"`
+	KmsMasterKeyId            string                 `json:"KmsMasterKeyId"`
+	ArchivePolicy             map[string]interface{} `json:"ArchivePolicy"`
+	BeginningArchiveTime      string                 `json:"BeginningArchiveTime"` // NOTE: not implemented
+	ContentBasedDeduplication bool                   `json:"ContentBasedDeduplication"`
 }
 
+// SetAttributesFromForm reads the attributes the way the SDKs send the map, as `Attributes.entry.N.key`
+// and `Attributes.entry.N.value`, as well as the `Attribute.N.Name` and `Attribute.N.Value` pairs.
 func (r *CreateTopicRequest) SetAttributesFromForm(values url.Values) {
-	for i := 1; true; i++ {
-		nameKey := fmt.Sprintf("Attribute.%d.Name", i)
-		attrName := values.Get(nameKey)
-		if attrName == "" {
-			break
-		}
-
-		valueKey := fmt.Sprintf("Attribute.%d.Value", i)
-		attrValue := values.Get(valueKey)
-		if attrValue == "" {
-			continue
-		}
-
-		switch attrName {
-		case "DeliveryPolicy":
-			var tmp map[string]interface{}
-			err := json.Unmarshal([]byte(attrValue), &tmp)
-			if err != nil {
-				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
-				continue
+	for _, keys := range [][2]string{{"Attribute.%d.Name", "Attribute.%d.Value"}, {"Attributes.entry.%d.key", "Attributes.entry.%d.value"}} {
+		for i := 1; true; i++ {
+			attrName := values.Get(fmt.Sprintf(keys[0], i))
+			if attrName == "" {
+				break
 			}
-			r.Attributes.DeliveryPolicy = tmp
-		case "DisplayName":
-			r.Attributes.DisplayName = attrValue
-		case "FifoTopic":
-			tmp, err := strconv.ParseBool(attrValue)
-			if err != nil {
-				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+			attrValue := values.Get(fmt.Sprintf(keys[1], i))
+			if attrValue == "" {
 				continue
 			}
-			r.Attributes.FifoTopic = tmp
-		case "Policy":
-			var tmp map[string]interface{}
-			err := json.Unmarshal([]byte(attrValue), &tmp)
-			if err != nil {
-				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
-				continue
-			}
-			r.Attributes.Policy = tmp
-		case "SignatureVersion":
-			tmp, err := strconv.Atoi(attrValue)
-			if err != nil {
-				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
-				continue
-			}
-			r.Attributes.SignatureVersion = StringToInt(tmp)
-		case "TracingConfig":
-			r.Attributes.TracingConfig = attrValue
-		case "KmsMasterKeyId":
-			r.Attributes.KmsMasterKeyId = attrValue
-		case "ArchivePolicy":
-			var tmp map[string]interface{}
-			err := json.Unmarshal([]byte(attrValue), &tmp)
-			if err != nil {
-				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
-				continue
-			}
-			r.Attributes.ArchivePolicy = tmp
-		case "BeginningArchiveTime":
-			r.Attributes.BeginningArchiveTime = attrValue
-		case "ContentBasedDeduplication":
-			tmp, err := strconv.ParseBool(attrValue)
-			if err != nil {
-				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
-				continue
-			}
-			r.Attributes.ContentBasedDeduplication = tmp
+			r.setAttribute(attrName, attrValue)
+		}
+	}
+}
+
+func (r *CreateTopicRequest) setAttribute(attrName string, attrValue string) {
+	switch attrName {
+	case "DeliveryPolicy":
+		var tmp map[string]interface{}
+		err := json.Unmarshal([]byte(attrValue), &tmp)
+		if err != nil {
+			log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+			return
+		}
+		r.Attributes.DeliveryPolicy = tmp
+	case "DisplayName":
+		r.Attributes.DisplayName = attrValue
+	case "FifoTopic":
+		tmp, err := strconv.ParseBool(attrValue)
+		if err != nil {
+			log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+			return
+		}
+		r.Attributes.FifoTopic = tmp
+	case "Policy":
+		var tmp map[string]interface{}
+		err := json.Unmarshal([]byte(attrValue), &tmp)
+		if err != nil {
+			log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+			return
 		}
+		r.Attributes.Policy = tmp
+	case "SignatureVersion":
+		tmp, err := strconv.Atoi(attrValue)
+		if err != nil {
+			log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+			return
+		}
+		r.Attributes.SignatureVersion = StringToInt(tmp)
+	case "TracingConfig":
+		r.Attributes.TracingConfig = attrValue
+	case "KmsMasterKeyId":
+		r.Attributes.KmsMasterKeyId = attrValue
+	case "ArchivePolicy":
+		var tmp map[string]interface{}
+		err := json.Unmarshal([]byte(attrValue), &tmp)
+		if err != nil {
+			log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+			return
+		}
+		r.Attributes.ArchivePolicy = tmp
+	case "BeginningArchiveTime":
+		r.Attributes.BeginningArchiveTime = attrValue
+	case "ContentBasedDeduplication":
+		tmp, err := strconv.ParseBool(attrValue)
+		if err != nil {
+			log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+			return
+		}
+		r.Attributes.ContentBasedDeduplication = tmp
 	}
 }
 
@@ -190,8 +194,8 @@ func NewPublishRequest() *PublishRequest {
 type PublishRequest struct {
 	Message                string                           `json:"Message" schema:"Message"`
 	MessageAttributes      map[string]MessageAttributeValue `json:"MessageAttributes" schema:"MessageAttributes"`
-	MessageDeduplicationId string                           `json:"MessageDeduplicationId" schema:"MessageDeduplicationId"` // Not implemented
-	MessageGroupId         string                           `json:"MessageGroupId" schema:"MessageGroupId"`                 // Not implemented
+	MessageDeduplicationId string                           `json:"MessageDeduplicationId" schema:"MessageDeduplicationId"`
+	MessageGroupId         string                           `json:"MessageGroupId" schema:"MessageGroupId"`
 	MessageStructure       string                           `json:"MessageStructure" schema:"MessageStructure"`
 	PhoneNumber            string                           `json:"PhoneNumber" schema:"PhoneNumber"` // Not implemented
 	Subject                string                           `json:"Subject" schema:"Subject"`
@@ -207,21 +211,73 @@ func (r *PublishRequest) SetAttributesFromForm(values url.Values) {
 			break
 		}
 
-		dataTypeKey := fmt.Sprintf("MessageAttributes.entry.%d.Value.DataType", i)
-		dataType := values.Get(dataTypeKey)
-		if dataType == "" {
+		value, ok := messageAttributeValueFromForm(values, fmt.Sprintf("MessageAttributes.entry.%d.Value", i))
+		if !ok {
 			log.Warnf("DataType of MessageAttribute %s is missing, MD5 checksum will most probably be wrong!\n", name)
 			continue
 		}
+		if r.MessageAttributes == nil {
+			r.MessageAttributes = make(map[string]MessageAttributeValue)
+		}
+		r.MessageAttributes[name] = value
+	}
+}
+
+// Publish Batch V1
+
+func NewPublishBatchRequest() *PublishBatchRequest {
+	return &PublishBatchRequest{}
+}
+
+// Ref: https://docs.aws.amazon.com/sns/latest/api/API_PublishBatch.html
+type PublishBatchRequest struct {
+	TopicArn                   string                     `json:"TopicArn" schema:"TopicArn"`
+	PublishBatchRequestEntries []PublishBatchRequestEntry `json:"PublishBatchRequestEntries" schema:"-"`
+}
 
-		stringValue := values.Get(fmt.Sprintf("MessageAttributes.entry.%d.Value.StringValue", i))
-		binaryValue := values.Get(fmt.Sprintf("MessageAttributes.entry.%d.Value.BinaryValue", i))
+type PublishBatchRequestEntry struct {
+	Id                     string                           `json:"Id"`
+	Message                string                           `json:"Message"`
+	MessageAttributes      map[string]MessageAttributeValue `json:"MessageAttributes"`
+	MessageDeduplicationId string                           `json:"MessageDeduplicationId"`
+	MessageGroupId         string                           `json:"MessageGroupId"`
+	MessageStructure       string                           `json:"MessageStructure"`
+	Subject                string                           `json:"Subject"`
+}
 
-		r.MessageAttributes[name] = MessageAttributeValue{
-			DataType:    dataType,
-			StringValue: stringValue,
-			BinaryValue: binaryValue,
+// SetAttributesFromForm reads the entries, which the query protocol lists as
+// `PublishBatchRequestEntries.member.N.Id`, with their attributes as `...member.N.MessageAttributes.entry.M.Name`.
+func (r *PublishBatchRequest) SetAttributesFromForm(values url.Values) {
+	for i := 1; true; i++ {
+		prefix := fmt.Sprintf("PublishBatchRequestEntries.member.%d.", i)
+		entry := PublishBatchRequestEntry{
+			Id:                     values.Get(prefix + "Id"),
+			Message:                values.Get(prefix + "Message"),
+			MessageDeduplicationId: values.Get(prefix + "MessageDeduplicationId"),
+			MessageGroupId:         values.Get(prefix + "MessageGroupId"),
+			MessageStructure:       values.Get(prefix + "MessageStructure"),
+			Subject:                values.Get(prefix + "Subject"),
+		}
+		if entry.Id == "" && entry.Message == "" {
+			break
 		}
+
+		for j := 1; true; j++ {
+			name := values.Get(fmt.Sprintf("%sMessageAttributes.entry.%d.Name", prefix, j))
+			if name == "" {
+				break
+			}
+			value, ok := messageAttributeValueFromForm(values, fmt.Sprintf("%sMessageAttributes.entry.%d.Value", prefix, j))
+			if !ok {
+				log.Warnf("DataType of MessageAttribute %s is missing, MD5 checksum will most probably be wrong!\n", name)
+				continue
+			}
+			if entry.MessageAttributes == nil {
+				entry.MessageAttributes = make(map[string]MessageAttributeValue)
+			}
+			entry.MessageAttributes[name] = value
+		}
+		r.PublishBatchRequestEntries = append(r.PublishBatchRequestEntries, entry)
 	}
 }
 
@@ -232,7 +288,7 @@ func NewListTopicsRequest() *ListTopicsRequest {
 }
 
 type ListTopicsRequest struct {
-	NextToken string `json:"NextToken" schema:"NextToken"` // not implemented
+	NextToken string `json:"NextToken" schema:"NextToken"`
 }
 
 func (r *ListTopicsRequest) SetAttributesFromForm(values url.Values) {}
@@ -256,7 +312,7 @@ func NewListSubscriptionsRequest() *ListSubscriptionsRequest {
 }
 
 type ListSubscriptionsRequest struct {
-	NextToken string `json:"NextToken" schema:"NextToken"` // not implemented
+	NextToken string `json:"NextToken" schema:"NextToken"`
 }
 
 func (r *ListSubscriptionsRequest) SetAttributesFromForm(values url.Values) {}
@@ -287,6 +343,33 @@ type SetSubscriptionAttributesRequest struct {
 
 func (r *SetSubscriptionAttributesRequest) SetAttributesFromForm(values url.Values) {}
 
+// Get Topic Attributes V1
+
+func NewGetTopicAttributesRequest() *GetTopicAttributesRequest {
+	return &GetTopicAttributesRequest{}
+}
+
+type GetTopicAttributesRequest struct {
+	TopicArn string `json:"TopicArn" schema:"TopicArn"`
+}
+
+func (r *GetTopicAttributesRequest) SetAttributesFromForm(values url.Values) {}
+
+// Set Topic Attributes V1
+
+func NewSetTopicAttributesRequest() *SetTopicAttributesRequest {
+	return &SetTopicAttributesRequest{}
+}
+
+// Ref: https://docs.aws.amazon.com/sns/latest/api/API_SetTopicAttributes.html
+type SetTopicAttributesRequest struct {
+	TopicArn       string `json:"TopicArn" schema:"TopicArn"`
+	AttributeName  string `json:"AttributeName" schema:"AttributeName"`
+	AttributeValue string `json:"AttributeValue" schema:"AttributeValue"`
+}
+
+func (r *SetTopicAttributesRequest) SetAttributesFromForm(values url.Values) {}
+
 // List Subscriptions By Topic
 
 func NewListSubscriptionsByTopicRequest() *ListSubscriptionsByTopicRequest {
@@ -294,7 +377,7 @@ func NewListSubscriptionsByTopicRequest() *ListSubscriptionsByTopicRequest {
 }
 
 type ListSubscriptionsByTopicRequest struct {
-	NextToken string `json:"NextToken" schema:"NextToken"` // not implemented
+	NextToken string `json:"NextToken" schema:"NextToken"`
 	TopicArn  string `json:"TopicArn" schema:"TopicArn"`
 }
 
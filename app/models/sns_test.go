@@ -70,6 +70,71 @@ func TestCreateTopicRequest_SetAttributesFromForm_success(t *testing.T) {
 	assert.Equal(t, true, ctr.Attributes.ContentBasedDeduplication)
 }
 
+func TestCreateTopicRequest_SetAttributesFromForm_entries(t *testing.T) {
+	form := url.Values{}
+	form.Add("Attributes.entry.1.key", "DisplayName")
+	form.Add("Attributes.entry.1.value", "Foo")
+	form.Add("Attributes.entry.2.key", "FifoTopic")
+	form.Add("Attributes.entry.2.value", "true")
+	form.Add("Attributes.entry.3.key", "Policy")
+	form.Add("Attributes.entry.3.value", "{\"i-am\":\"the-policy\"}")
+
+	ctr := NewCreateTopicRequest()
+	ctr.SetAttributesFromForm(form)
+
+	assert.Equal(t, "Foo", ctr.Attributes.DisplayName)
+	assert.Equal(t, true, ctr.Attributes.FifoTopic)
+	assert.Equal(t, "the-policy", ctr.Attributes.Policy["i-am"])
+	assert.Equal(t, StringToInt(1), ctr.Attributes.SignatureVersion)
+}
+
+func TestPublishRequest_SetAttributesFromForm_success(t *testing.T) {
+	form := url.Values{}
+	form.Add("MessageAttributes.entry.1.Name", "trace")
+	form.Add("MessageAttributes.entry.1.Value.DataType", "String")
+	form.Add("MessageAttributes.entry.1.Value.StringValue", "abc")
+
+	pr := NewPublishRequest()
+	pr.SetAttributesFromForm(form)
+
+	assert.Equal(t, map[string]MessageAttributeValue{"trace": {DataType: "String", StringValue: "abc"}}, pr.MessageAttributes)
+}
+
+func TestPublishBatchRequest_SetAttributesFromForm_success(t *testing.T) {
+	form := url.Values{}
+	form.Add("PublishBatchRequestEntries.member.1.Id", "first")
+	form.Add("PublishBatchRequestEntries.member.1.Message", "message-1")
+	form.Add("PublishBatchRequestEntries.member.1.Subject", "subject-1")
+	form.Add("PublishBatchRequestEntries.member.1.MessageAttributes.entry.1.Name", "trace")
+	form.Add("PublishBatchRequestEntries.member.1.MessageAttributes.entry.1.Value.DataType", "String")
+	form.Add("PublishBatchRequestEntries.member.1.MessageAttributes.entry.1.Value.StringValue", "abc")
+	form.Add("PublishBatchRequestEntries.member.2.Id", "second")
+	form.Add("PublishBatchRequestEntries.member.2.Message", "{\"default\":\"message-2\"}")
+	form.Add("PublishBatchRequestEntries.member.2.MessageStructure", "json")
+	form.Add("PublishBatchRequestEntries.member.2.MessageGroupId", "group-1")
+	form.Add("PublishBatchRequestEntries.member.2.MessageDeduplicationId", "dedup-1")
+	form.Add("PublishBatchRequestEntries.member.4.Id", "not-consecutive")
+
+	pbr := NewPublishBatchRequest()
+	pbr.SetAttributesFromForm(form)
+
+	assert.Equal(t, []PublishBatchRequestEntry{
+		{
+			Id:                "first",
+			Message:           "message-1",
+			Subject:           "subject-1",
+			MessageAttributes: map[string]MessageAttributeValue{"trace": {DataType: "String", StringValue: "abc"}},
+		},
+		{
+			Id:                     "second",
+			Message:                "{\"default\":\"message-2\"}",
+			MessageStructure:       "json",
+			MessageGroupId:         "group-1",
+			MessageDeduplicationId: "dedup-1",
+		},
+	}, pbr.PublishBatchRequestEntries)
+}
+
 func TestSubscribeRequest_SetAttributesFromForm_success(t *testing.T) {
 	form := url.Values{}
 	form.Add("Attributes.entry.1.key", "RawMessageDelivery")
@@ -16,6 +16,26 @@ type CreateQueueRequest struct {
 	Attributes QueueAttributes   `json:"Attributes" schema:"Attribute"`
 	Tags       map[string]string `json:"Tags" schema:"Tags"`
 	Version    string            `json:"Version" schema:"Version"`
+	// GivenAttributes names the attributes the request actually had, to tell them from the defaults.
+	GivenAttributes []string `json:"-" schema:"-"`
+}
+
+func (r *CreateQueueRequest) UnmarshalJSON(data []byte) error {
+	// The alias doesn't have this method, so it decodes as usual, on top of the defaults.
+	type createQueueRequest CreateQueueRequest
+	if err := json.Unmarshal(data, (*createQueueRequest)(r)); err != nil {
+		return err
+	}
+	var given struct {
+		Attributes map[string]json.RawMessage `json:"Attributes"`
+	}
+	if err := json.Unmarshal(data, &given); err != nil {
+		return err
+	}
+	for name := range given.Attributes {
+		r.GivenAttributes = append(r.GivenAttributes, name)
+	}
+	return nil
 }
 
 // TODO - is there an easier way to do this?  Similar to the StringToInt type?
@@ -55,8 +75,7 @@ func (r *CreateQueueRequest) SetAttributesFromForm(values url.Values) {
 			}
 			r.Attributes.MessageRetentionPeriod = StringToInt(tmp)
 		case "Policy":
-			var tmp map[string]interface{}
-			err := json.Unmarshal([]byte(attrValue), &tmp)
+			tmp, err := policyFromString(attrValue)
 			if err != nil {
 				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
 				continue
@@ -108,14 +127,37 @@ func (r *CreateQueueRequest) SetAttributesFromForm(values url.Values) {
 			tmp.DeadLetterTargetArn = decodedPolicy.DeadLetterTargetArn
 			r.Attributes.RedrivePolicy = tmp
 		case "RedriveAllowPolicy":
-			var tmp map[string]interface{}
-			err := json.Unmarshal([]byte(attrValue), &tmp)
+			tmp, err := policyFromString(attrValue)
 			if err != nil {
 				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
 				continue
 			}
 			r.Attributes.RedriveAllowPolicy = tmp
+		case "FifoQueue":
+			tmp, err := strconv.ParseBool(attrValue)
+			if err != nil {
+				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+				continue
+			}
+			fifoQueue := StringToBool(tmp)
+			r.Attributes.FifoQueue = &fifoQueue
+		case "ContentBasedDeduplication":
+			tmp, err := strconv.ParseBool(attrValue)
+			if err != nil {
+				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+				continue
+			}
+			contentBasedDeduplication := StringToBool(tmp)
+			r.Attributes.ContentBasedDeduplication = &contentBasedDeduplication
+		case "DeduplicationScope":
+			r.Attributes.DeduplicationScope = attrValue
+		case "FifoThroughputLimit":
+			r.Attributes.FifoThroughputLimit = attrValue
 		}
+		r.GivenAttributes = append(r.GivenAttributes, attrName)
+	}
+	if tags := tagsFromForm(values); len(tags) > 0 {
+		r.Tags = tags
 	}
 	return
 }
@@ -179,9 +221,8 @@ type SendMessageRequest struct {
 	MessageGroupId         string                           `json:"MessageGroupId" schema:"MessageGroupId"`
 	// MessageSystemAttributes is custom attributes for AWS services.
 	// Please see: https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_SendMessage.html#SQS-SendMessage-request-MessageSystemAttributes
-	// On AWS, the only supported attribute is "AWSTraceHeader" that is for AWS X-Ray.
-	// Goaws does not contains X-Ray emulation, so currently MessageSystemAttributes is unsupported.
-	// TODO: Replace with a struct with known attributes "AWSTraceHeader".
+	// On AWS, the only supported attribute is "AWSTraceHeader" that is for AWS X-Ray.  Goaws does not
+	// emulate X-Ray, it just hands the header back on receive.
 	MessageSystemAttributes map[string]MessageAttributeValue `json:"MessageSystemAttributes" schema:"MessageSystemAttributes"`
 	QueueUrl                string                           `json:"QueueUrl" schema:"QueueUrl"`
 }
@@ -194,20 +235,21 @@ func (r *SendMessageRequest) SetAttributesFromForm(values url.Values) {
 			break
 		}
 
-		dataTypeKey := fmt.Sprintf("MessageAttribute.%d.Value.DataType", i)
-		dataType := values.Get(dataTypeKey)
-		if dataType == "" {
+		value, ok := messageAttributeValueFromForm(values, fmt.Sprintf("MessageAttribute.%d.Value", i))
+		if !ok {
 			log.Warnf("DataType of MessageAttribute %s is missing, MD5 checksum will most probably be wrong!\n", name)
 			continue
 		}
-
-		stringValue := values.Get(fmt.Sprintf("MessageAttribute.%d.Value.StringValue", i))
-		binaryValue := values.Get(fmt.Sprintf("MessageAttribute.%d.Value.BinaryValue", i))
-
-		r.MessageAttributes[name] = MessageAttributeValue{
-			DataType:    dataType,
-			StringValue: stringValue,
-			BinaryValue: binaryValue,
+		r.MessageAttributes[name] = value
+	}
+	for i := 1; true; i++ {
+		name := values.Get(fmt.Sprintf("MessageSystemAttribute.%d.Name", i))
+		if name == "" {
+			break
+		}
+		r.MessageSystemAttributes[name] = MessageAttributeValue{
+			DataType:    values.Get(fmt.Sprintf("MessageSystemAttribute.%d.Value.DataType", i)),
+			StringValue: values.Get(fmt.Sprintf("MessageSystemAttribute.%d.Value.StringValue", i)),
 		}
 	}
 }
@@ -237,7 +279,19 @@ func (r *SendMessageBatchRequest) SetAttributesFromForm(values url.Values) {
 		attributeIndex, err2 := strconv.Atoi(keySegments[3])
 
 		// If the entry index and attribute index cannot be obtained, the attribute will not be set, so skip
-		if err1 != nil || err2 != nil {
+		if err1 != nil || err2 != nil || entryIndex < 0 || entryIndex >= len(r.Entries) {
+			continue
+		}
+
+		systemNameKey := fmt.Sprintf("Entries.%d.MessageSystemAttributes.%d.Name", entryIndex, attributeIndex)
+		if key == systemNameKey {
+			if r.Entries[entryIndex].MessageSystemAttributes == nil {
+				r.Entries[entryIndex].MessageSystemAttributes = make(map[string]MessageAttributeValue)
+			}
+			r.Entries[entryIndex].MessageSystemAttributes[values.Get(systemNameKey)] = MessageAttributeValue{
+				DataType:    values.Get(fmt.Sprintf("Entries.%d.MessageSystemAttributes.%d.Value.DataType", entryIndex, attributeIndex)),
+				StringValue: values.Get(fmt.Sprintf("Entries.%d.MessageSystemAttributes.%d.Value.StringValue", entryIndex, attributeIndex)),
+			}
 			continue
 		}
 
@@ -246,40 +300,27 @@ func (r *SendMessageBatchRequest) SetAttributesFromForm(values url.Values) {
 			continue
 		}
 		name := values.Get(nameKey)
-		dataTypeKey := fmt.Sprintf("Entries.%d.MessageAttributes.%d.Value.DataType", entryIndex, attributeIndex)
-		dataType := values.Get(dataTypeKey)
-		if dataType == "" {
+		value, ok := messageAttributeValueFromForm(values, fmt.Sprintf("Entries.%d.MessageAttributes.%d.Value", entryIndex, attributeIndex))
+		if !ok {
 			log.Warnf("DataType of MessageAttribute %s is missing, MD5 checksum will most probably be wrong!\n", name)
 			continue
 		}
 
-		stringValue := values.Get(fmt.Sprintf("Entries.%d.MessageAttributes.%d.Value.StringValue", entryIndex, attributeIndex))
-		binaryValue := values.Get(fmt.Sprintf("Entries.%d.MessageAttributes.%d.Value.BinaryValue", entryIndex, attributeIndex))
-
 		if r.Entries[entryIndex].MessageAttributes == nil {
 			r.Entries[entryIndex].MessageAttributes = make(map[string]MessageAttributeValue)
 		}
-
-		r.Entries[entryIndex].MessageAttributes[name] = MessageAttributeValue{
-			DataType:    dataType,
-			StringValue: stringValue,
-			BinaryValue: binaryValue,
-		}
-
-		if _, ok := r.Entries[entryIndex].MessageAttributes[name]; !ok {
-			log.Warnf("StringValue or BinaryValue of MessageAttribute %s is missing, MD5 checksum will most probably be wrong!\n", name)
-		}
+		r.Entries[entryIndex].MessageAttributes[name] = value
 	}
 }
 
 type SendMessageBatchRequestEntry struct {
 	Id                      string                           `json:"Id" schema:"Id"`
 	MessageBody             string                           `json:"MessageBody" schema:"MessageBody"`
-	DelaySeconds            int                              `json:"DelaySeconds" schema:"DelaySeconds"` // NOTE: not implemented
+	DelaySeconds            int                              `json:"DelaySeconds" schema:"DelaySeconds"`
 	MessageAttributes       map[string]MessageAttributeValue `json:"MessageAttributes" schema:"MessageAttributes"`
 	MessageDeduplicationId  string                           `json:"MessageDeduplicationId" schema:"MessageDeduplicationId"`
 	MessageGroupId          string                           `json:"MessageGroupId" schema:"MessageGroupId"`
-	MessageSystemAttributes map[string]MessageAttributeValue `json:"MessageSystemAttributes" schema:"MessageSystemAttributes"` // NOTE: not implemented
+	MessageSystemAttributes map[string]MessageAttributeValue `json:"MessageSystemAttributes" schema:"MessageSystemAttributes"` // only AWSTraceHeader, see SendMessageRequest
 }
 
 // Get Queue Url Request
@@ -315,7 +356,8 @@ func (r *SetQueueAttributesRequest) SetAttributesFromForm(values url.Values) {
 
 		valueKey := fmt.Sprintf("Attribute.%d.Value", i)
 		attrValue := values.Get(valueKey)
-		if attrValue == "" {
+		// An empty Policy or RedriveAllowPolicy removes the queue's.
+		if attrValue == "" && attrName != "Policy" && attrName != "RedriveAllowPolicy" {
 			continue
 		}
 		switch attrName {
@@ -341,8 +383,7 @@ func (r *SetQueueAttributesRequest) SetAttributesFromForm(values url.Values) {
 			}
 			r.Attributes.MessageRetentionPeriod = StringToInt(tmp)
 		case "Policy":
-			var tmp map[string]interface{}
-			err := json.Unmarshal([]byte(attrValue), &tmp)
+			tmp, err := policyFromString(attrValue)
 			if err != nil {
 				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
 				continue
@@ -394,13 +435,32 @@ func (r *SetQueueAttributesRequest) SetAttributesFromForm(values url.Values) {
 			tmp.DeadLetterTargetArn = decodedPolicy.DeadLetterTargetArn
 			r.Attributes.RedrivePolicy = tmp
 		case "RedriveAllowPolicy":
-			var tmp map[string]interface{}
-			err := json.Unmarshal([]byte(attrValue), &tmp)
+			tmp, err := policyFromString(attrValue)
 			if err != nil {
 				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
 				continue
 			}
 			r.Attributes.RedriveAllowPolicy = tmp
+		case "FifoQueue":
+			tmp, err := strconv.ParseBool(attrValue)
+			if err != nil {
+				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+				continue
+			}
+			fifoQueue := StringToBool(tmp)
+			r.Attributes.FifoQueue = &fifoQueue
+		case "ContentBasedDeduplication":
+			tmp, err := strconv.ParseBool(attrValue)
+			if err != nil {
+				log.Debugf("Failed to parse form attribute - %s: %s", attrName, attrValue)
+				continue
+			}
+			contentBasedDeduplication := StringToBool(tmp)
+			r.Attributes.ContentBasedDeduplication = &contentBasedDeduplication
+		case "DeduplicationScope":
+			r.Attributes.DeduplicationScope = attrValue
+		case "FifoThroughputLimit":
+			r.Attributes.FifoThroughputLimit = attrValue
 		}
 	}
 	return
@@ -412,13 +472,65 @@ func (r *SetQueueAttributesRequest) SetAttributesFromForm(values url.Values) {
 type QueueAttributes struct {
 	DelaySeconds                  StringToInt            `json:"DelaySeconds"`
 	MaximumMessageSize            StringToInt            `json:"MaximumMessageSize"`
-	MessageRetentionPeriod        StringToInt            `json:"MessageRetentionPeriod"` // NOTE: not implemented
-	Policy                        map[string]interface{} `json:"Policy"`                 // NOTE: not implemented
+	MessageRetentionPeriod        StringToInt            `json:"MessageRetentionPeriod"`
+	Policy                        map[string]interface{} `json:"Policy"` // empty, but not nil, to remove it
 	ReceiveMessageWaitTimeSeconds StringToInt            `json:"ReceiveMessageWaitTimeSeconds"`
 	VisibilityTimeout             StringToInt            `json:"VisibilityTimeout"`
 	// Dead Letter Queues Only
 	RedrivePolicy      RedrivePolicy          `json:"RedrivePolicy"`
-	RedriveAllowPolicy map[string]interface{} `json:"RedriveAllowPolicy"` // NOTE: not implemented
+	RedriveAllowPolicy map[string]interface{} `json:"RedriveAllowPolicy"` // empty, but not nil, to remove it
+	// FIFO Queues Only - nil when not given, so that SetQueueAttributes can tell them from `false`
+	FifoQueue                 *StringToBool `json:"FifoQueue"`
+	ContentBasedDeduplication *StringToBool `json:"ContentBasedDeduplication"`
+	// High throughput FIFO Queues Only - empty when not given
+	DeduplicationScope  string `json:"DeduplicationScope"`  // queue or messageGroup
+	FifoThroughputLimit string `json:"FifoThroughputLimit"` // perQueue or perMessageGroupId
+}
+
+// UnmarshalJSON takes the Policy and RedriveAllowPolicy as strings, the way AWS carries them, as well as the
+// documents themselves.
+func (a *QueueAttributes) UnmarshalJSON(data []byte) error {
+	// The alias doesn't have this method, so it decodes as usual, on top of what's there already.
+	type queueAttributes QueueAttributes
+	attributes := struct {
+		*queueAttributes
+		Policy             json.RawMessage `json:"Policy"`
+		RedriveAllowPolicy json.RawMessage `json:"RedriveAllowPolicy"`
+	}{queueAttributes: (*queueAttributes)(a)}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return err
+	}
+	var err error
+	if a.Policy, err = policyFromJSON(attributes.Policy, a.Policy); err != nil {
+		return err
+	}
+	a.RedriveAllowPolicy, err = policyFromJSON(attributes.RedriveAllowPolicy, a.RedriveAllowPolicy)
+	return err
+}
+
+// policyFromJSON decodes a policy document given either as a string or as is, keeping `current` when
+// there's none.
+func policyFromJSON(raw json.RawMessage, current map[string]interface{}) (map[string]interface{}, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return current, nil
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		value = string(raw)
+	}
+	return policyFromString(value)
+}
+
+// policyFromString decodes a policy document, an empty one is an empty policy - to remove the queue's.
+func policyFromString(value string) (map[string]interface{}, error) {
+	policy := map[string]interface{}{}
+	if value == "" {
+		return policy, nil
+	}
+	if err := json.Unmarshal([]byte(value), &policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
 }
 
 type RedrivePolicy struct {
@@ -454,12 +566,30 @@ type ReceiveMessageRequest struct {
 	MessageSystemAttributeNames []string `json:"MessageSystemAttributeNames" schema:"MessageSystemAttributeNames"`
 	MessageAttributeNames       []string `json:"MessageAttributeNames" schema:"MessageAttributeNames"`
 	MaxNumberOfMessages         int      `json:"MaxNumberOfMessages" schema:"MaxNumberOfMessages"`
-	VisibilityTimeout           int      `json:"VisibilityTimeout" schema:"VisibilityTimeout"`
-	WaitTimeSeconds             int      `json:"WaitTimeSeconds" schema:"WaitTimeSeconds"`
-	ReceiveRequestAttemptId     string   `json:"ReceiveRequestAttemptId" schema:"ReceiveRequestAttemptId"`
+	// VisibilityTimeout overrides the queue's for the received messages, nil leaves it alone.
+	VisibilityTimeout       *int   `json:"VisibilityTimeout" schema:"VisibilityTimeout"`
+	WaitTimeSeconds         int    `json:"WaitTimeSeconds" schema:"WaitTimeSeconds"`
+	ReceiveRequestAttemptId string `json:"ReceiveRequestAttemptId" schema:"ReceiveRequestAttemptId"`
 }
 
-func (r *ReceiveMessageRequest) SetAttributesFromForm(values url.Values) {}
+func (r *ReceiveMessageRequest) SetAttributesFromForm(values url.Values) {
+	r.AttributeNames = append(r.AttributeNames, listFromForm(values, "AttributeName")...)
+	r.MessageSystemAttributeNames = append(r.MessageSystemAttributeNames, listFromForm(values, "MessageSystemAttributeName")...)
+	r.MessageAttributeNames = append(r.MessageAttributeNames, listFromForm(values, "MessageAttributeName")...)
+}
+
+// listFromForm reads the `<prefix>.N` members of a list in the query protocol.
+func listFromForm(values url.Values, prefix string) []string {
+	list := []string{}
+	for i := 1; true; i++ {
+		value := values.Get(fmt.Sprintf("%s.%d", prefix, i))
+		if value == "" {
+			break
+		}
+		list = append(list, value)
+	}
+	return list
+}
 
 func NewCreateQueueRequest() *CreateQueueRequest {
 	return &CreateQueueRequest{
@@ -485,6 +615,23 @@ type ChangeMessageVisibilityRequest struct {
 
 func (r *ChangeMessageVisibilityRequest) SetAttributesFromForm(values url.Values) {}
 
+type ChangeMessageVisibilityBatchRequestEntry struct {
+	Id                string `json:"Id" schema:"Id"`
+	ReceiptHandle     string `json:"ReceiptHandle" schema:"ReceiptHandle"`
+	VisibilityTimeout int    `json:"VisibilityTimeout" schema:"VisibilityTimeout"`
+}
+
+type ChangeMessageVisibilityBatchRequest struct {
+	Entries  []ChangeMessageVisibilityBatchRequestEntry `json:"Entries" schema:"Entries"`
+	QueueUrl string                                     `json:"QueueUrl" schema:"QueueUrl"`
+}
+
+func NewChangeMessageVisibilityBatchRequest() *ChangeMessageVisibilityBatchRequest {
+	return &ChangeMessageVisibilityBatchRequest{}
+}
+
+func (r *ChangeMessageVisibilityBatchRequest) SetAttributesFromForm(values url.Values) {}
+
 func NewDeleteMessageRequest() *DeleteMessageRequest {
 	return &DeleteMessageRequest{}
 }
@@ -531,3 +678,124 @@ func NewDeleteMessageBatchRequest() *DeleteMessageBatchRequest {
 }
 
 func (r *DeleteMessageBatchRequest) SetAttributesFromForm(values url.Values) {}
+
+// tagsFromForm reads the `Tag.N.Key` / `Tag.N.Value` pairs of the query protocol.
+func tagsFromForm(values url.Values) map[string]string {
+	tags := map[string]string{}
+	for i := 1; true; i++ {
+		key := values.Get(fmt.Sprintf("Tag.%d.Key", i))
+		if key == "" {
+			break
+		}
+		tags[key] = values.Get(fmt.Sprintf("Tag.%d.Value", i))
+	}
+	return tags
+}
+
+func NewTagQueueRequest() *TagQueueRequest {
+	return &TagQueueRequest{}
+}
+
+type TagQueueRequest struct {
+	QueueUrl string            `json:"QueueUrl" schema:"QueueUrl"`
+	Tags     map[string]string `json:"Tags" schema:"-"`
+}
+
+func (r *TagQueueRequest) SetAttributesFromForm(values url.Values) {
+	r.Tags = tagsFromForm(values)
+}
+
+func NewUntagQueueRequest() *UntagQueueRequest {
+	return &UntagQueueRequest{}
+}
+
+type UntagQueueRequest struct {
+	QueueUrl string   `json:"QueueUrl" schema:"QueueUrl"`
+	TagKeys  []string `json:"TagKeys" schema:"-"`
+}
+
+func (r *UntagQueueRequest) SetAttributesFromForm(values url.Values) {
+	r.TagKeys = append(r.TagKeys, listFromForm(values, "TagKey")...)
+}
+
+func NewListQueueTagsRequest() *ListQueueTagsRequest {
+	return &ListQueueTagsRequest{}
+}
+
+type ListQueueTagsRequest struct {
+	QueueUrl string `json:"QueueUrl" schema:"QueueUrl"`
+}
+
+func (r *ListQueueTagsRequest) SetAttributesFromForm(values url.Values) {}
+
+func NewListDeadLetterSourceQueuesRequest() *ListDeadLetterSourceQueuesRequest {
+	return &ListDeadLetterSourceQueuesRequest{}
+}
+
+type ListDeadLetterSourceQueuesRequest struct {
+	QueueUrl   string `json:"QueueUrl" schema:"QueueUrl"`
+	MaxResults int    `json:"MaxResults" schema:"MaxResults"`
+	NextToken  string `json:"NextToken" schema:"NextToken"`
+}
+
+func (r *ListDeadLetterSourceQueuesRequest) SetAttributesFromForm(values url.Values) {}
+
+func NewStartMessageMoveTaskRequest() *StartMessageMoveTaskRequest {
+	return &StartMessageMoveTaskRequest{}
+}
+
+type StartMessageMoveTaskRequest struct {
+	SourceArn                    string `json:"SourceArn" schema:"SourceArn"`
+	DestinationArn               string `json:"DestinationArn" schema:"DestinationArn"`
+	MaxNumberOfMessagesPerSecond int    `json:"MaxNumberOfMessagesPerSecond" schema:"MaxNumberOfMessagesPerSecond"`
+}
+
+func (r *StartMessageMoveTaskRequest) SetAttributesFromForm(values url.Values) {}
+
+func NewListMessageMoveTasksRequest() *ListMessageMoveTasksRequest {
+	return &ListMessageMoveTasksRequest{}
+}
+
+type ListMessageMoveTasksRequest struct {
+	SourceArn  string `json:"SourceArn" schema:"SourceArn"`
+	MaxResults int    `json:"MaxResults" schema:"MaxResults"`
+}
+
+func (r *ListMessageMoveTasksRequest) SetAttributesFromForm(values url.Values) {}
+
+func NewCancelMessageMoveTaskRequest() *CancelMessageMoveTaskRequest {
+	return &CancelMessageMoveTaskRequest{}
+}
+
+type CancelMessageMoveTaskRequest struct {
+	TaskHandle string `json:"TaskHandle" schema:"TaskHandle"`
+}
+
+func (r *CancelMessageMoveTaskRequest) SetAttributesFromForm(values url.Values) {}
+
+func NewAddPermissionRequest() *AddPermissionRequest {
+	return &AddPermissionRequest{}
+}
+
+type AddPermissionRequest struct {
+	QueueUrl      string   `json:"QueueUrl" schema:"QueueUrl"`
+	Label         string   `json:"Label" schema:"Label"`
+	AWSAccountIds []string `json:"AWSAccountIds" schema:"-"`
+	Actions       []string `json:"Actions" schema:"-"`
+}
+
+func (r *AddPermissionRequest) SetAttributesFromForm(values url.Values) {
+	r.AWSAccountIds = append(r.AWSAccountIds, listFromForm(values, "AWSAccountId")...)
+	r.Actions = append(r.Actions, listFromForm(values, "ActionName")...)
+}
+
+func NewRemovePermissionRequest() *RemovePermissionRequest {
+	return &RemovePermissionRequest{}
+}
+
+type RemovePermissionRequest struct {
+	QueueUrl string `json:"QueueUrl" schema:"QueueUrl"`
+	Label    string `json:"Label" schema:"Label"`
+}
+
+func (r *RemovePermissionRequest) SetAttributesFromForm(values url.Values) {}
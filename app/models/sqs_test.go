@@ -84,6 +84,45 @@ func TestCreateQueueRequest_SetAttributesFromForm_success(t *testing.T) {
 	assert.Equal(t, map[string]interface{}{"i-am": "the-redrive-allow-policy"}, cqr.Attributes.RedriveAllowPolicy)
 }
 
+func TestCreateQueueRequest_SetAttributesFromForm_success_fifo_attributes(t *testing.T) {
+	form := url.Values{}
+	form.Add("Attribute.1.Name", "FifoQueue")
+	form.Add("Attribute.1.Value", "true")
+	form.Add("Attribute.2.Name", "ContentBasedDeduplication")
+	form.Add("Attribute.2.Value", "false")
+	form.Add("Attribute.3.Name", "DeduplicationScope")
+	form.Add("Attribute.3.Value", "messageGroup")
+	form.Add("Attribute.4.Name", "FifoThroughputLimit")
+	form.Add("Attribute.4.Value", "perMessageGroupId")
+
+	cqr := &CreateQueueRequest{
+		Attributes: QueueAttributes{},
+	}
+	cqr.SetAttributesFromForm(form)
+
+	assert.True(t, cqr.Attributes.FifoQueue.Bool())
+	assert.False(t, cqr.Attributes.ContentBasedDeduplication.Bool())
+	assert.Equal(t, "messageGroup", cqr.Attributes.DeduplicationScope)
+	assert.Equal(t, "perMessageGroupId", cqr.Attributes.FifoThroughputLimit)
+	assert.Equal(t, []string{"FifoQueue", "ContentBasedDeduplication", "DeduplicationScope", "FifoThroughputLimit"}, cqr.GivenAttributes)
+}
+
+func TestCreateQueueRequest_UnmarshalJSON_keeps_defaults_and_given_attributes(t *testing.T) {
+	cqr := &CreateQueueRequest{
+		Attributes: QueueAttributes{
+			MaximumMessageSize: 262144,
+			VisibilityTimeout:  30,
+		},
+	}
+	err := json.Unmarshal([]byte(`{"QueueName": "new-queue", "Attributes": {"VisibilityTimeout": "5"}}`), cqr)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "new-queue", cqr.QueueName)
+	assert.Equal(t, StringToInt(262144), cqr.Attributes.MaximumMessageSize)
+	assert.Equal(t, StringToInt(5), cqr.Attributes.VisibilityTimeout)
+	assert.Equal(t, []string{"VisibilityTimeout"}, cqr.GivenAttributes)
+}
+
 func TestCreateQueueRequest_SetAttributesFromForm_success_handles_redrive_recieve_count_int(t *testing.T) {
 	expectedRedrivePolicy := RedrivePolicy{
 		MaxReceiveCount:     100,
@@ -320,6 +359,26 @@ func TestSendMessageRequest_SetAttributesFromForm_success(t *testing.T) {
 	assert.Equal(t, "VmFsdWUy", attr2.BinaryValue)
 }
 
+func TestSendMessageRequest_SetAttributesFromForm_list_and_custom_type_values(t *testing.T) {
+	form := url.Values{}
+	form.Add("MessageAttribute.1.Name", "Attr1")
+	form.Add("MessageAttribute.1.Value.DataType", "String.json")
+	form.Add("MessageAttribute.1.Value.StringListValue.1", "Value1")
+	form.Add("MessageAttribute.1.Value.StringListValue.2", "Value2")
+	form.Add("MessageAttribute.2.Name", "Attr2")
+	form.Add("MessageAttribute.2.Value.DataType", "Binary.gif")
+	form.Add("MessageAttribute.2.Value.BinaryListValue.1", "VmFsdWUx")
+
+	r := &SendMessageRequest{
+		MessageAttributes:       make(map[string]MessageAttributeValue),
+		MessageSystemAttributes: make(map[string]MessageAttributeValue),
+	}
+	r.SetAttributesFromForm(form)
+
+	assert.Equal(t, MessageAttributeValue{DataType: "String.json", StringListValues: []string{"Value1", "Value2"}}, r.MessageAttributes["Attr1"])
+	assert.Equal(t, MessageAttributeValue{DataType: "Binary.gif", BinaryListValues: []string{"VmFsdWUx"}}, r.MessageAttributes["Attr2"])
+}
+
 func TestSetQueueAttributesRequest_SetAttributesFromForm_success(t *testing.T) {
 	expectedRedrivePolicy := RedrivePolicy{
 		MaxReceiveCount:     100,
@@ -461,3 +520,88 @@ func TestSetQueueAttributesRequest_SetAttributesFromForm_success_skips_invalid_v
 	assert.Equal(t, RedrivePolicy{}, cqr.Attributes.RedrivePolicy)
 	assert.Equal(t, map[string]interface{}(nil), cqr.Attributes.RedriveAllowPolicy)
 }
+
+func TestReceiveMessageRequest_SetAttributesFromForm(t *testing.T) {
+	form := url.Values{}
+	form.Add("AttributeName.1", "SentTimestamp")
+	form.Add("AttributeName.2", "ApproximateReceiveCount")
+	form.Add("MessageSystemAttributeName.1", "SenderId")
+	form.Add("MessageAttributeName.1", "foo.*")
+	form.Add("MessageAttributeName.3", "skipped")
+
+	r := &ReceiveMessageRequest{}
+	r.SetAttributesFromForm(form)
+
+	assert.Equal(t, []string{"SentTimestamp", "ApproximateReceiveCount"}, r.AttributeNames)
+	assert.Equal(t, []string{"SenderId"}, r.MessageSystemAttributeNames)
+	assert.Equal(t, []string{"foo.*"}, r.MessageAttributeNames)
+}
+
+func TestSendMessageRequest_SetAttributesFromForm_system_attributes(t *testing.T) {
+	form := url.Values{}
+	form.Add("MessageSystemAttribute.1.Name", "AWSTraceHeader")
+	form.Add("MessageSystemAttribute.1.Value.DataType", "String")
+	form.Add("MessageSystemAttribute.1.Value.StringValue", "Root=1-5759e988-bd862e3fe1be46a994272793")
+
+	r := NewSendMessageRequest()
+	r.SetAttributesFromForm(form)
+
+	assert.Equal(t, MessageAttributeValue{DataType: "String", StringValue: "Root=1-5759e988-bd862e3fe1be46a994272793"}, r.MessageSystemAttributes["AWSTraceHeader"])
+}
+
+func TestSendMessageBatchRequest_SetAttributesFromForm_system_attributes(t *testing.T) {
+	form := url.Values{}
+	form.Add("Entries.1.MessageSystemAttributes.1.Name", "AWSTraceHeader")
+	form.Add("Entries.1.MessageSystemAttributes.1.Value.DataType", "String")
+	form.Add("Entries.1.MessageSystemAttributes.1.Value.StringValue", "Root=1-5759e988-bd862e3fe1be46a994272793")
+	form.Add("Entries.1.MessageAttributes.1.Name", "attr1")
+	form.Add("Entries.1.MessageAttributes.1.Value.DataType", "String")
+	form.Add("Entries.1.MessageAttributes.1.Value.StringValue", "value1")
+	form.Add("Entries.5.MessageSystemAttributes.1.Name", "AWSTraceHeader")
+
+	r := NewSendMessageBatchRequest()
+	r.Entries = make([]SendMessageBatchRequestEntry, 2)
+	r.SetAttributesFromForm(form)
+
+	assert.Nil(t, r.Entries[0].MessageSystemAttributes)
+	assert.Equal(t, map[string]MessageAttributeValue{
+		"AWSTraceHeader": {DataType: "String", StringValue: "Root=1-5759e988-bd862e3fe1be46a994272793"},
+	}, r.Entries[1].MessageSystemAttributes)
+	assert.Equal(t, map[string]MessageAttributeValue{
+		"attr1": {DataType: "String", StringValue: "value1"},
+	}, r.Entries[1].MessageAttributes)
+}
+
+func TestQueueAttributes_UnmarshalJSON_policy_as_string_or_object(t *testing.T) {
+	expected := map[string]interface{}{"Version": "2012-10-17"}
+
+	attrs := QueueAttributes{}
+	err := json.Unmarshal([]byte(`{"Policy": "{\"Version\": \"2012-10-17\"}", "VisibilityTimeout": "5"}`), &attrs)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, attrs.Policy)
+	assert.Equal(t, StringToInt(5), attrs.VisibilityTimeout)
+
+	attrs = QueueAttributes{}
+	err = json.Unmarshal([]byte(`{"Policy": {"Version": "2012-10-17"}}`), &attrs)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expected, attrs.Policy)
+
+	attrs = QueueAttributes{}
+	err = json.Unmarshal([]byte(`{"Policy": ""}`), &attrs)
+
+	assert.Nil(t, err)
+	assert.NotNil(t, attrs.Policy)
+	assert.Empty(t, attrs.Policy)
+	assert.Nil(t, attrs.RedriveAllowPolicy)
+}
+
+func TestQueueAttributes_UnmarshalJSON_redrive_allow_policy_as_string(t *testing.T) {
+	attrs := QueueAttributes{}
+	err := json.Unmarshal([]byte(`{"RedriveAllowPolicy": "{\"redrivePermission\": \"denyAll\"}"}`), &attrs)
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"redrivePermission": "denyAll"}, attrs.RedriveAllowPolicy)
+	assert.Nil(t, attrs.Policy)
+}
@@ -0,0 +1,196 @@
+package persistence
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+
+	"github.com/Admiral-Piett/goaws/app"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	opPutQueue      = "putQueue"
+	opDeleteQueue   = "deleteQueue"
+	opPurgeQueue    = "purgeQueue"
+	opPutMessage    = "putMessage"
+	opDeleteMessage = "deleteMessage"
+	opPutTopic      = "putTopic"
+	opDeleteTopic   = "deleteTopic"
+)
+
+// journalEntry is one line of the journal.  Every entry carries the full resulting state of what it
+// touched rather than a delta, so replaying an entry twice is harmless.
+type journalEntry struct {
+	Op        string       `json:"op"`
+	Queue     string       `json:"queue,omitempty"`
+	QueueData *queueRecord `json:"queueData,omitempty"`
+	Message   *app.Message `json:"message,omitempty"`
+	MessageId string       `json:"messageId,omitempty"`
+	Fifo      *fifoState   `json:"fifo,omitempty"`
+	Topic     string       `json:"topic,omitempty"`
+	TopicData *topicRecord `json:"topicData,omitempty"`
+}
+
+// QueueUpdated records the settings of a created or modified queue, not including its messages.
+func QueueUpdated(q *app.Queue) {
+	if current == nil {
+		return
+	}
+	r := newQueueRecord(q, false)
+	current.append(journalEntry{Op: opPutQueue, Queue: q.Name, QueueData: &r})
+}
+
+func QueueDeleted(queueName string) {
+	if current == nil {
+		return
+	}
+	current.append(journalEntry{Op: opDeleteQueue, Queue: queueName})
+}
+
+func QueuePurged(q *app.Queue) {
+	if current == nil {
+		return
+	}
+	current.append(journalEntry{Op: opPurgeQueue, Queue: q.Name, Fifo: newFifoState(q)})
+}
+
+// MessageUpdated records a message that was added to `q` or changed in any way (received, made visible
+// again, had its visibility changed...).
+func MessageUpdated(q *app.Queue, msg *app.Message) {
+	if current == nil {
+		return
+	}
+	m := *msg
+	current.append(journalEntry{Op: opPutMessage, Queue: q.Name, Message: &m, Fifo: newFifoState(q)})
+}
+
+// MessageDeleted records a message that was removed from `q`, by a delete or a move to another queue.
+func MessageDeleted(q *app.Queue, messageId string) {
+	if current == nil {
+		return
+	}
+	current.append(journalEntry{Op: opDeleteMessage, Queue: q.Name, MessageId: messageId, Fifo: newFifoState(q)})
+}
+
+// TopicUpdated records a created topic or any change to its attributes or subscriptions.
+func TopicUpdated(t *app.Topic) {
+	if current == nil {
+		return
+	}
+	r := newTopicRecord(t)
+	current.append(journalEntry{Op: opPutTopic, Topic: t.Name, TopicData: &r})
+}
+
+func TopicDeleted(topicName string) {
+	if current == nil {
+		return
+	}
+	current.append(journalEntry{Op: opDeleteTopic, Topic: topicName})
+}
+
+func (s *store) append(e journalEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Errorf("Failed to encode journal entry %s: %s", e.Op, err)
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.journal == nil {
+		return
+	}
+	if _, err := s.journal.Write(append(b, '\n')); err != nil {
+		log.Errorf("Failed to write journal entry %s: %s", e.Op, err)
+	}
+}
+
+// replayJournal applies the journal on top of the snapshot state.  A torn last line (i.e. from a crash
+// mid-write) ends the replay, everything before it is kept.
+func replayJournal(filename string, s *state) (int, error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	count := 0
+	for scanner.Scan() {
+		e := journalEntry{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.Warnf("Stopping journal replay at entry %d: %s", count+1, err)
+			break
+		}
+		s.apply(e)
+		count++
+	}
+	return count, scanner.Err()
+}
+
+func (s *state) apply(e journalEntry) {
+	switch e.Op {
+	case opPutQueue:
+		if existing, ok := s.queues[e.Queue]; ok {
+			e.QueueData.Messages = existing.Messages
+		}
+		s.queues[e.Queue] = e.QueueData
+	case opDeleteQueue:
+		delete(s.queues, e.Queue)
+	case opPurgeQueue:
+		if q, ok := s.queues[e.Queue]; ok {
+			q.Messages = nil
+			q.Fifo = e.Fifo
+		}
+	case opPutMessage:
+		q, ok := s.queues[e.Queue]
+		if !ok {
+			return
+		}
+		q.Fifo = e.Fifo
+		if i, ok := s.messageIndex(q)[e.Message.Uuid]; ok {
+			q.Messages[i] = *e.Message
+			return
+		}
+		s.messageIndex(q)[e.Message.Uuid] = len(q.Messages)
+		q.Messages = append(q.Messages, *e.Message)
+	case opDeleteMessage:
+		q, ok := s.queues[e.Queue]
+		if !ok {
+			return
+		}
+		q.Fifo = e.Fifo
+		if i, ok := s.messageIndex(q)[e.MessageId]; ok {
+			q.Messages = append(q.Messages[:i], q.Messages[i+1:]...)
+			// Splicing shifts everything after `i`, so the index has to be rebuilt on the next lookup.
+			delete(s.indexes, q)
+		}
+	case opPutTopic:
+		s.topics[e.Topic] = e.TopicData
+	case opDeleteTopic:
+		delete(s.topics, e.Topic)
+	default:
+		log.Warnf("Unknown journal entry %s", e.Op)
+	}
+}
+
+// messageIndex maps message IDs to their position in `q.Messages` so that replaying a long journal of
+// receives doesn't rescan the queue for every entry.
+func (s *state) messageIndex(q *queueRecord) map[string]int {
+	if s.indexes == nil {
+		s.indexes = map[*queueRecord]map[string]int{}
+	}
+	index, ok := s.indexes[q]
+	if !ok {
+		index = make(map[string]int, len(q.Messages))
+		for i := range q.Messages {
+			index[q.Messages[i].Uuid] = i
+		}
+		s.indexes[q] = index
+	}
+	return index
+}
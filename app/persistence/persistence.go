@@ -0,0 +1,248 @@
+package persistence
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	snapshotFile    = "snapshot.json"
+	journalFile     = "journal.log"
+	snapshotVersion = 1
+)
+
+// store is the open on-disk state.  Lock ordering is always `app.SyncQueues` -> queues (by name) ->
+// `app.SyncTopics` -> store, the journal hooks below are called by the handlers while they still hold the
+// resource locks so that the journal order matches the order the changes were made in.
+type store struct {
+	sync.Mutex
+	dir     string
+	journal *os.File
+}
+
+var current *store
+
+// Enabled reports whether there is an open store to write to.
+func Enabled() bool {
+	return current != nil
+}
+
+// Open restores the queues, topics and messages saved in `config.Directory` on top of whatever has been
+// configured already, then starts journaling every change into it.  Any previously opened store is closed.
+func Open(config app.EnvPersistence) error {
+	if current != nil {
+		if err := Close(); err != nil {
+			log.Errorf("Failed to close previous store: %s", err)
+		}
+	}
+	if err := os.MkdirAll(config.Directory, 0755); err != nil {
+		return err
+	}
+
+	state, err := readSnapshot(filepath.Join(config.Directory, snapshotFile))
+	if err != nil {
+		return err
+	}
+	replayed, err := replayJournal(filepath.Join(config.Directory, journalFile), state)
+	if err != nil {
+		return err
+	}
+	restore(state)
+	log.Infof("Restored %d queue(s) and %d topic(s) from %s (%d journal entries)",
+		len(state.queues), len(state.topics), config.Directory, replayed)
+
+	current = &store{dir: config.Directory}
+	// Fold the replayed journal into a fresh snapshot straight away, this also opens the new journal.
+	return Snapshot()
+}
+
+// Close writes a final snapshot and stops journaling.
+func Close() error {
+	s := current
+	if s == nil {
+		return nil
+	}
+	err := Snapshot()
+	s.Lock()
+	defer s.Unlock()
+	if s.journal != nil {
+		if closeErr := s.journal.Close(); err == nil {
+			err = closeErr
+		}
+		s.journal = nil
+	}
+	current = nil
+	return err
+}
+
+// Snapshot saves the full state and truncates the journal.
+func Snapshot() error {
+	s := current
+	if s == nil {
+		return nil
+	}
+	// Everything stays locked until the journal has been truncated, otherwise a change made after its
+	// queue was copied would be dropped from both.
+	app.SyncQueues.RLock()
+	defer app.SyncQueues.RUnlock()
+	queues := make([]*app.Queue, 0, len(app.SyncQueues.Queues))
+	for _, q := range app.SyncQueues.Queues {
+		queues = append(queues, q)
+	}
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].Name < queues[j].Name
+	})
+	for _, q := range queues {
+		q.RLock()
+		defer q.RUnlock()
+	}
+	app.SyncTopics.RLock()
+	defer app.SyncTopics.RUnlock()
+	s.Lock()
+	defer s.Unlock()
+
+	snap := snapshot{Version: snapshotVersion, Taken: time.Now().UTC()}
+	for _, q := range queues {
+		snap.Queues = append(snap.Queues, newQueueRecord(q, true))
+	}
+	for _, t := range app.SyncTopics.Topics {
+		snap.Topics = append(snap.Topics, newTopicRecord(t))
+	}
+	if err := writeSnapshot(filepath.Join(s.dir, snapshotFile), snap); err != nil {
+		return err
+	}
+
+	if s.journal != nil {
+		s.journal.Close()
+	}
+	journal, err := os.OpenFile(filepath.Join(s.dir, journalFile), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		s.journal = nil
+		return err
+	}
+	s.journal = journal
+	log.Debugf("Snapshot written with %d queue(s) and %d topic(s)", len(snap.Queues), len(snap.Topics))
+	return nil
+}
+
+// PeriodicSnapshots takes a snapshot every `d` until `quit` is closed or signalled.
+func PeriodicSnapshots(d time.Duration, quit <-chan struct{}) {
+	ticker := time.NewTicker(d)
+	for {
+		select {
+		case <-ticker.C:
+			if err := Snapshot(); err != nil {
+				log.Errorf("Failed to write snapshot: %s", err)
+			}
+		case <-quit:
+			ticker.Stop()
+			return
+		}
+	}
+}
+
+func writeSnapshot(filename string, snap snapshot) error {
+	tmp := filename + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	if err := json.NewEncoder(w).Encode(snap); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// state is the restored data before it is handed over to `app`.
+type state struct {
+	queues  map[string]*queueRecord
+	topics  map[string]*topicRecord
+	indexes map[*queueRecord]map[string]int
+}
+
+func readSnapshot(filename string) (*state, error) {
+	s := &state{queues: map[string]*queueRecord{}, topics: map[string]*topicRecord{}}
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	snap := snapshot{}
+	if err := json.NewDecoder(bufio.NewReader(f)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %s", filename, err)
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d in %s", snap.Version, filename)
+	}
+	for i := range snap.Queues {
+		s.queues[snap.Queues[i].Name] = &snap.Queues[i]
+	}
+	for i := range snap.Topics {
+		s.topics[snap.Topics[i].Name] = &snap.Topics[i]
+	}
+	return s, nil
+}
+
+// restore hands the loaded records over to `app`.  Restored resources replace configured ones of the same
+// name, everything else that was configured is left as it is.
+func restore(s *state) {
+	app.SyncQueues.Lock()
+	defer app.SyncQueues.Unlock()
+	app.SyncTopics.Lock()
+	defer app.SyncTopics.Unlock()
+
+	for name, r := range s.queues {
+		q, ok := app.SyncQueues.Queues[name]
+		if !ok {
+			q = &app.Queue{}
+			app.SyncQueues.Queues[name] = q
+		}
+		q.Lock()
+		r.apply(q)
+		q.Messages = app.NewMessageStore(r.Messages...)
+		q.Unlock()
+	}
+	for name, r := range s.queues {
+		if r.DeadLetterQueue == "" {
+			continue
+		}
+		dlq, ok := app.SyncQueues.Queues[r.DeadLetterQueue]
+		if !ok {
+			log.Warnf("Dead letter queue %s of %s was not restored", r.DeadLetterQueue, name)
+			continue
+		}
+		q := app.SyncQueues.Queues[name]
+		q.Lock()
+		q.DeadLetterQueue = dlq
+		q.Unlock()
+	}
+	for name, r := range s.topics {
+		app.SyncTopics.Topics[name] = r.toTopic()
+	}
+}
@@ -0,0 +1,302 @@
+package persistence
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/test"
+)
+
+func openStore(t *testing.T, dir string) {
+	err := Open(app.EnvPersistence{Enabled: true, Directory: dir})
+	assert.Nil(t, err)
+}
+
+// crash drops the open store without the final snapshot that `Close` would write, leaving the journal
+// as the only record of anything that happened since the last snapshot.
+func crash() {
+	current.journal.Close()
+	current = nil
+}
+
+func newQueue(name string) *app.Queue {
+	return &app.Queue{
+		Name:              name,
+		URL:               "http://region.host:port/accountID/" + name,
+		Arn:               "arn:aws:sqs:region:accountID:" + name,
+		VisibilityTimeout: 30,
+		Duplicates:        make(map[string]app.Deduplicated),
+	}
+}
+
+func TestOpen_empty_directory_is_created(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "data")
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	openStore(t, dir)
+
+	assert.True(t, Enabled())
+	_, err := os.Stat(filepath.Join(dir, snapshotFile))
+	assert.Nil(t, err)
+	_, err = os.Stat(filepath.Join(dir, journalFile))
+	assert.Nil(t, err)
+}
+
+func TestSnapshot_round_trip(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	q := newQueue("queue1")
+	q.Created = time.Unix(1700000000, 0).UTC()
+	q.LastModified = time.Unix(1700003600, 0).UTC()
+	q.Messages = app.NewMessageStore(
+		app.Message{MessageBody: []byte("hello"), Uuid: "id-1", MD5OfMessageBody: "md5", NumberOfReceives: 2},
+	)
+	app.SyncQueues.Queues["queue1"] = q
+	app.SyncTopics.Topics["topic1"] = &app.Topic{
+		Name: "topic1",
+		Arn:  "arn:aws:sns:region:accountID:topic1",
+		Subscriptions: []*app.Subscription{
+			{TopicArn: "arn:aws:sns:region:accountID:topic1", Protocol: "sqs", SubscriptionArn: "sub-1", EndPoint: q.Arn, Raw: true},
+		},
+		SubscriptionsDeleted: 3,
+		DisplayName:          "Topic One",
+		TracingConfig:        "Active",
+	}
+
+	openStore(t, dir)
+	assert.Nil(t, Close())
+	test.ResetResources()
+	openStore(t, dir)
+
+	restored := app.SyncQueues.Queues["queue1"]
+	assert.Equal(t, "queue1", restored.Name)
+	assert.Equal(t, 30, restored.VisibilityTimeout)
+	assert.True(t, q.Created.Equal(restored.Created))
+	assert.True(t, q.LastModified.Equal(restored.LastModified))
+	assert.Len(t, restored.Messages.All(), 1)
+	assert.Equal(t, "hello", string(restored.Messages.All()[0].MessageBody))
+	assert.Equal(t, 2, restored.Messages.All()[0].NumberOfReceives)
+
+	topic := app.SyncTopics.Topics["topic1"]
+	assert.Len(t, topic.Subscriptions, 1)
+	assert.Equal(t, "sub-1", topic.Subscriptions[0].SubscriptionArn)
+	assert.True(t, topic.Subscriptions[0].Raw)
+	assert.Equal(t, 3, topic.SubscriptionsDeleted)
+	assert.Equal(t, "Topic One", topic.DisplayName)
+	assert.Equal(t, "Active", topic.TracingConfig)
+}
+
+func TestSnapshot_round_trip_fifo_state(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	q := newQueue("queue1.fifo")
+	q.IsFIFO = true
+	q.ContentBasedDeduplication = true
+	q.DeduplicationScope = app.DeduplicationScopeMessageGroup
+	q.FifoThroughputLimit = app.FifoThroughputLimitPerMessageGroupId
+	q.LockGroup("group-1")
+	sequenceNumber := q.NextSequenceNumber()
+	q.InitDuplicatation(&app.Message{GroupID: "group-1", DeduplicationID: "dedup-1"})
+	app.SyncQueues.Queues["queue1.fifo"] = q
+
+	openStore(t, dir)
+	assert.Nil(t, Close())
+	test.ResetResources()
+	openStore(t, dir)
+
+	restored := app.SyncQueues.Queues["queue1.fifo"]
+	assert.True(t, restored.ContentBasedDeduplication)
+	assert.Equal(t, app.DeduplicationScopeMessageGroup, restored.DeduplicationScope)
+	assert.Equal(t, app.FifoThroughputLimitPerMessageGroupId, restored.FifoThroughputLimit)
+	assert.True(t, restored.IsLocked("group-1"))
+	assert.True(t, restored.IsDuplicate("group-1", "dedup-1"))
+	assert.False(t, restored.IsDuplicate("group-2", "dedup-1"))
+	assert.Equal(t, sequenceNumber, fmt.Sprintf("%020d", restored.FIFOSequenceNumber))
+}
+
+func TestSnapshot_round_trip_fifo_topic_state(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	topic := &app.Topic{Name: "topic1.fifo", Arn: "arn:aws:sns:region:accountID:topic1.fifo", FifoTopic: true}
+	published, _ := topic.Sequence("dedup-1", "id-1", time.Now())
+	app.SyncTopics.Topics["topic1.fifo"] = topic
+
+	openStore(t, dir)
+	assert.Nil(t, Close())
+	test.ResetResources()
+	openStore(t, dir)
+
+	restored := app.SyncTopics.Topics["topic1.fifo"]
+	assert.True(t, restored.FifoTopic)
+	assert.Equal(t, published.SequenceNumber, fmt.Sprintf("%020d", restored.SequenceNumber))
+	original, ok := restored.Sequence("dedup-1", "id-2", time.Now())
+	assert.False(t, ok)
+	assert.Equal(t, "id-1", original.MessageId)
+	assert.Equal(t, published.SequenceNumber, original.SequenceNumber)
+}
+
+func TestJournal_replays_changes_since_last_snapshot(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	app.SyncQueues.Queues["queue1"] = newQueue("queue1")
+	app.SyncQueues.Queues["queue2"] = newQueue("queue2")
+	app.SyncTopics.Topics["topic1"] = &app.Topic{Name: "topic1", Arn: "arn:aws:sns:region:accountID:topic1"}
+	openStore(t, dir)
+
+	q1 := app.SyncQueues.Queues["queue1"]
+	MessageUpdated(q1, q1.Messages.Add(app.Message{MessageBody: []byte("first"), Uuid: "id-1"}))
+	second := q1.Messages.Add(app.Message{MessageBody: []byte("second"), Uuid: "id-2"})
+	MessageUpdated(q1, second)
+	second.NumberOfReceives = 1
+	MessageUpdated(q1, second)
+	q1.Messages.Remove("id-1")
+	MessageDeleted(q1, "id-1")
+
+	q3 := newQueue("queue3")
+	q3.DelaySeconds = 10
+	QueueUpdated(q3)
+	QueueDeleted("queue2")
+
+	TopicUpdated(&app.Topic{Name: "topic2", Arn: "arn:aws:sns:region:accountID:topic2"})
+	TopicDeleted("topic1")
+	crash()
+
+	test.ResetResources()
+	openStore(t, dir)
+
+	restored := app.SyncQueues.Queues["queue1"]
+	assert.Len(t, restored.Messages.All(), 1)
+	assert.Equal(t, "id-2", restored.Messages.All()[0].Uuid)
+	assert.Equal(t, 1, restored.Messages.All()[0].NumberOfReceives)
+
+	_, ok := app.SyncQueues.Queues["queue2"]
+	assert.False(t, ok)
+	assert.Equal(t, 10, app.SyncQueues.Queues["queue3"].DelaySeconds)
+
+	_, ok = app.SyncTopics.Topics["topic1"]
+	assert.False(t, ok)
+	_, ok = app.SyncTopics.Topics["topic2"]
+	assert.True(t, ok)
+}
+
+func TestJournal_purge_clears_messages(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	q := newQueue("queue1")
+	q.Messages = app.NewMessageStore(app.Message{Uuid: "id-1"}, app.Message{Uuid: "id-2"})
+	app.SyncQueues.Queues["queue1"] = q
+	openStore(t, dir)
+
+	q.Messages.Clear()
+	QueuePurged(q)
+	crash()
+
+	test.ResetResources()
+	openStore(t, dir)
+
+	assert.Len(t, app.SyncQueues.Queues["queue1"].Messages.All(), 0)
+}
+
+func TestJournal_torn_last_line_is_ignored(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	app.SyncQueues.Queues["queue1"] = newQueue("queue1")
+	openStore(t, dir)
+
+	q := app.SyncQueues.Queues["queue1"]
+	MessageUpdated(q, q.Messages.Add(app.Message{Uuid: "id-1"}))
+	current.journal.Write([]byte(`{"op":"putMessage","queue":"queue1","mess`))
+	crash()
+
+	test.ResetResources()
+	openStore(t, dir)
+
+	restored := app.SyncQueues.Queues["queue1"]
+	assert.Len(t, restored.Messages.All(), 1)
+	assert.Equal(t, "id-1", restored.Messages.All()[0].Uuid)
+}
+
+func TestRestore_links_dead_letter_queues(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	dlq := newQueue("dead-letter")
+	q := newQueue("queue1")
+	q.DeadLetterQueue = dlq
+	q.MaxReceiveCount = 3
+	app.SyncQueues.Queues["dead-letter"] = dlq
+	app.SyncQueues.Queues["queue1"] = q
+
+	openStore(t, dir)
+	assert.Nil(t, Close())
+	test.ResetResources()
+	openStore(t, dir)
+
+	restored := app.SyncQueues.Queues["queue1"]
+	assert.Equal(t, 3, restored.MaxReceiveCount)
+	assert.Same(t, app.SyncQueues.Queues["dead-letter"], restored.DeadLetterQueue)
+}
+
+func TestRestore_overrides_configured_queue(t *testing.T) {
+	dir := t.TempDir()
+	defer func() {
+		Close()
+		test.ResetResources()
+	}()
+
+	q := newQueue("queue1")
+	q.VisibilityTimeout = 45
+	q.Messages = app.NewMessageStore(app.Message{Uuid: "id-1"})
+	app.SyncQueues.Queues["queue1"] = q
+	openStore(t, dir)
+	assert.Nil(t, Close())
+
+	// A fresh start where the config only knows the queue's defaults and another queue.
+	test.ResetResources()
+	configured := newQueue("queue1")
+	app.SyncQueues.Queues["queue1"] = configured
+	app.SyncQueues.Queues["queue2"] = newQueue("queue2")
+	openStore(t, dir)
+
+	assert.Same(t, configured, app.SyncQueues.Queues["queue1"])
+	assert.Equal(t, 45, configured.VisibilityTimeout)
+	assert.Len(t, configured.Messages.All(), 1)
+	_, ok := app.SyncQueues.Queues["queue2"]
+	assert.True(t, ok)
+}
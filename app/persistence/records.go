@@ -0,0 +1,217 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+)
+
+// NOTE: The records below are the on-disk shape of our resources.  We keep them separate from the `app`
+// structs so that pointers between resources (i.e. dead letter queues) are stored by name and the files
+// stay readable across refactors of the in-memory models.
+
+type snapshot struct {
+	Version int           `json:"version"`
+	Taken   time.Time     `json:"taken"`
+	Queues  []queueRecord `json:"queues"`
+	Topics  []topicRecord `json:"topics"`
+}
+
+type queueRecord struct {
+	Name                          string            `json:"name"`
+	URL                           string            `json:"url"`
+	Arn                           string            `json:"arn"`
+	VisibilityTimeout             int               `json:"visibilityTimeout"`
+	ReceiveMessageWaitTimeSeconds int               `json:"receiveMessageWaitTimeSeconds"`
+	DelaySeconds                  int               `json:"delaySeconds"`
+	MaximumMessageSize            int               `json:"maximumMessageSize"`
+	MessageRetentionPeriod        int               `json:"messageRetentionPeriod"`
+	DeadLetterQueue               string            `json:"deadLetterQueue,omitempty"`
+	MaxReceiveCount               int               `json:"maxReceiveCount"`
+	IsFIFO                        bool              `json:"isFifo"`
+	ContentBasedDeduplication     bool              `json:"contentBasedDeduplication,omitempty"`
+	DeduplicationScope            string            `json:"deduplicationScope,omitempty"`
+	FifoThroughputLimit           string            `json:"fifoThroughputLimit,omitempty"`
+	Fifo                          *fifoState        `json:"fifo,omitempty"`
+	Tags                          map[string]string `json:"tags,omitempty"`
+	Policy                        string            `json:"policy,omitempty"`
+	RedriveAllowPolicy            string            `json:"redriveAllowPolicy,omitempty"`
+	Created                       time.Time         `json:"created"`
+	LastModified                  time.Time         `json:"lastModified"`
+	Messages                      []app.Message     `json:"messages,omitempty"`
+}
+
+// fifoState is the part of a queue that changes along with its messages, so it is journaled with them.
+type fifoState struct {
+	LockedGroups   map[string]int              `json:"lockedGroups,omitempty"`
+	SequenceNumber int64                       `json:"sequenceNumber,omitempty"`
+	Duplicates     map[string]app.Deduplicated `json:"duplicates,omitempty"`
+}
+
+type topicRecord struct {
+	Name                      string                      `json:"name"`
+	Arn                       string                      `json:"arn"`
+	Subscriptions             []app.Subscription          `json:"subscriptions,omitempty"`
+	SubscriptionsDeleted      int                         `json:"subscriptionsDeleted,omitempty"`
+	DisplayName               string                      `json:"displayName,omitempty"`
+	Policy                    string                      `json:"policy,omitempty"`
+	DeliveryPolicy            string                      `json:"deliveryPolicy,omitempty"`
+	FifoTopic                 bool                        `json:"fifoTopic,omitempty"`
+	ContentBasedDeduplication bool                        `json:"contentBasedDeduplication,omitempty"`
+	SignatureVersion          string                      `json:"signatureVersion,omitempty"`
+	TracingConfig             string                      `json:"tracingConfig,omitempty"`
+	KmsMasterKeyId            string                      `json:"kmsMasterKeyId,omitempty"`
+	ArchivePolicy             string                      `json:"archivePolicy,omitempty"`
+	SequenceNumber            int64                       `json:"sequenceNumber,omitempty"`
+	Duplicates                map[string]app.Deduplicated `json:"duplicates,omitempty"`
+}
+
+// NOTE: the caller must hold the lock of `q`.
+func newQueueRecord(q *app.Queue, withMessages bool) queueRecord {
+	r := queueRecord{
+		Name:                          q.Name,
+		URL:                           q.URL,
+		Arn:                           q.Arn,
+		VisibilityTimeout:             q.VisibilityTimeout,
+		ReceiveMessageWaitTimeSeconds: q.ReceiveMessageWaitTimeSeconds,
+		DelaySeconds:                  q.DelaySeconds,
+		MaximumMessageSize:            q.MaximumMessageSize,
+		MessageRetentionPeriod:        q.MessageRetentionPeriod,
+		MaxReceiveCount:               q.MaxReceiveCount,
+		IsFIFO:                        q.IsFIFO,
+		ContentBasedDeduplication:     q.ContentBasedDeduplication,
+		DeduplicationScope:            q.DeduplicationScope,
+		FifoThroughputLimit:           q.FifoThroughputLimit,
+		Fifo:                          newFifoState(q),
+		Policy:                        q.Policy,
+		RedriveAllowPolicy:            q.RedriveAllowPolicy,
+		Created:                       q.Created,
+		LastModified:                  q.LastModified,
+	}
+	if len(q.Tags) > 0 {
+		r.Tags = make(map[string]string, len(q.Tags))
+		for k, v := range q.Tags {
+			r.Tags[k] = v
+		}
+	}
+	if q.DeadLetterQueue != nil {
+		r.DeadLetterQueue = q.DeadLetterQueue.Name
+	}
+	if withMessages {
+		r.Messages = q.Messages.All()
+	}
+	return r
+}
+
+func newFifoState(q *app.Queue) *fifoState {
+	if !q.IsFIFO && len(q.Duplicates) == 0 {
+		return nil
+	}
+	s := &fifoState{
+		LockedGroups:   make(map[string]int, len(q.FIFOMessages)),
+		SequenceNumber: q.FIFOSequenceNumber,
+		Duplicates:     make(map[string]app.Deduplicated, len(q.Duplicates)),
+	}
+	for k, v := range q.FIFOMessages {
+		s.LockedGroups[k] = v
+	}
+	for k, v := range q.Duplicates {
+		s.Duplicates[k] = v
+	}
+	return s
+}
+
+// apply copies the queue level settings of the record onto `q`, leaving its messages alone.
+// The dead letter queue is linked separately, once every queue has been loaded.
+func (r queueRecord) apply(q *app.Queue) {
+	q.Name = r.Name
+	q.URL = r.URL
+	q.Arn = r.Arn
+	q.VisibilityTimeout = r.VisibilityTimeout
+	q.ReceiveMessageWaitTimeSeconds = r.ReceiveMessageWaitTimeSeconds
+	q.DelaySeconds = r.DelaySeconds
+	q.MaximumMessageSize = r.MaximumMessageSize
+	q.MessageRetentionPeriod = r.MessageRetentionPeriod
+	q.MaxReceiveCount = r.MaxReceiveCount
+	q.IsFIFO = r.IsFIFO
+	q.ContentBasedDeduplication = r.ContentBasedDeduplication
+	q.DeduplicationScope = r.DeduplicationScope
+	q.FifoThroughputLimit = r.FifoThroughputLimit
+	q.Tags = r.Tags
+	q.Policy = r.Policy
+	q.RedriveAllowPolicy = r.RedriveAllowPolicy
+	// Files written before the timestamps were recorded keep the ones the queue was loaded with.
+	if !r.Created.IsZero() {
+		q.Created = r.Created
+		q.LastModified = r.LastModified
+	}
+	r.Fifo.apply(q)
+}
+
+func (s *fifoState) apply(q *app.Queue) {
+	if q.Duplicates == nil {
+		q.Duplicates = make(map[string]app.Deduplicated)
+	}
+	if s == nil {
+		return
+	}
+	q.FIFOMessages = s.LockedGroups
+	q.FIFOSequenceNumber = s.SequenceNumber
+	if s.Duplicates != nil {
+		q.Duplicates = s.Duplicates
+	}
+}
+
+// NOTE: the caller must hold `app.SyncTopics`.
+func newTopicRecord(t *app.Topic) topicRecord {
+	r := topicRecord{
+		Name:                      t.Name,
+		Arn:                       t.Arn,
+		SubscriptionsDeleted:      t.SubscriptionsDeleted,
+		DisplayName:               t.DisplayName,
+		Policy:                    t.Policy,
+		DeliveryPolicy:            t.DeliveryPolicy,
+		FifoTopic:                 t.FifoTopic,
+		ContentBasedDeduplication: t.ContentBasedDeduplication,
+		SignatureVersion:          t.SignatureVersion,
+		TracingConfig:             t.TracingConfig,
+		KmsMasterKeyId:            t.KmsMasterKeyId,
+		ArchivePolicy:             t.ArchivePolicy,
+		SequenceNumber:            t.SequenceNumber,
+	}
+	if len(t.Duplicates) > 0 {
+		r.Duplicates = make(map[string]app.Deduplicated, len(t.Duplicates))
+		for k, v := range t.Duplicates {
+			r.Duplicates[k] = v
+		}
+	}
+	for _, sub := range t.Subscriptions {
+		r.Subscriptions = append(r.Subscriptions, *sub)
+	}
+	return r
+}
+
+func (r topicRecord) toTopic() *app.Topic {
+	t := &app.Topic{
+		Name:                      r.Name,
+		Arn:                       r.Arn,
+		Subscriptions:             make([]*app.Subscription, 0, len(r.Subscriptions)),
+		SubscriptionsDeleted:      r.SubscriptionsDeleted,
+		DisplayName:               r.DisplayName,
+		Policy:                    r.Policy,
+		DeliveryPolicy:            r.DeliveryPolicy,
+		FifoTopic:                 r.FifoTopic,
+		ContentBasedDeduplication: r.ContentBasedDeduplication,
+		SignatureVersion:          r.SignatureVersion,
+		TracingConfig:             r.TracingConfig,
+		KmsMasterKeyId:            r.KmsMasterKeyId,
+		ArchivePolicy:             r.ArchivePolicy,
+		SequenceNumber:            r.SequenceNumber,
+		Duplicates:                r.Duplicates,
+	}
+	for i := range r.Subscriptions {
+		sub := r.Subscriptions[i]
+		t.Subscriptions = append(t.Subscriptions, &sub)
+	}
+	return t
+}
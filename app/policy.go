@@ -0,0 +1,323 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PolicyDocument is a resource policy, the part of the IAM policy language queue policies use.
+// https://docs.aws.amazon.com/AWSSimpleQueueService/latest/SQSDeveloperGuide/sqs-basic-examples-of-sqs-policies.html
+type PolicyDocument struct {
+	Version   string            `json:"Version,omitempty"`
+	Id        string            `json:"Id,omitempty"`
+	Statement []PolicyStatement `json:"Statement"`
+}
+
+type PolicyStatement struct {
+	Sid       string                             `json:"Sid,omitempty"`
+	Effect    string                             `json:"Effect"`
+	Principal PolicyPrincipal                    `json:"Principal"`
+	Action    PolicyValues                       `json:"Action"`
+	Resource  PolicyValues                       `json:"Resource,omitempty"`
+	Condition map[string]map[string]PolicyValues `json:"Condition,omitempty"`
+}
+
+// PolicyPrincipal is either everybody, `"*"`, or the AWS accounts and services a statement is about.
+type PolicyPrincipal struct {
+	All     bool
+	AWS     PolicyValues
+	Service PolicyValues
+}
+
+// PolicyValues is a list in a policy, which may also be written as a single value.
+type PolicyValues []string
+
+// PolicyRequest is what a policy is evaluated for: an action on a resource, by a caller of an account or
+// by an AWS service acting for its resource SourceArn.
+type PolicyRequest struct {
+	Action    string // e.g. sqs:SendMessage
+	Resource  string
+	AccountId string
+	Service   string // e.g. sns.amazonaws.com
+	SourceArn string
+}
+
+const (
+	PolicyEffectAllow = "Allow"
+	PolicyEffectDeny  = "Deny"
+)
+
+// ParsePolicy reads a policy document, checking it has what evaluating it needs.
+func ParsePolicy(text string) (*PolicyDocument, error) {
+	doc := &PolicyDocument{}
+	if err := json.Unmarshal([]byte(text), doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Statement) == 0 {
+		return nil, errors.New("the policy has no statements")
+	}
+	for _, statement := range doc.Statement {
+		if statement.Effect != PolicyEffectAllow && statement.Effect != PolicyEffectDeny {
+			return nil, fmt.Errorf("invalid effect: %s", statement.Effect)
+		}
+		if !statement.Principal.All && len(statement.Principal.AWS) == 0 && len(statement.Principal.Service) == 0 {
+			return nil, errors.New("a statement has no principal")
+		}
+		if len(statement.Action) == 0 {
+			return nil, errors.New("a statement has no action")
+		}
+	}
+	return doc, nil
+}
+
+// Allows evaluates the policy the way AWS does resource policies: a Deny wins over everything, and
+// anything that isn't allowed is denied.  Callers of the account the resource belongs to are allowed
+// unless denied, as if their own IAM policies let them do anything - services never are.
+func (d *PolicyDocument) Allows(r PolicyRequest) bool {
+	allowed := r.Service == "" && r.AccountId == arnAccount(r.Resource)
+	if d == nil {
+		return allowed
+	}
+	for _, statement := range d.Statement {
+		if !statement.matches(r) {
+			continue
+		}
+		if statement.Effect == PolicyEffectDeny {
+			return false
+		}
+		allowed = true
+	}
+	return allowed
+}
+
+func (s PolicyStatement) matches(r PolicyRequest) bool {
+	if !s.Principal.matches(r) {
+		return false
+	}
+	if !s.Action.matchAny(strings.ToLower(r.Action), true) {
+		return false
+	}
+	if len(s.Resource) > 0 && !s.Resource.matchAny(r.Resource, false) {
+		return false
+	}
+	for operator, conditions := range s.Condition {
+		for key, values := range conditions {
+			if !conditionHolds(operator, conditionValue(key, r), values) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (p PolicyPrincipal) matches(r PolicyRequest) bool {
+	if p.All {
+		return true
+	}
+	for _, principal := range p.AWS {
+		if principal == "*" {
+			return true
+		}
+		if r.Service != "" {
+			continue
+		}
+		// An account ID, its root or any user or role in it.
+		if principal == r.AccountId || (strings.HasPrefix(principal, "arn:") && arnAccount(principal) == r.AccountId) {
+			return true
+		}
+	}
+	for _, service := range p.Service {
+		if r.Service != "" && service == r.Service {
+			return true
+		}
+	}
+	return false
+}
+
+func (v PolicyValues) matchAny(value string, ignoreCase bool) bool {
+	for _, pattern := range v {
+		if ignoreCase {
+			pattern = strings.ToLower(pattern)
+		}
+		if wildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionValue is the value of a condition key for the request, nil when the request has none.
+func conditionValue(key string, r PolicyRequest) *string {
+	var value string
+	switch strings.ToLower(key) {
+	case "aws:sourcearn":
+		value = r.SourceArn
+	case "aws:sourceaccount":
+		value = arnAccount(r.SourceArn)
+	case "aws:principalaccount":
+		if r.Service == "" {
+			value = r.AccountId
+		}
+	}
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// conditionHolds applies a condition operator, like IAM a key the request doesn't have only satisfies the
+// negated and `IfExists` ones.  Operators that aren't supported never hold.
+func conditionHolds(operator string, value *string, values PolicyValues) bool {
+	ifExists := strings.HasSuffix(operator, "IfExists")
+	operator = strings.TrimSuffix(operator, "IfExists")
+	negated := strings.Contains(operator, "Not")
+	if value == nil {
+		return ifExists || negated
+	}
+
+	var match bool
+	switch operator {
+	case "StringEquals", "StringNotEquals", "ArnEquals", "ArnNotEquals":
+		for _, v := range values {
+			match = match || v == *value
+		}
+	case "StringEqualsIgnoreCase", "StringNotEqualsIgnoreCase":
+		for _, v := range values {
+			match = match || strings.EqualFold(v, *value)
+		}
+	case "StringLike", "StringNotLike", "ArnLike", "ArnNotLike":
+		match = values.matchAny(*value, false)
+	default:
+		return false
+	}
+	return match != negated
+}
+
+// wildcardMatch matches a value against a pattern where `*` is any run of characters and `?` any one.
+func wildcardMatch(pattern string, value string) bool {
+	p, v := 0, 0
+	star, mark := -1, 0
+	for v < len(value) {
+		switch {
+		case p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]):
+			p++
+			v++
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, v
+			p++
+		case star >= 0:
+			p = star + 1
+			mark++
+			v = mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// arnAccount is the account ID out of an ARN, `arn:partition:service:region:account:resource`.
+func arnAccount(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return ""
+	}
+	return parts[4]
+}
+
+// UnmarshalJSON takes a single statement as well as a list of them.
+func (d *PolicyDocument) UnmarshalJSON(data []byte) error {
+	var doc struct {
+		Version   string          `json:"Version"`
+		Id        string          `json:"Id"`
+		Statement json.RawMessage `json:"Statement"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	d.Version = doc.Version
+	d.Id = doc.Id
+	d.Statement = nil
+	if len(doc.Statement) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(doc.Statement, &d.Statement); err == nil {
+		return nil
+	}
+	statement := PolicyStatement{}
+	if err := json.Unmarshal(doc.Statement, &statement); err != nil {
+		return err
+	}
+	d.Statement = []PolicyStatement{statement}
+	return nil
+}
+
+func (p *PolicyPrincipal) UnmarshalJSON(data []byte) error {
+	var all string
+	if err := json.Unmarshal(data, &all); err == nil {
+		if all != "*" {
+			return fmt.Errorf("invalid principal: %s", all)
+		}
+		*p = PolicyPrincipal{All: true}
+		return nil
+	}
+	var principals struct {
+		AWS     PolicyValues `json:"AWS"`
+		Service PolicyValues `json:"Service"`
+	}
+	if err := json.Unmarshal(data, &principals); err != nil {
+		return err
+	}
+	*p = PolicyPrincipal{AWS: principals.AWS, Service: principals.Service}
+	return nil
+}
+
+func (p PolicyPrincipal) MarshalJSON() ([]byte, error) {
+	if p.All {
+		return json.Marshal("*")
+	}
+	principals := map[string]PolicyValues{}
+	if len(p.AWS) > 0 {
+		principals["AWS"] = p.AWS
+	}
+	if len(p.Service) > 0 {
+		principals["Service"] = p.Service
+	}
+	return json.Marshal(principals)
+}
+
+// UnmarshalJSON takes a single value as well as a list, conditions can have booleans and numbers too.
+func (v *PolicyValues) UnmarshalJSON(data []byte) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		list = []interface{}{raw}
+	}
+	*v = make(PolicyValues, 0, len(list))
+	for _, item := range list {
+		switch value := item.(type) {
+		case string:
+			*v = append(*v, value)
+		case bool, float64:
+			*v = append(*v, fmt.Sprint(value))
+		default:
+			return fmt.Errorf("invalid policy value: %v", item)
+		}
+	}
+	return nil
+}
+
+func (v PolicyValues) MarshalJSON() ([]byte, error) {
+	if len(v) == 1 {
+		return json.Marshal(v[0])
+	}
+	return json.Marshal([]string(v))
+}
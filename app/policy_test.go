@@ -0,0 +1,124 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const policyQueueArn = "arn:aws:sqs:us-east-1:100010001000:policy-queue"
+
+func TestParsePolicy_accepts_single_values(t *testing.T) {
+	doc, err := ParsePolicy(`{"Version":"2012-10-17","Statement":{"Effect":"Allow","Principal":{"AWS":"200020002000"},"Action":"sqs:SendMessage"}}`)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []PolicyStatement{{
+		Effect:    PolicyEffectAllow,
+		Principal: PolicyPrincipal{AWS: PolicyValues{"200020002000"}},
+		Action:    PolicyValues{"sqs:SendMessage"},
+	}}, doc.Statement)
+}
+
+func TestParsePolicy_round_trips(t *testing.T) {
+	text := `{"Version":"2012-10-17","Statement":[{"Sid":"s1","Effect":"Deny","Principal":"*","Action":["sqs:SendMessage","sqs:ReceiveMessage"],"Resource":"arn:aws:sqs:*","Condition":{"ArnLike":{"aws:SourceArn":"arn:aws:sns:*"}}}]}`
+	doc, err := ParsePolicy(text)
+	assert.Nil(t, err)
+
+	marshalled, err := json.Marshal(doc)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, text, string(marshalled))
+}
+
+func TestParsePolicy_invalid(t *testing.T) {
+	for _, text := range []string{
+		`not json`,
+		`{"Version":"2012-10-17"}`,
+		`{"Statement":[{"Effect":"Maybe","Principal":"*","Action":"*"}]}`,
+		`{"Statement":[{"Effect":"Allow","Action":"*"}]}`,
+		`{"Statement":[{"Effect":"Allow","Principal":"someone","Action":"*"}]}`,
+		`{"Statement":[{"Effect":"Allow","Principal":"*"}]}`,
+	} {
+		_, err := ParsePolicy(text)
+		assert.NotNil(t, err, text)
+	}
+}
+
+func TestPolicyDocument_Allows_owner_without_policy(t *testing.T) {
+	var doc *PolicyDocument
+
+	assert.True(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, AccountId: "100010001000"}))
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, AccountId: "200020002000"}))
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, Service: "sns.amazonaws.com"}))
+}
+
+func TestPolicyDocument_Allows_other_account(t *testing.T) {
+	doc, _ := ParsePolicy(`{"Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::200020002000:root"},"Action":"SQS:Send*","Resource":"` + policyQueueArn + `"}]}`)
+
+	assert.True(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, AccountId: "200020002000"}))
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:ReceiveMessage", Resource: policyQueueArn, AccountId: "200020002000"}))
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, AccountId: "300030003000"}))
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn + "-other", AccountId: "200020002000"}))
+}
+
+func TestPolicyDocument_Allows_deny_wins(t *testing.T) {
+	doc, _ := ParsePolicy(`{"Statement":[
+		{"Effect":"Allow","Principal":"*","Action":"sqs:*"},
+		{"Effect":"Deny","Principal":{"AWS":"100010001000"},"Action":"sqs:DeleteQueue"}
+	]}`)
+
+	assert.True(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, AccountId: "200020002000"}))
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:DeleteQueue", Resource: policyQueueArn, AccountId: "100010001000"}))
+}
+
+func TestPolicyDocument_Allows_service_with_source_arn(t *testing.T) {
+	doc, _ := ParsePolicy(`{"Statement":[{"Effect":"Allow","Principal":{"Service":"sns.amazonaws.com"},"Action":"sqs:SendMessage",
+		"Condition":{"ArnEquals":{"aws:SourceArn":"arn:aws:sns:us-east-1:100010001000:allowed-topic"}}}]}`)
+
+	assert.True(t, doc.Allows(PolicyRequest{
+		Action: "sqs:SendMessage", Resource: policyQueueArn,
+		Service: "sns.amazonaws.com", SourceArn: "arn:aws:sns:us-east-1:100010001000:allowed-topic",
+	}))
+	assert.False(t, doc.Allows(PolicyRequest{
+		Action: "sqs:SendMessage", Resource: policyQueueArn,
+		Service: "sns.amazonaws.com", SourceArn: "arn:aws:sns:us-east-1:100010001000:other-topic",
+	}))
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, AccountId: "200020002000"}))
+}
+
+func TestPolicyDocument_Allows_conditions(t *testing.T) {
+	doc, _ := ParsePolicy(`{"Statement":[{"Effect":"Allow","Principal":"*","Action":"sqs:SendMessage",
+		"Condition":{"StringNotEqualsIfExists":{"aws:PrincipalAccount":"300030003000"},"StringLike":{"aws:SourceAccount":"1000*"}}}]}`)
+
+	assert.True(t, doc.Allows(PolicyRequest{
+		Action: "sqs:SendMessage", Resource: policyQueueArn,
+		Service: "sns.amazonaws.com", SourceArn: "arn:aws:sns:us-east-1:100010001000:topic",
+	}))
+	assert.False(t, doc.Allows(PolicyRequest{
+		Action: "sqs:SendMessage", Resource: policyQueueArn,
+		Service: "sns.amazonaws.com", SourceArn: "arn:aws:sns:us-east-1:200020002000:topic",
+	}))
+	// Without a source account the StringLike can't hold.
+	assert.False(t, doc.Allows(PolicyRequest{Action: "sqs:SendMessage", Resource: policyQueueArn, AccountId: "200020002000"}))
+}
+
+func TestQueue_Allows(t *testing.T) {
+	q := &Queue{
+		Arn:    policyQueueArn,
+		Policy: `{"Statement":[{"Effect":"Allow","Principal":{"AWS":"200020002000"},"Action":"sqs:ReceiveMessage"}]}`,
+	}
+
+	assert.True(t, q.Allows(PolicyRequest{Action: "sqs:ReceiveMessage", AccountId: "200020002000"}))
+	assert.True(t, q.Allows(PolicyRequest{Action: "sqs:DeleteQueue", AccountId: "100010001000"}))
+	assert.False(t, q.Allows(PolicyRequest{Action: "sqs:DeleteQueue", AccountId: "200020002000"}))
+}
+
+func TestWildcardMatch(t *testing.T) {
+	assert.True(t, wildcardMatch("*", ""))
+	assert.True(t, wildcardMatch("sqs:*", "sqs:sendmessage"))
+	assert.True(t, wildcardMatch("arn:*:queue-?", "arn:aws:sqs:queue-1"))
+	assert.True(t, wildcardMatch("a*b*c", "aXbYbZc"))
+	assert.False(t, wildcardMatch("a*b*c", "aXbYbZ"))
+	assert.False(t, wildcardMatch("sqs:Send", "sqs:SendMessage"))
+}
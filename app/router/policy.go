@@ -0,0 +1,81 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Admiral-Piett/goaws/app"
+)
+
+// queuePolicyActions are the actions on a single queue, by its QueueUrl, with the policy action each needs.
+var queuePolicyActions = map[string]string{
+	"GetQueueAttributes":           "sqs:GetQueueAttributes",
+	"SetQueueAttributes":           "sqs:SetQueueAttributes",
+	"SendMessage":                  "sqs:SendMessage",
+	"SendMessageBatch":             "sqs:SendMessage",
+	"ReceiveMessage":               "sqs:ReceiveMessage",
+	"ChangeMessageVisibility":      "sqs:ChangeMessageVisibility",
+	"ChangeMessageVisibilityBatch": "sqs:ChangeMessageVisibility",
+	"DeleteMessage":                "sqs:DeleteMessage",
+	"DeleteMessageBatch":           "sqs:DeleteMessage",
+	"PurgeQueue":                   "sqs:PurgeQueue",
+	"DeleteQueue":                  "sqs:DeleteQueue",
+	"TagQueue":                     "sqs:TagQueue",
+	"UntagQueue":                   "sqs:UntagQueue",
+	"ListQueueTags":                "sqs:ListQueueTags",
+	"ListDeadLetterSourceQueues":   "sqs:ListDeadLetterSourceQueues",
+	"AddPermission":                "sqs:AddPermission",
+	"RemovePermission":             "sqs:RemovePermission",
+}
+
+// authorizeQueueAction evaluates the policy of the queue the request is for, if it's for one, and tells
+// whether the request may go on.  Requests for queues that don't exist are left to their handlers.
+func authorizeQueueAction(req *http.Request, action string) bool {
+	policyAction, ok := queuePolicyActions[action]
+	if !ok {
+		return true
+	}
+	uriSegments := strings.Split(requestQueueUrl(req), "/")
+	queue, ok := app.SyncQueues.Get(uriSegments[len(uriSegments)-1])
+	if !ok {
+		return true
+	}
+	return queue.Allows(app.PolicyRequest{Action: policyAction, AccountId: callerAccountId(req)})
+}
+
+// requestQueueUrl reads the QueueUrl out of the request, putting a JSON body back for the handler.
+func requestQueueUrl(req *http.Request) string {
+	if resolveProtocol(req) == AwsQueryProtocol {
+		return req.FormValue("QueueUrl")
+	}
+	if req.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var request struct {
+		QueueUrl string `json:"QueueUrl"`
+	}
+	json.Unmarshal(body, &request)
+	return request.QueueUrl
+}
+
+// callerAccountId is the account the caller belongs to, that of the credential the request is signed
+// with if requests are, the configured one otherwise.
+func callerAccountId(req *http.Request) string {
+	account := app.CurrentEnvironment.PolicyEvaluation.CallerAccountId
+	if app.CurrentEnvironment.Authentication.Enabled {
+		credential, _ := signingCredential(req)
+		account = credential.AccountId
+	}
+	if account == "" {
+		account = app.CurrentEnvironment.AccountID
+	}
+	return account
+}
@@ -0,0 +1,114 @@
+package router
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/mocks"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const policyQueueUrl = "http://us-east-1.localhost:4200/100010001000/policy-queue"
+
+func setPolicyEnvironment(policy string) func() {
+	previous := app.CurrentEnvironment
+	previousRoutes := routingTableV1
+	app.CurrentEnvironment.AccountID = "100010001000"
+	app.CurrentEnvironment.PolicyEvaluation = app.EnvPolicyEvaluation{Enabled: true, CallerAccountId: "200020002000"}
+	app.SyncQueues.Queues["policy-queue"] = &app.Queue{
+		Name:   "policy-queue",
+		URL:    policyQueueUrl,
+		Arn:    "arn:aws:sqs:us-east-1:100010001000:policy-queue",
+		Policy: policy,
+	}
+	return func() {
+		app.CurrentEnvironment = previous
+		delete(app.SyncQueues.Queues, "policy-queue")
+		routingTableV1 = previousRoutes
+	}
+}
+
+func TestActionHandler_denies_by_queue_policy(t *testing.T) {
+	defer setPolicyEnvironment("")()
+
+	mockCalled := false
+	routingTableV1 = map[string]func(r *http.Request) (int, interfaces.AbstractResponseBody){
+		"SendMessage": func(req *http.Request) (int, interfaces.AbstractResponseBody) {
+			mockCalled = true
+			return http.StatusOK, mocks.BaseResponse{Message: "response-body"}
+		},
+	}
+
+	w, r := test.GenerateRequestInfo("POST", "/", nil, false)
+	form := url.Values{}
+	form.Add("Action", "SendMessage")
+	form.Add("QueueUrl", policyQueueUrl)
+	r.PostForm = form
+
+	actionHandler(w, r)
+
+	assert.False(t, mockCalled)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	errorResponse := models.ErrorResponse{}
+	xml.Unmarshal(w.Body.Bytes(), &errorResponse)
+	assert.Equal(t, "AccessDenied", errorResponse.Result.Code)
+}
+
+func TestActionHandler_allows_by_queue_policy_json(t *testing.T) {
+	defer setPolicyEnvironment(`{"Statement":[{"Effect":"Allow","Principal":{"AWS":"200020002000"},"Action":"sqs:SendMessage"}]}`)()
+
+	body := ""
+	routingTableV1 = map[string]func(r *http.Request) (int, interfaces.AbstractResponseBody){
+		"SendMessage": func(req *http.Request) (int, interfaces.AbstractResponseBody) {
+			b, _ := io.ReadAll(req.Body)
+			body = string(b)
+			return http.StatusOK, mocks.BaseResponse{Message: "response-body"}
+		},
+		"PurgeQueue": func(req *http.Request) (int, interfaces.AbstractResponseBody) {
+			return http.StatusOK, mocks.BaseResponse{Message: "response-body"}
+		},
+	}
+
+	requestBody := `{"QueueUrl":"` + policyQueueUrl + `","MessageBody":"hello"}`
+	r := httptest.NewRequest("POST", "/", strings.NewReader(requestBody))
+	r.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	r.Header.Set("X-Amz-Target", "AmazonSQS.SendMessage")
+	w := httptest.NewRecorder()
+
+	actionHandler(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, requestBody, body)
+
+	r = httptest.NewRequest("POST", "/", strings.NewReader(`{"QueueUrl":"`+policyQueueUrl+`"}`))
+	r.Header.Set("Content-Type", "application/x-amz-json-1.0")
+	r.Header.Set("X-Amz-Target", "AmazonSQS.PurgeQueue")
+	w = httptest.NewRecorder()
+
+	actionHandler(w, r)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCallerAccountId(t *testing.T) {
+	defer setAuthenticationEnvironment()()
+	app.CurrentEnvironment.AccountID = "100010001000"
+	app.CurrentEnvironment.Authentication.Credentials = append(app.CurrentEnvironment.Authentication.Credentials,
+		app.EnvCredential{AccessKeyId: "OTHER", SecretAccessKey: "secret", AccountId: "300030003000"})
+
+	assert.Equal(t, "100010001000", callerAccountId(signedRequest(t, "Action=ListQueues", "AKID", "secret", "sqs", "us-east-1")))
+	assert.Equal(t, "300030003000", callerAccountId(signedRequest(t, "Action=ListQueues", "OTHER", "secret", "sqs", "us-east-1")))
+
+	app.CurrentEnvironment.Authentication.Enabled = false
+	app.CurrentEnvironment.PolicyEvaluation.CallerAccountId = "200020002000"
+	assert.Equal(t, "200020002000", callerAccountId(httptest.NewRequest("POST", "/", nil)))
+}
@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/utils"
 
 	log "github.com/sirupsen/logrus"
 
@@ -64,24 +66,35 @@ func encodeResponse(w http.ResponseWriter, req *http.Request, statusCode int, bo
 // V1 - includes JSON Support (and of course the old XML).
 var routingTableV1 = map[string]func(r *http.Request) (int, interfaces.AbstractResponseBody){
 	// SQS
-	"CreateQueue":             sqs.CreateQueueV1,
-	"ListQueues":              sqs.ListQueuesV1,
-	"GetQueueAttributes":      sqs.GetQueueAttributesV1,
-	"SetQueueAttributes":      sqs.SetQueueAttributesV1,
-	"SendMessage":             sqs.SendMessageV1,
-	"ReceiveMessage":          sqs.ReceiveMessageV1,
-	"ChangeMessageVisibility": sqs.ChangeMessageVisibilityV1,
-	"DeleteMessage":           sqs.DeleteMessageV1,
-	"GetQueueUrl":             sqs.GetQueueUrlV1,
-	"PurgeQueue":              sqs.PurgeQueueV1,
-	"DeleteQueue":             sqs.DeleteQueueV1,
-	"SendMessageBatch":        sqs.SendMessageBatchV1,
-	"DeleteMessageBatch":      sqs.DeleteMessageBatchV1,
+	"CreateQueue":                  sqs.CreateQueueV1,
+	"ListQueues":                   sqs.ListQueuesV1,
+	"GetQueueAttributes":           sqs.GetQueueAttributesV1,
+	"SetQueueAttributes":           sqs.SetQueueAttributesV1,
+	"SendMessage":                  sqs.SendMessageV1,
+	"ReceiveMessage":               sqs.ReceiveMessageV1,
+	"ChangeMessageVisibility":      sqs.ChangeMessageVisibilityV1,
+	"ChangeMessageVisibilityBatch": sqs.ChangeMessageVisibilityBatchV1,
+	"DeleteMessage":                sqs.DeleteMessageV1,
+	"GetQueueUrl":                  sqs.GetQueueUrlV1,
+	"PurgeQueue":                   sqs.PurgeQueueV1,
+	"DeleteQueue":                  sqs.DeleteQueueV1,
+	"SendMessageBatch":             sqs.SendMessageBatchV1,
+	"DeleteMessageBatch":           sqs.DeleteMessageBatchV1,
+	"TagQueue":                     sqs.TagQueueV1,
+	"UntagQueue":                   sqs.UntagQueueV1,
+	"ListQueueTags":                sqs.ListQueueTagsV1,
+	"ListDeadLetterSourceQueues":   sqs.ListDeadLetterSourceQueuesV1,
+	"StartMessageMoveTask":         sqs.StartMessageMoveTaskV1,
+	"ListMessageMoveTasks":         sqs.ListMessageMoveTasksV1,
+	"CancelMessageMoveTask":        sqs.CancelMessageMoveTaskV1,
+	"AddPermission":                sqs.AddPermissionV1,
+	"RemovePermission":             sqs.RemovePermissionV1,
 
 	// SNS
 	"Subscribe":                 sns.SubscribeV1,
 	"Unsubscribe":               sns.UnsubscribeV1,
 	"Publish":                   sns.PublishV1,
+	"PublishBatch":              sns.PublishBatchV1,
 	"ListTopics":                sns.ListTopicsV1,
 	"CreateTopic":               sns.CreateTopicV1,
 	"DeleteTopic":               sns.DeleteTopicV1,
@@ -89,6 +102,8 @@ var routingTableV1 = map[string]func(r *http.Request) (int, interfaces.AbstractR
 	"GetSubscriptionAttributes": sns.GetSubscriptionAttributesV1,
 	"SetSubscriptionAttributes": sns.SetSubscriptionAttributesV1,
 	"ListSubscriptionsByTopic":  sns.ListSubscriptionsByTopicV1,
+	"GetTopicAttributes":        sns.GetTopicAttributesV1,
+	"SetTopicAttributes":        sns.SetTopicAttributesV1,
 
 	// SNS Internal
 	"ConfirmSubscription": sns.ConfirmSubscriptionV1,
@@ -100,12 +115,28 @@ func health(w http.ResponseWriter, req *http.Request) {
 }
 
 func actionHandler(w http.ResponseWriter, req *http.Request) {
+	// The signature has to be checked before anything parses the form out of the body.
+	if app.CurrentEnvironment.Authentication.Enabled {
+		if errKey, isSqs := verifySignature(req); errKey != "" {
+			log.Warnf("Rejecting request with a bad signature: %s", errKey)
+			statusCode, responseBody := utils.CreateErrorResponseV1(errKey, isSqs)
+			encodeResponse(w, req, statusCode, responseBody)
+			return
+		}
+	}
+
 	action := extractAction(req)
 	log.WithFields(
 		log.Fields{
 			"action": action,
 			"url":    req.URL,
 		}).Debug("Handling URL request")
+	if app.CurrentEnvironment.PolicyEvaluation.Enabled && !authorizeQueueAction(req, action) {
+		log.Warnf("Denying %s by the policy of the queue", action)
+		statusCode, responseBody := utils.CreateErrorResponseV1("AccessDenied", true)
+		encodeResponse(w, req, statusCode, responseBody)
+		return
+	}
 	// If we don't find a match in this table, pass on to the existing flow.
 	jsonFn, ok := routingTableV1[action]
 	if ok {
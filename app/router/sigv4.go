@@ -0,0 +1,201 @@
+package router
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+)
+
+const (
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat  = "20060102T150405Z"
+	// Like AWS, a signed request is only good this long either side of its X-Amz-Date, so a captured one
+	// can't be replayed forever.
+	maxSignatureSkew = 15 * time.Minute
+)
+
+// verifySignature checks the AWS Signature Version 4 `Authorization` header of `req` against the
+// configured credentials.  It returns the key of the error to respond with (empty when the signature is
+// good), and whether the request was signed for SQS rather than SNS, so the error can be shaped to match.
+// The body is read to hash it, and put back for the handler.
+func verifySignature(req *http.Request) (string, bool) {
+	// Until we know better from the credential scope, errors take the SQS shape.
+	isSqs := true
+
+	authorization := req.Header.Get("Authorization")
+	if authorization == "" {
+		return "MissingAuthenticationToken", isSqs
+	}
+	if !strings.HasPrefix(authorization, sigV4Algorithm+" ") {
+		return "InvalidClientTokenId", isSqs
+	}
+	fields := authorizationFields(authorization)
+
+	// Credential=<access key>/<yyyymmdd>/<region>/<service>/aws4_request
+	scope := strings.Split(fields["Credential"], "/")
+	if len(scope) != 5 || scope[4] != "aws4_request" {
+		return "SignatureDoesNotMatch", isSqs
+	}
+	isSqs = scope[3] != "sns"
+
+	credential, ok := findCredential(scope[0])
+	if !ok || credential.SecretAccessKey == "" {
+		return "InvalidClientTokenId", isSqs
+	}
+	secret := credential.SecretAccessKey
+
+	// A client set up for the wrong region would otherwise sign happily with it.
+	if scope[2] != app.CurrentEnvironment.Region {
+		return "SignatureDoesNotMatch", isSqs
+	}
+
+	amzDate := req.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(amzDate, scope[1]) || fields["SignedHeaders"] == "" || fields["Signature"] == "" {
+		return "SignatureDoesNotMatch", isSqs
+	}
+	signedAt, err := time.Parse(amzDateFormat, amzDate)
+	if err != nil {
+		return "SignatureDoesNotMatch", isSqs
+	}
+	if skew := time.Since(signedAt); skew > maxSignatureSkew || skew < -maxSignatureSkew {
+		return "SignatureExpired", isSqs
+	}
+
+	body := []byte{}
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return "SignatureDoesNotMatch", isSqs
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req),
+		canonicalQuery(req),
+		canonicalHeaders(req, fields["SignedHeaders"]),
+		fields["SignedHeaders"],
+		hashHex(body),
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		strings.Join(scope[1:], "/"),
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := []byte("AWS4" + secret)
+	for _, part := range scope[1:] {
+		key = hmacSHA256(key, part)
+	}
+	expected := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	if !hmac.Equal([]byte(expected), []byte(fields["Signature"])) {
+		return "SignatureDoesNotMatch", isSqs
+	}
+	return "", isSqs
+}
+
+// signingCredential is the configured credential the request is signed with, if any.
+func signingCredential(req *http.Request) (app.EnvCredential, bool) {
+	authorization := req.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, sigV4Algorithm+" ") {
+		return app.EnvCredential{}, false
+	}
+	scope := strings.Split(authorizationFields(authorization)["Credential"], "/")
+	return findCredential(scope[0])
+}
+
+func authorizationFields(authorization string) map[string]string {
+	fields := map[string]string{}
+	for _, field := range strings.Split(strings.TrimPrefix(authorization, sigV4Algorithm+" "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) == 2 {
+			fields[kv[0]] = kv[1]
+		}
+	}
+	return fields
+}
+
+func findCredential(accessKeyId string) (app.EnvCredential, bool) {
+	for _, credential := range app.CurrentEnvironment.Authentication.Credentials {
+		if credential.AccessKeyId == accessKeyId {
+			return credential, true
+		}
+	}
+	return app.EnvCredential{}, false
+}
+
+func canonicalURI(req *http.Request) string {
+	path := req.URL.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	// The SDKs escape the already escaped path once more for every service but S3.
+	return uriEncode(path, false)
+}
+
+func canonicalQuery(req *http.Request) string {
+	params := []string{}
+	for key, values := range req.URL.Query() {
+		for _, value := range values {
+			params = append(params, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+func canonicalHeaders(req *http.Request, signedHeaders string) string {
+	var b strings.Builder
+	for _, name := range strings.Split(signedHeaders, ";") {
+		values := []string{}
+		switch name {
+		case "host":
+			values = append(values, req.Host)
+		case "content-length":
+			values = append(values, strconv.FormatInt(req.ContentLength, 10))
+		default:
+			for _, value := range req.Header.Values(name) {
+				values = append(values, strings.Join(strings.Fields(value), " "))
+			}
+		}
+		b.WriteString(name + ":" + strings.Join(values, ",") + "\n")
+	}
+	return b.String()
+}
+
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' && !encodeSlash {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
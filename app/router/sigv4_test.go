@@ -0,0 +1,152 @@
+package router
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/interfaces"
+	"github.com/Admiral-Piett/goaws/app/mocks"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/stretchr/testify/assert"
+
+	sqs "github.com/Admiral-Piett/goaws/app/gosqs"
+)
+
+func signedRequest(t *testing.T, body string, accessKey string, secret string, service string, region string) *http.Request {
+	return signedRequestAt(t, body, accessKey, secret, service, region, time.Now())
+}
+
+func signedRequestAt(t *testing.T, body string, accessKey string, secret string, service string, region string, signingTime time.Time) *http.Request {
+	req := httptest.NewRequest("POST", "/100010001000/my%20queue?Version=2012-11-05", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	sum := sha256.Sum256([]byte(body))
+	creds := aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: secret}
+	err := v4.NewSigner().SignHTTP(context.TODO(), creds, req, hex.EncodeToString(sum[:]), service, region, signingTime)
+	assert.Nil(t, err)
+	return req
+}
+
+func setAuthenticationEnvironment() func() {
+	previous := app.CurrentEnvironment
+	app.CurrentEnvironment.Region = "us-east-1"
+	app.CurrentEnvironment.Authentication = app.EnvAuthentication{
+		Enabled: true,
+		Credentials: []app.EnvCredential{
+			{AccessKeyId: "AKID", SecretAccessKey: "secret"},
+		},
+	}
+	return func() {
+		app.CurrentEnvironment = previous
+	}
+}
+
+func TestVerifySignature_success(t *testing.T) {
+	defer setAuthenticationEnvironment()()
+
+	req := signedRequest(t, "Action=ListQueues", "AKID", "secret", "sqs", "us-east-1")
+	errKey, isSqs := verifySignature(req)
+
+	assert.Equal(t, "", errKey)
+	assert.True(t, isSqs)
+	body, _ := io.ReadAll(req.Body)
+	assert.Equal(t, "Action=ListQueues", string(body))
+}
+
+func TestVerifySignature_errors(t *testing.T) {
+	defer setAuthenticationEnvironment()()
+
+	req := signedRequest(t, "Action=ListTopics", "AKID", "wrong", "sns", "us-east-1")
+	errKey, isSqs := verifySignature(req)
+	assert.Equal(t, "SignatureDoesNotMatch", errKey)
+	assert.False(t, isSqs)
+
+	req = signedRequest(t, "Action=ListQueues", "AKID", "secret", "sqs", "eu-west-1")
+	errKey, _ = verifySignature(req)
+	assert.Equal(t, "SignatureDoesNotMatch", errKey)
+
+	req = signedRequest(t, "Action=ListQueues", "UNKNOWN", "secret", "sqs", "us-east-1")
+	errKey, _ = verifySignature(req)
+	assert.Equal(t, "InvalidClientTokenId", errKey)
+
+	req = signedRequest(t, "Action=ListQueues", "AKID", "secret", "sqs", "us-east-1")
+	req.Body = io.NopCloser(strings.NewReader("Action=PurgeQueue"))
+	errKey, _ = verifySignature(req)
+	assert.Equal(t, "SignatureDoesNotMatch", errKey)
+
+	req = httptest.NewRequest("POST", "/", strings.NewReader("Action=ListQueues"))
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	errKey, _ = verifySignature(req)
+	assert.Equal(t, "InvalidClientTokenId", errKey)
+}
+
+func TestVerifySignature_missing_authorization(t *testing.T) {
+	defer setAuthenticationEnvironment()()
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader("Action=ListQueues"))
+	errKey, isSqs := verifySignature(req)
+
+	assert.Equal(t, "MissingAuthenticationToken", errKey)
+	assert.True(t, isSqs)
+	assert.Equal(t, "MissingAuthenticationToken", models.SqsErrors[errKey].Code)
+}
+
+func TestVerifySignature_expired(t *testing.T) {
+	defer setAuthenticationEnvironment()()
+
+	req := signedRequestAt(t, "Action=ListTopics", "AKID", "secret", "sns", "us-east-1", time.Now().Add(-16*time.Minute))
+	errKey, isSqs := verifySignature(req)
+	assert.Equal(t, "SignatureExpired", errKey)
+	assert.False(t, isSqs)
+	assert.Equal(t, "SignatureDoesNotMatch", models.SnsErrors[errKey].Code)
+
+	req = signedRequestAt(t, "Action=ListQueues", "AKID", "secret", "sqs", "us-east-1", time.Now().Add(16*time.Minute))
+	errKey, _ = verifySignature(req)
+	assert.Equal(t, "SignatureExpired", errKey)
+
+	req = signedRequestAt(t, "Action=ListQueues", "AKID", "secret", "sqs", "us-east-1", time.Now().Add(-14*time.Minute))
+	errKey, _ = verifySignature(req)
+	assert.Equal(t, "", errKey)
+}
+
+func TestActionHandler_rejects_bad_signature(t *testing.T) {
+	defer setAuthenticationEnvironment()()
+	defer func() {
+		routingTableV1 = map[string]func(r *http.Request) (int, interfaces.AbstractResponseBody){
+			"CreateQueue": sqs.CreateQueueV1,
+		}
+	}()
+
+	mockCalled := false
+	routingTableV1 = map[string]func(r *http.Request) (int, interfaces.AbstractResponseBody){
+		"ListQueues": func(req *http.Request) (int, interfaces.AbstractResponseBody) {
+			mockCalled = true
+			return http.StatusOK, mocks.BaseResponse{Message: "response-body"}
+		},
+	}
+
+	w := httptest.NewRecorder()
+	actionHandler(w, signedRequest(t, "Action=ListQueues", "AKID", "wrong", "sqs", "us-east-1"))
+
+	assert.False(t, mockCalled)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	errorResponse := models.ErrorResponse{}
+	xml.Unmarshal(w.Body.Bytes(), &errorResponse)
+	assert.Equal(t, "SignatureDoesNotMatch", errorResponse.Result.Code)
+
+	w = httptest.NewRecorder()
+	actionHandler(w, signedRequest(t, "Action=ListQueues", "AKID", "secret", "sqs", "us-east-1"))
+
+	assert.True(t, mockCalled)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -1,7 +1,12 @@
 package app
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
 )
 
 type MsgAttr struct {
@@ -22,6 +27,7 @@ type SNSMessage struct {
 	UnsubscribeURL    string
 	SubscribeURL      string             `json:"SubscribeURL",omitempty`
 	MessageAttributes map[string]MsgAttr `json:"MessageAttributes",omitempty`
+	SequenceNumber    string             `json:"SequenceNumber,omitempty"` // only from FIFO topics
 }
 
 type Subscription struct {
@@ -47,11 +53,11 @@ func (fp *FilterPolicy) IsSatisfiedBy(msgAttrs map[string]MessageAttributeValue)
 		// String, String.Array, Number data-types are allowed by SNS filter policies
 		// however go-AWS currently only supports String filter policies. That feature can be added here
 		// ref: https://docs.aws.amazon.com/sns/latest/dg/message-filtering.html
-		if attrValue.DataType != "String" {
+		if attrValue.BaseType() != "String" {
 			return false
 		}
 
-		if !stringInSlice(attrValue.Value, policyAttrValues) {
+		if !stringInSlice(attrValue.StringValue, policyAttrValues) {
 			return false // the attribute value has to be among filtered ones
 		}
 	}
@@ -69,9 +75,65 @@ func stringInSlice(a string, list []string) bool {
 }
 
 type Topic struct {
-	Name          string
-	Arn           string
-	Subscriptions []*Subscription
+	Name                      string
+	Arn                       string
+	Subscriptions             []*Subscription
+	SubscriptionsDeleted      int // how many subscriptions were ever removed from it
+	DisplayName               string
+	Policy                    string // empty for the default policy
+	DeliveryPolicy            string // empty for the default delivery policy
+	FifoTopic                 bool
+	ContentBasedDeduplication bool
+	SignatureVersion          string
+	TracingConfig             string
+	KmsMasterKeyId            string
+	ArchivePolicy             string
+	SequenceNumber            int64                   // the last sequence number a FIFO topic handed out
+	Duplicates                map[string]Deduplicated // by the deduplication IDs a FIFO topic published
+
+	// Publishing is held while publishing to a FIFO topic, so that its subscriptions get the messages in
+	// the order of their sequence numbers.  It is taken before any queue lock or `SyncTopics`.
+	Publishing sync.Mutex
+}
+
+func HasFIFOTopicName(topicName string) bool {
+	return strings.HasSuffix(topicName, ".fifo")
+}
+
+// DeduplicationId is the ID a FIFO topic deduplicates a message by, with ContentBasedDeduplication it
+// defaults to the SHA-256 of the message.
+func (t *Topic) DeduplicationId(deduplicationId string, message string) string {
+	if deduplicationId != "" || !t.ContentBasedDeduplication {
+		return deduplicationId
+	}
+	sum := sha256.Sum256([]byte(message))
+	return hex.EncodeToString(sum[:])
+}
+
+// Sequence hands out the next sequence number of a FIFO topic to the message, unless its deduplication ID
+// was already published within the DeduplicationPeriod: then it returns the original message, and false.
+// NOTE: the caller must hold `SyncTopics`.
+func (t *Topic) Sequence(deduplicationId string, messageId string, now time.Time) (Deduplicated, bool) {
+	for id, published := range t.Duplicates {
+		if published.Expired(now) {
+			delete(t.Duplicates, id)
+		}
+	}
+	if original, ok := t.Duplicates[deduplicationId]; ok {
+		return original, false
+	}
+
+	next := now.UnixNano()
+	if next <= t.SequenceNumber {
+		next = t.SequenceNumber + 1
+	}
+	t.SequenceNumber = next
+	published := Deduplicated{MessageId: messageId, SequenceNumber: fmt.Sprintf("%020d", next), Sent: now}
+	if t.Duplicates == nil {
+		t.Duplicates = make(map[string]Deduplicated)
+	}
+	t.Duplicates[deduplicationId] = published
+	return published, true
 }
 
 type (
@@ -2,6 +2,9 @@ package app
 
 import (
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestFilterPolicy_IsSatisfiedBy(t *testing.T) {
@@ -12,23 +15,23 @@ func TestFilterPolicy_IsSatisfiedBy(t *testing.T) {
 	}{
 		{
 			&FilterPolicy{"foo": {"bar"}},
-			map[string]MessageAttributeValue{"foo": {DataType: "String", Value: "bar"}},
+			map[string]MessageAttributeValue{"foo": {DataType: "String", StringValue: "bar"}},
 			true,
 		},
 		{
 			&FilterPolicy{"foo": {"bar", "xyz"}},
-			map[string]MessageAttributeValue{"foo": {DataType: "String", Value: "xyz"}},
+			map[string]MessageAttributeValue{"foo": {DataType: "String", StringValue: "xyz"}},
 			true,
 		},
 		{
 			&FilterPolicy{"foo": {"bar", "xyz"}, "abc": {"def"}},
-			map[string]MessageAttributeValue{"foo": {DataType: "String", Value: "xyz"},
-				"abc": {DataType: "String", Value: "def"}},
+			map[string]MessageAttributeValue{"foo": {DataType: "String", StringValue: "xyz"},
+				"abc": {DataType: "String", StringValue: "def"}},
 			true,
 		},
 		{
 			&FilterPolicy{"foo": {"bar"}},
-			map[string]MessageAttributeValue{"foo": {DataType: "String", Value: "baz"}},
+			map[string]MessageAttributeValue{"foo": {DataType: "String", StringValue: "baz"}},
 			false,
 		},
 		{
@@ -38,12 +41,12 @@ func TestFilterPolicy_IsSatisfiedBy(t *testing.T) {
 		},
 		{
 			&FilterPolicy{"foo": {"bar"}, "abc": {"def"}},
-			map[string]MessageAttributeValue{"foo": {DataType: "String", Value: "bar"}},
+			map[string]MessageAttributeValue{"foo": {DataType: "String", StringValue: "bar"}},
 			false,
 		},
 		{
 			&FilterPolicy{"foo": {"bar"}},
-			map[string]MessageAttributeValue{"foo": {DataType: "Binary", Value: "bar"}},
+			map[string]MessageAttributeValue{"foo": {DataType: "Binary", BinaryValue: "bar"}},
 			false,
 		},
 	}
@@ -56,3 +59,35 @@ func TestFilterPolicy_IsSatisfiedBy(t *testing.T) {
 	}
 
 }
+
+func TestTopic_Sequence_deduplicates_within_period(t *testing.T) {
+	topic := &Topic{Name: "topic.fifo", FifoTopic: true}
+	now := time.Now()
+
+	first, ok := topic.Sequence("dedup-1", "id-1", now)
+	assert.True(t, ok)
+	assert.Equal(t, "id-1", first.MessageId)
+	duplicate, ok := topic.Sequence("dedup-1", "id-2", now.Add(time.Minute))
+	assert.False(t, ok)
+	assert.Equal(t, first, duplicate)
+
+	second, ok := topic.Sequence("dedup-2", "id-3", now)
+	assert.True(t, ok)
+	assert.True(t, second.SequenceNumber > first.SequenceNumber)
+
+	third, ok := topic.Sequence("dedup-1", "id-4", now.Add(DeduplicationPeriod+time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, "id-4", third.MessageId)
+	assert.True(t, third.SequenceNumber > second.SequenceNumber)
+	assert.Len(t, topic.Duplicates, 1)
+}
+
+func TestTopic_DeduplicationId(t *testing.T) {
+	topic := &Topic{Name: "topic.fifo", FifoTopic: true}
+	assert.Equal(t, "", topic.DeduplicationId("", "message"))
+	assert.Equal(t, "dedup-1", topic.DeduplicationId("dedup-1", "message"))
+
+	topic.ContentBasedDeduplication = true
+	assert.Equal(t, "dedup-1", topic.DeduplicationId("dedup-1", "message"))
+	assert.Equal(t, "ab530a13e45914982b79f9b7e3fba994cfd1f3fb22f71cea1afbf02b460c6d1d", topic.DeduplicationId("", "message"))
+}
@@ -1,10 +1,13 @@
 package app
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
-	"strconv"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -19,14 +22,20 @@ type Message struct {
 	MD5OfMessageBody       string
 	ReceiptHandle          string
 	ReceiptTime            time.Time
+	FirstReceiveTime       time.Time
 	VisibilityTimeout      time.Time
 	NumberOfReceives       int
 	Retry                  int
 	MessageAttributes      map[string]MessageAttributeValue
 	GroupID                string
 	DeduplicationID        string
+	SequenceNumber         string
+	AWSTraceHeader         string
 	SentTime               time.Time
 	DelaySecs              int
+	// DeadLetterQueueSourceArn is the queue a dead lettered message was moved out of, so that it can
+	// be redriven back there.
+	DeadLetterQueueSourceArn string
 }
 
 func (m *Message) IsReadyForReceipt() bool {
@@ -58,15 +67,28 @@ func getRandomLatency() (time.Duration, error) {
 	return randomDuration, nil
 }
 
+// MessageAttributeValue is a message attribute, the same for both protocols, SQS and SNS.  The DataType is
+// `String`, `Number` or `Binary`, optionally followed by a custom type, e.g. `Number.float`.  Binary values
+// are kept base64 encoded, the way both protocols carry them.
+// NOTE: AWS doesn't accept the list values yet, they're carried along all the same.
 type MessageAttributeValue struct {
-	Name     string
-	DataType string
-	Value    string
-	ValueKey string
+	BinaryListValues []string `json:"BinaryListValues,omitempty"`
+	BinaryValue      string   `json:"BinaryValue,omitempty"`
+	DataType         string   `json:"DataType"`
+	StringListValues []string `json:"StringListValues,omitempty"`
+	StringValue      string   `json:"StringValue,omitempty"`
+}
+
+// BaseType is the DataType without any custom type: `String`, `Number` or `Binary`.
+func (v MessageAttributeValue) BaseType() string {
+	return strings.SplitN(v.DataType, ".", 2)[0]
 }
 
 // TODO - put all this in the models package
+// Queue - the embedded lock guards everything but the name, URL and ARN, which never change once the
+// queue is registered.
 type Queue struct {
+	sync.RWMutex
 	Name                          string
 	URL                           string
 	Arn                           string
@@ -74,74 +96,440 @@ type Queue struct {
 	ReceiveMessageWaitTimeSeconds int
 	DelaySeconds                  int
 	MaximumMessageSize            int
-	MessageRetentionPeriod        int // seconds  // TODO - not used in the code yet
-	Messages                      []Message
+	MessageRetentionPeriod        int // seconds
+	Messages                      MessageStore
 	DeadLetterQueue               *Queue
 	MaxReceiveCount               int
 	IsFIFO                        bool
-	FIFOMessages                  map[string]int
-	FIFOSequenceNumbers           map[string]int
-	EnableDuplicates              bool
-	Duplicates                    map[string]time.Time
+	ContentBasedDeduplication     bool
+	DeduplicationScope            string                    // "queue" unless set to "messageGroup"
+	FifoThroughputLimit           string                    // "perQueue" unless set to "perMessageGroupId"
+	FIFOMessages                  map[string]int            // in flight messages per message group
+	FIFOSequenceNumber            int64                     // the last sequence number handed out
+	Duplicates                    map[string]Deduplicated   // by deduplication ID, see `duplicateKey`
+	ReceiveAttempts               map[string]ReceiveAttempt // FIFO receives by ReceiveRequestAttemptId
+	Tags                          map[string]string
+	Created                       time.Time
+	LastModified                  time.Time // the last time its attributes were set
+	Policy                        string    // the access policy document, empty for none
+	RedriveAllowPolicy            string    // who may use it as a dead letter queue, empty to allow all
+
+	notifyLock sync.Mutex
+	notify     chan struct{}
 }
 
-var SyncQueues = struct {
+// QueueRegistry holds every queue by name.  Its lock only guards the map, the queues themselves are
+// guarded by their own locks, so a busy queue doesn't hold up any other.
+// Lock ordering is always the registry before any queue - never take the registry lock while holding
+// a queue's.  Two queues are locked together with `LockWithDeadLetterQueue`.
+type QueueRegistry struct {
 	sync.RWMutex
 	Queues map[string]*Queue
-}{Queues: make(map[string]*Queue)}
+}
+
+var SyncQueues = QueueRegistry{Queues: make(map[string]*Queue)}
+
+// Get looks a queue up by name.
+func (r *QueueRegistry) Get(queueName string) (*Queue, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	q, ok := r.Queues[queueName]
+	return q, ok
+}
+
+// Add registers `q` unless a queue with the same name already exists, in which case that queue is
+// returned instead along with false.
+func (r *QueueRegistry) Add(q *Queue) (*Queue, bool) {
+	r.Lock()
+	defer r.Unlock()
+	if existing, ok := r.Queues[q.Name]; ok {
+		return existing, false
+	}
+	r.Queues[q.Name] = q
+	return q, true
+}
+
+// Delete removes a queue from the registry and returns it.
+func (r *QueueRegistry) Delete(queueName string) (*Queue, bool) {
+	r.Lock()
+	defer r.Unlock()
+	q, ok := r.Queues[queueName]
+	if ok {
+		delete(r.Queues, queueName)
+	}
+	return q, ok
+}
+
+// All returns every registered queue, ordered by name.
+func (r *QueueRegistry) All() []*Queue {
+	r.RLock()
+	queues := make([]*Queue, 0, len(r.Queues))
+	for _, q := range r.Queues {
+		queues = append(queues, q)
+	}
+	r.RUnlock()
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].Name < queues[j].Name
+	})
+	return queues
+}
+
+// LockWithDeadLetterQueue locks `q` along with its dead letter queue, if it has one, always taking the
+// two locks in name order so that queues which are each other's dead letter queue can't deadlock.
+// The returned function unlocks both.
+func (q *Queue) LockWithDeadLetterQueue() (*Queue, func()) {
+	for {
+		q.Lock()
+		dlq := q.DeadLetterQueue
+		if dlq == nil || dlq == q {
+			return dlq, q.Unlock
+		}
+		if q.Name < dlq.Name {
+			dlq.Lock()
+			return dlq, func() {
+				dlq.Unlock()
+				q.Unlock()
+			}
+		}
+		q.Unlock()
+		dlq.Lock()
+		q.Lock()
+		// The redrive policy could have changed while we weren't holding the lock.
+		if q.DeadLetterQueue == dlq {
+			return dlq, func() {
+				q.Unlock()
+				dlq.Unlock()
+			}
+		}
+		q.Unlock()
+		dlq.Unlock()
+	}
+}
+
+// Allows evaluates the queue's policy for a request to the queue, see `PolicyDocument.Allows`.  A queue
+// without a policy, or one that can't be read, only lets its own account in.
+// NOTE: this takes the read lock of `q` itself.
+func (q *Queue) Allows(r PolicyRequest) bool {
+	q.RLock()
+	policy := q.Policy
+	q.RUnlock()
+
+	r.Resource = q.Arn
+	var doc *PolicyDocument
+	if policy != "" {
+		var err error
+		doc, err = ParsePolicy(policy)
+		if err != nil {
+			log.Errorf("Invalid policy of queue %s: %s", q.Name, err)
+		}
+	}
+	return doc.Allows(r)
+}
+
+// RedriveAllowPolicy says which source queues may use a queue as their dead letter queue.
+// https://docs.aws.amazon.com/AWSSimpleQueueService/latest/APIReference/API_SetQueueAttributes.html
+type RedriveAllowPolicy struct {
+	RedrivePermission string   `json:"redrivePermission"`
+	SourceQueueArns   []string `json:"sourceQueueArns,omitempty"`
+}
+
+const (
+	RedrivePermissionAllowAll = "allowAll"
+	RedrivePermissionDenyAll  = "denyAll"
+	RedrivePermissionByQueue  = "byQueue"
+
+	maxRedriveSourceQueueArns = 10
+)
+
+// ParseRedriveAllowPolicy reads a redrive allow policy, the source queues are there for `byQueue` only,
+// which needs 1 to 10 of them.
+func ParseRedriveAllowPolicy(text string) (*RedriveAllowPolicy, error) {
+	policy := &RedriveAllowPolicy{}
+	if err := json.Unmarshal([]byte(text), policy); err != nil {
+		return nil, err
+	}
+	switch policy.RedrivePermission {
+	case RedrivePermissionAllowAll, RedrivePermissionDenyAll:
+		if len(policy.SourceQueueArns) > 0 {
+			return nil, fmt.Errorf("sourceQueueArns can't be given with %s", policy.RedrivePermission)
+		}
+	case RedrivePermissionByQueue:
+		if len(policy.SourceQueueArns) == 0 || len(policy.SourceQueueArns) > maxRedriveSourceQueueArns {
+			return nil, fmt.Errorf("byQueue needs 1 to %d sourceQueueArns", maxRedriveSourceQueueArns)
+		}
+	default:
+		return nil, fmt.Errorf("invalid redrivePermission: %s", policy.RedrivePermission)
+	}
+	return policy, nil
+}
+
+// AllowsRedriveFrom tells if the queue's redrive allow policy lets the queue `sourceArn` use it as its
+// dead letter queue.  Without a policy, or with one that can't be read, every queue may.
+// NOTE: this takes the read lock of `q` itself.
+func (q *Queue) AllowsRedriveFrom(sourceArn string) bool {
+	q.RLock()
+	text := q.RedriveAllowPolicy
+	q.RUnlock()
+	if text == "" {
+		return true
+	}
+	policy, err := ParseRedriveAllowPolicy(text)
+	if err != nil {
+		log.Errorf("Invalid redrive allow policy of queue %s: %s", q.Name, err)
+		return true
+	}
+	switch policy.RedrivePermission {
+	case RedrivePermissionDenyAll:
+		return false
+	case RedrivePermissionByQueue:
+		for _, arn := range policy.SourceQueueArns {
+			if arn == sourceArn {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
 
 var DeduplicationPeriod = 5 * time.Minute
 
+// Deduplicated is what is remembered of the first message sent with a deduplication ID, a duplicate of it
+// sent within the DeduplicationPeriod gets its message ID and sequence number back, like on AWS.
+type Deduplicated struct {
+	MessageId      string    `json:"messageId,omitempty"`
+	SequenceNumber string    `json:"sequenceNumber,omitempty"`
+	Sent           time.Time `json:"sent"`
+}
+
+// UnmarshalJSON also takes the bare send time that was all that got remembered before.
+func (d *Deduplicated) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*d = Deduplicated{}
+		return json.Unmarshal(data, &d.Sent)
+	}
+	type deduplicated Deduplicated
+	return json.Unmarshal(data, (*deduplicated)(d))
+}
+
+// Expired tells if the DeduplicationPeriod of the message is over at `now`.
+func (d Deduplicated) Expired(now time.Time) bool {
+	return now.After(d.Sent.Add(DeduplicationPeriod))
+}
+
+// The values of the high throughput FIFO queue attributes, the first of each pair is the default.
+const (
+	DeduplicationScopeQueue              = "queue"
+	DeduplicationScopeMessageGroup       = "messageGroup"
+	FifoThroughputLimitPerQueue          = "perQueue"
+	FifoThroughputLimitPerMessageGroupId = "perMessageGroupId"
+)
+
 func HasFIFOQueueName(queueName string) bool {
 	return strings.HasSuffix(queueName, ".fifo")
 }
 
-func (q *Queue) NextSequenceNumber(groupId string) string {
-	if _, ok := q.FIFOSequenceNumbers[groupId]; !ok {
-		q.FIFOSequenceNumbers = map[string]int{
-			groupId: 0,
-		}
+// Changed returns a channel that is closed the next time `Signal` is called on the queue.  Long polling
+// receivers grab it before looking for messages, so anything that shows up in between still wakes them.
+func (q *Queue) Changed() <-chan struct{} {
+	q.notifyLock.Lock()
+	defer q.notifyLock.Unlock()
+	if q.notify == nil {
+		q.notify = make(chan struct{})
 	}
+	return q.notify
+}
 
-	q.FIFOSequenceNumbers[groupId]++
-	return strconv.Itoa(q.FIFOSequenceNumbers[groupId])
+// Signal wakes everyone waiting on `Changed`, it should be called whenever a message may have become
+// visible in the queue (or the queue has gone away).
+func (q *Queue) Signal() {
+	q.notifyLock.Lock()
+	defer q.notifyLock.Unlock()
+	if q.notify != nil {
+		close(q.notify)
+		q.notify = nil
+	}
 }
 
+// NOTE: the FIFO and deduplication helpers below expect the caller to hold the queue's lock.
+
+// NextSequenceNumber hands out the queue's next sequence number.  Like AWS's they are 20 digit numbers
+// that only ever go up, but aren't consecutive - they follow the clock, so they keep going up even if
+// the queue is recreated.
+func (q *Queue) NextSequenceNumber() string {
+	next := time.Now().UnixNano()
+	if next <= q.FIFOSequenceNumber {
+		next = q.FIFOSequenceNumber + 1
+	}
+	q.FIFOSequenceNumber = next
+	return fmt.Sprintf("%020d", next)
+}
+
+// IsLocked reports whether a message of the group is in flight, in which case none of the group's
+// messages can be received until it is deleted or becomes visible again.
 func (q *Queue) IsLocked(groupId string) bool {
-	_, ok := q.FIFOMessages[groupId]
-	return ok
+	return q.FIFOMessages[groupId] > 0
 }
 
+// LockGroup counts a message of the group going in flight.
 func (q *Queue) LockGroup(groupId string) {
-	if _, ok := q.FIFOMessages[groupId]; !ok {
-		q.FIFOMessages = map[string]int{
-			groupId: 0,
-		}
+	if q.FIFOMessages == nil {
+		q.FIFOMessages = make(map[string]int)
 	}
+	q.FIFOMessages[groupId]++
 }
 
+// UnlockGroup counts an in flight message of the group being deleted or becoming visible again.
 func (q *Queue) UnlockGroup(groupId string) {
-	if _, ok := q.FIFOMessages[groupId]; ok {
+	if q.FIFOMessages[groupId] <= 1 {
 		delete(q.FIFOMessages, groupId)
+		return
 	}
+	q.FIFOMessages[groupId]--
 }
 
-func (q *Queue) IsDuplicate(deduplicationId string) bool {
-	if !q.EnableDuplicates || !q.IsFIFO || deduplicationId == "" {
-		return false
+// DeduplicationId is the ID a message sent to a FIFO queue is deduplicated by: the one given, or the
+// SHA-256 of the body if the queue has ContentBasedDeduplication.  Empty if there is neither.
+func (q *Queue) DeduplicationId(deduplicationId string, messageBody string) string {
+	if deduplicationId != "" || !q.ContentBasedDeduplication {
+		return deduplicationId
 	}
+	sum := sha256.Sum256([]byte(messageBody))
+	return hex.EncodeToString(sum[:])
+}
 
-	_, ok := q.Duplicates[deduplicationId]
+// duplicateKey is what a deduplication ID is remembered by, with a DeduplicationScope of messageGroup the
+// same ID can be used in each group.
+func (q *Queue) duplicateKey(groupId string, deduplicationId string) string {
+	if q.DeduplicationScope == DeduplicationScopeMessageGroup {
+		return groupId + "/" + deduplicationId
+	}
+	return deduplicationId
+}
 
+// Enqueue adds `msg` to the queue and wakes up its receivers.  A FIFO queue first gives it the next sequence
+// number, unless its deduplication ID was already used within the DeduplicationPeriod: then nothing is
+// added, and like AWS the copy of `msg` returned carries the original's message ID and sequence number to
+// answer with.
+// NOTE: the caller must hold the lock of `q`, and persist the stored message.
+func (q *Queue) Enqueue(msg Message) (stored *Message, duplicate bool) {
+	if original, ok := q.Duplicate(msg.GroupID, msg.DeduplicationID, time.Now()); ok {
+		msg.Uuid = original.MessageId
+		msg.SequenceNumber = original.SequenceNumber
+		return &msg, true
+	}
+	if q.IsFIFO {
+		msg.SequenceNumber = q.NextSequenceNumber()
+	}
+	stored = q.Messages.Add(msg)
+	q.InitDuplicatation(stored)
+	q.Signal()
+	return stored, false
+}
+
+// Duplicate returns what the queue remembers of the first message sent with the deduplication ID, if the
+// ID was already used within the DeduplicationPeriod before `now`.
+func (q *Queue) Duplicate(groupId string, deduplicationId string, now time.Time) (Deduplicated, bool) {
+	if !q.IsFIFO || deduplicationId == "" {
+		return Deduplicated{}, false
+	}
+
+	original, ok := q.Duplicates[q.duplicateKey(groupId, deduplicationId)]
+	if !ok || original.Expired(now) {
+		return Deduplicated{}, false
+	}
+	return original, true
+}
+
+func (q *Queue) IsDuplicate(groupId string, deduplicationId string) bool {
+	_, ok := q.Duplicate(groupId, deduplicationId, time.Now())
 	return ok
 }
 
-func (q *Queue) InitDuplicatation(deduplicationId string) {
-	if !q.EnableDuplicates || !q.IsFIFO || deduplicationId == "" {
+// InitDuplicatation remembers `msg` as the first one sent with its deduplication ID.
+func (q *Queue) InitDuplicatation(msg *Message) {
+	if !q.IsFIFO || msg.DeduplicationID == "" {
+		return
+	}
+
+	if q.Duplicates == nil {
+		q.Duplicates = make(map[string]Deduplicated)
+	}
+	now := time.Now()
+	key := q.duplicateKey(msg.GroupID, msg.DeduplicationID)
+	if original, ok := q.Duplicates[key]; !ok || original.Expired(now) {
+		q.Duplicates[key] = Deduplicated{MessageId: msg.Uuid, SequenceNumber: msg.SequenceNumber, Sent: now}
+	}
+}
+
+// ReceiveAttempt is what a FIFO receive handed out, so that retrying it with the same ReceiveRequestAttemptId
+// within the DeduplicationPeriod gets the same messages and receipt handles.
+type ReceiveAttempt struct {
+	Messages []ReceivedMessage
+	Expires  time.Time
+}
+
+// ReceivedMessage is a message as a receive left it, any later change makes its receive attempt void.
+type ReceivedMessage struct {
+	ReceiptHandle     string
+	VisibilityTimeout time.Time
+}
+
+// RememberReceiveAttempt records the messages handed out by a FIFO receive with `attemptId`.
+func (q *Queue) RememberReceiveAttempt(attemptId string, messages []*Message, now time.Time) {
+	if !q.IsFIFO || attemptId == "" || len(messages) == 0 {
 		return
 	}
+	if q.ReceiveAttempts == nil {
+		q.ReceiveAttempts = make(map[string]ReceiveAttempt)
+	}
+	attempt := ReceiveAttempt{Expires: now.Add(DeduplicationPeriod)}
+	for _, msg := range messages {
+		attempt.Messages = append(attempt.Messages, ReceivedMessage{
+			ReceiptHandle:     msg.ReceiptHandle,
+			VisibilityTimeout: msg.VisibilityTimeout,
+		})
+	}
+	q.ReceiveAttempts[attemptId] = attempt
+}
 
-	if _, ok := q.Duplicates[deduplicationId]; !ok {
-		q.Duplicates[deduplicationId] = time.Now()
+// RetryReceiveAttempt returns the messages of an earlier receive with `attemptId`, with their visibility
+// timeout reset to `visibilityTimeout`.  If any of them has been deleted or had its visibility changed - or
+// timed out - since, the attempt is forgotten and false is returned, the retry is a new receive then.
+// NOTE: the messages are re-indexed but the caller still has to persist them.
+func (q *Queue) RetryReceiveAttempt(attemptId string, visibilityTimeout time.Duration, now time.Time) ([]*Message, bool) {
+	attempt, ok := q.ReceiveAttempts[attemptId]
+	if !ok {
+		return nil, false
+	}
+	if now.After(attempt.Expires) {
+		delete(q.ReceiveAttempts, attemptId)
+		return nil, false
+	}
+
+	messages := make([]*Message, 0, len(attempt.Messages))
+	for _, received := range attempt.Messages {
+		msg, ok := q.Messages.GetByReceiptHandle(received.ReceiptHandle)
+		if !ok || !msg.VisibilityTimeout.Equal(received.VisibilityTimeout) || !msg.VisibilityTimeout.After(now) {
+			delete(q.ReceiveAttempts, attemptId)
+			return nil, false
+		}
+		messages = append(messages, msg)
+	}
+
+	for i, msg := range messages {
+		msg.VisibilityTimeout = now.Add(visibilityTimeout)
+		q.Messages.Update(msg)
+		attempt.Messages[i].VisibilityTimeout = msg.VisibilityTimeout
+	}
+	return messages, true
+}
+
+// ExpireReceiveAttempts forgets the receive attempts that can no longer be retried.
+func (q *Queue) ExpireReceiveAttempts(now time.Time) {
+	for attemptId, attempt := range q.ReceiveAttempts {
+		if now.After(attempt.Expires) {
+			delete(q.ReceiveAttempts, attemptId)
+		}
 	}
 }
@@ -1,6 +1,9 @@
 package app
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -17,3 +20,264 @@ func TestMessage_IsReadyForReceipt(t *testing.T) {
 	time.Sleep(duration)
 	assert.True(t, msg.IsReadyForReceipt())
 }
+
+func TestQueueRegistry_Add_keeps_existing_queue(t *testing.T) {
+	r := QueueRegistry{Queues: make(map[string]*Queue)}
+	first := &Queue{Name: "queue"}
+
+	q, added := r.Add(first)
+	assert.True(t, added)
+	assert.Same(t, first, q)
+
+	q, added = r.Add(&Queue{Name: "queue"})
+	assert.False(t, added)
+	assert.Same(t, first, q)
+}
+
+func TestQueueRegistry_All_is_ordered_by_name(t *testing.T) {
+	r := QueueRegistry{Queues: map[string]*Queue{
+		"c": {Name: "c"},
+		"a": {Name: "a"},
+		"b": {Name: "b"},
+	}}
+
+	queues := r.All()
+
+	assert.Equal(t, "a", queues[0].Name)
+	assert.Equal(t, "b", queues[1].Name)
+	assert.Equal(t, "c", queues[2].Name)
+}
+
+func TestQueue_LockWithDeadLetterQueue_mutual_dead_letter_queues(t *testing.T) {
+	a := &Queue{Name: "a"}
+	b := &Queue{Name: "b"}
+	a.DeadLetterQueue = b
+	b.DeadLetterQueue = a
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			_, unlock := a.LockWithDeadLetterQueue()
+			unlock()
+		}
+	}()
+	for i := 0; i < 1000; i++ {
+		dlq, unlock := b.LockWithDeadLetterQueue()
+		assert.Same(t, a, dlq)
+		unlock()
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("locking queues that are each other's dead letter queue deadlocked")
+	}
+}
+
+func TestQueue_NextSequenceNumber_only_goes_up(t *testing.T) {
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+
+	previous := ""
+	for i := 0; i < 100; i++ {
+		next := q.NextSequenceNumber()
+		assert.Len(t, next, 20)
+		assert.True(t, next > previous)
+		previous = next
+	}
+
+	// Even if the clock is behind the last one handed out.
+	q.FIFOSequenceNumber = time.Now().Add(time.Hour).UnixNano()
+	assert.Equal(t, fmt.Sprintf("%020d", q.FIFOSequenceNumber+1), q.NextSequenceNumber())
+}
+
+func TestQueue_LockGroup_counts_messages_per_group(t *testing.T) {
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+
+	q.LockGroup("a")
+	q.LockGroup("a")
+	q.LockGroup("b")
+	assert.True(t, q.IsLocked("a"))
+	assert.True(t, q.IsLocked("b"))
+
+	q.UnlockGroup("a")
+	assert.True(t, q.IsLocked("a"))
+	assert.True(t, q.IsLocked("b"))
+
+	q.UnlockGroup("a")
+	assert.False(t, q.IsLocked("a"))
+	assert.True(t, q.IsLocked("b"))
+	assert.Equal(t, map[string]int{"b": 1}, q.FIFOMessages)
+}
+
+func TestQueue_DeduplicationId(t *testing.T) {
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+	assert.Equal(t, "dedup-1", q.DeduplicationId("dedup-1", "body"))
+	assert.Equal(t, "", q.DeduplicationId("", "body"))
+
+	q.ContentBasedDeduplication = true
+	assert.Equal(t, "dedup-1", q.DeduplicationId("dedup-1", "body"))
+	assert.Equal(t, "230d8358dc8e8890b4c58deeb62912ee2f20357ae92a5cc861b98e68fe31acb5", q.DeduplicationId("", "body"))
+}
+
+func TestQueue_IsDuplicate_deduplication_scope(t *testing.T) {
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+	q.InitDuplicatation(&Message{GroupID: "group-1", DeduplicationID: "dedup-1"})
+	assert.True(t, q.IsDuplicate("group-1", "dedup-1"))
+	assert.True(t, q.IsDuplicate("group-2", "dedup-1"))
+
+	q = &Queue{Name: "queue.fifo", IsFIFO: true, DeduplicationScope: DeduplicationScopeMessageGroup}
+	q.InitDuplicatation(&Message{GroupID: "group-1", DeduplicationID: "dedup-1"})
+	assert.True(t, q.IsDuplicate("group-1", "dedup-1"))
+	assert.False(t, q.IsDuplicate("group-2", "dedup-1"))
+}
+
+func TestQueue_Duplicate_expires_after_deduplication_period(t *testing.T) {
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+	q.InitDuplicatation(&Message{Uuid: "id-1", GroupID: "group-1", DeduplicationID: "dedup-1"})
+
+	original, ok := q.Duplicate("group-1", "dedup-1", time.Now().Add(DeduplicationPeriod-time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, "id-1", original.MessageId)
+	_, ok = q.Duplicate("group-1", "dedup-1", time.Now().Add(DeduplicationPeriod+time.Second))
+	assert.False(t, ok)
+
+	// An expired entry doesn't hold up the next message, even before the queue's maintenance clears it.
+	q.Duplicates["dedup-1"] = Deduplicated{MessageId: "id-1", Sent: time.Now().Add(-DeduplicationPeriod - time.Second)}
+	stored, duplicate := q.Enqueue(Message{Uuid: "id-2", GroupID: "group-1", DeduplicationID: "dedup-1"})
+	assert.False(t, duplicate)
+	assert.Equal(t, "id-2", stored.Uuid)
+	assert.Equal(t, "id-2", q.Duplicates["dedup-1"].MessageId)
+}
+
+func TestQueue_Enqueue(t *testing.T) {
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+	changed := q.Changed()
+
+	stored, duplicate := q.Enqueue(Message{Uuid: "id-1", GroupID: "group-1", DeduplicationID: "dedup-1"})
+	assert.False(t, duplicate)
+	assert.NotEmpty(t, stored.SequenceNumber)
+	assert.Equal(t, 1, q.Messages.Len())
+	select {
+	case <-changed:
+	default:
+		t.Fatal("receivers weren't woken up")
+	}
+
+	again, duplicate := q.Enqueue(Message{Uuid: "id-2", GroupID: "group-1", DeduplicationID: "dedup-1"})
+	assert.True(t, duplicate)
+	assert.Equal(t, "id-1", again.Uuid)
+	assert.Equal(t, stored.SequenceNumber, again.SequenceNumber)
+	assert.Equal(t, 1, q.Messages.Len())
+
+	standard := &Queue{Name: "queue"}
+	for i := 0; i < 2; i++ {
+		stored, duplicate = standard.Enqueue(Message{Uuid: fmt.Sprintf("id-%d", i), DeduplicationID: "dedup-1"})
+		assert.False(t, duplicate)
+		assert.Empty(t, stored.SequenceNumber)
+	}
+	assert.Equal(t, 2, standard.Messages.Len())
+}
+
+func TestDeduplicated_UnmarshalJSON(t *testing.T) {
+	duplicates := map[string]Deduplicated{}
+	err := json.Unmarshal([]byte(`{"old":"2024-01-02T03:04:05Z","new":{"messageId":"id-1","sequenceNumber":"1","sent":"2024-01-02T03:04:05Z"}}`), &duplicates)
+
+	assert.Nil(t, err)
+	sent := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, Deduplicated{Sent: sent}, duplicates["old"])
+	assert.Equal(t, Deduplicated{MessageId: "id-1", SequenceNumber: "1", Sent: sent}, duplicates["new"])
+}
+
+func TestQueue_RetryReceiveAttempt(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+	for _, id := range []string{"message-1", "message-2"} {
+		q.Messages.Add(Message{Uuid: id, MessageBody: []byte(id)})
+	}
+	q.Messages.Receive(func(msg *Message) bool {
+		msg.ReceiptHandle = msg.Uuid + "#handle"
+		msg.VisibilityTimeout = now.Add(30 * time.Second)
+		return true
+	})
+	msg1, _ := q.Messages.Get("message-1")
+	msg2, _ := q.Messages.Get("message-2")
+	q.RememberReceiveAttempt("attempt-1", []*Message{msg1, msg2}, now)
+
+	// The retry resets the visibility timeout.
+	messages, ok := q.RetryReceiveAttempt("attempt-1", 60*time.Second, now.Add(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, []*Message{msg1, msg2}, messages)
+	assert.Equal(t, now.Add(61*time.Second), msg1.VisibilityTimeout)
+
+	_, ok = q.RetryReceiveAttempt("attempt-1", 60*time.Second, now.Add(2*time.Second))
+	assert.True(t, ok)
+
+	// Not once the visibility of one of them changed.
+	msg2.VisibilityTimeout = now.Add(5 * time.Second)
+	q.Messages.Update(msg2)
+	_, ok = q.RetryReceiveAttempt("attempt-1", 60*time.Second, now.Add(3*time.Second))
+	assert.False(t, ok)
+	assert.NotContains(t, q.ReceiveAttempts, "attempt-1")
+
+	// Nor after the deduplication period.
+	q.RememberReceiveAttempt("attempt-2", []*Message{msg1}, now)
+	_, ok = q.RetryReceiveAttempt("attempt-2", 60*time.Second, now.Add(DeduplicationPeriod+time.Second))
+	assert.False(t, ok)
+
+	// Standard queues don't remember any.
+	q = &Queue{Name: "queue"}
+	q.RememberReceiveAttempt("attempt-1", []*Message{msg1}, now)
+	assert.Empty(t, q.ReceiveAttempts)
+}
+
+func TestQueue_ExpireReceiveAttempts(t *testing.T) {
+	now := time.Now()
+	q := &Queue{Name: "queue.fifo", IsFIFO: true}
+	q.RememberReceiveAttempt("attempt-1", []*Message{{Uuid: "message-1"}}, now)
+	q.RememberReceiveAttempt("attempt-2", []*Message{{Uuid: "message-2"}}, now.Add(time.Minute))
+
+	q.ExpireReceiveAttempts(now.Add(DeduplicationPeriod + time.Second))
+
+	assert.NotContains(t, q.ReceiveAttempts, "attempt-1")
+	assert.Contains(t, q.ReceiveAttempts, "attempt-2")
+}
+
+func TestParseRedriveAllowPolicy(t *testing.T) {
+	policy, err := ParseRedriveAllowPolicy(`{"redrivePermission":"byQueue","sourceQueueArns":["arn:aws:sqs:us-east-1:100010001000:source"]}`)
+	assert.Nil(t, err)
+	assert.Equal(t, &RedriveAllowPolicy{
+		RedrivePermission: RedrivePermissionByQueue,
+		SourceQueueArns:   []string{"arn:aws:sqs:us-east-1:100010001000:source"},
+	}, policy)
+
+	for _, text := range []string{
+		`not json`,
+		`{}`,
+		`{"redrivePermission":"sometimes"}`,
+		`{"redrivePermission":"byQueue"}`,
+		`{"redrivePermission":"byQueue","sourceQueueArns":["1","2","3","4","5","6","7","8","9","10","11"]}`,
+		`{"redrivePermission":"allowAll","sourceQueueArns":["arn:aws:sqs:us-east-1:100010001000:source"]}`,
+	} {
+		_, err := ParseRedriveAllowPolicy(text)
+		assert.NotNil(t, err, text)
+	}
+}
+
+func TestQueue_AllowsRedriveFrom(t *testing.T) {
+	source := "arn:aws:sqs:us-east-1:100010001000:source"
+	other := "arn:aws:sqs:us-east-1:100010001000:other"
+
+	q := &Queue{}
+	assert.True(t, q.AllowsRedriveFrom(source))
+
+	q.RedriveAllowPolicy = `{"redrivePermission":"allowAll"}`
+	assert.True(t, q.AllowsRedriveFrom(source))
+
+	q.RedriveAllowPolicy = `{"redrivePermission":"denyAll"}`
+	assert.False(t, q.AllowsRedriveFrom(source))
+
+	q.RedriveAllowPolicy = `{"redrivePermission":"byQueue","sourceQueueArns":["` + source + `"]}`
+	assert.True(t, q.AllowsRedriveFrom(source))
+	assert.False(t, q.AllowsRedriveFrom(other))
+}
@@ -6,8 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	urlLib "net/url"
+	"strconv"
+	"testing"
+	"time"
 
 	"github.com/Admiral-Piett/goaws/app"
+	"github.com/stretchr/testify/assert"
 )
 
 func ResetApp() {
@@ -58,3 +62,23 @@ func GenerateRequestInfo(method, url string, body interface{}, isJson bool) (*ht
 	rr := httptest.NewRecorder()
 	return rr, req
 }
+
+// RecentTimestamp tells if value is a Unix time, in seconds, from the last minute.  The queue timestamps are
+// whatever time the test ran, so that's as much as can be checked.
+func RecentTimestamp(value string) bool {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	return age > -time.Second && age < time.Minute
+}
+
+// ClearQueueTimestamps checks the queue was created and last modified just now, then clears both so that the
+// rest of the queue can be compared with the fixtures.
+func ClearQueueTimestamps(t *testing.T, q *app.Queue) {
+	assert.WithinDuration(t, time.Now(), q.Created, time.Minute)
+	assert.WithinDuration(t, time.Now(), q.LastModified, time.Minute)
+	q.Created = time.Time{}
+	q.LastModified = time.Time{}
+}
@@ -0,0 +1,114 @@
+package smoke_tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateAuthenticatedConfig(serverUrl string, accessKey string, secret string, region string) aws.Config {
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(serverUrl)
+	sdkConfig.Region = region
+	sdkConfig.Credentials = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: accessKey, SecretAccessKey: secret}, nil
+	})
+	return sdkConfig
+}
+
+func enableAuthentication() func() {
+	previous := app.CurrentEnvironment
+	app.CurrentEnvironment.Region = "us-east-1"
+	app.CurrentEnvironment.Authentication = app.EnvAuthentication{
+		Enabled: true,
+		Credentials: []app.EnvCredential{
+			{AccessKeyId: "AKIDEXAMPLE", SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"},
+		},
+	}
+	return func() {
+		app.CurrentEnvironment = previous
+	}
+}
+
+func Test_Authentication_json_signed_request_succeeds(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+	defer enableAuthentication()()
+
+	sqsClient := sqs.NewFromConfig(generateAuthenticatedConfig(server.URL, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1"))
+	_, err := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: aws.String("signed-queue"),
+	})
+
+	assert.Nil(t, err)
+}
+
+func Test_Authentication_json_wrong_secret_returns_signature_does_not_match(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+	defer enableAuthentication()()
+
+	sqsClient := sqs.NewFromConfig(generateAuthenticatedConfig(server.URL, "AKIDEXAMPLE", "wrong", "us-east-1"))
+	_, err := sqsClient.ListQueues(context.TODO(), &sqs.ListQueuesInput{})
+
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "SignatureDoesNotMatch")
+}
+
+func Test_Authentication_json_wrong_region_returns_signature_does_not_match(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+	defer enableAuthentication()()
+
+	sqsClient := sqs.NewFromConfig(generateAuthenticatedConfig(server.URL, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "eu-west-1"))
+	_, err := sqsClient.ListQueues(context.TODO(), &sqs.ListQueuesInput{})
+
+	assert.Contains(t, err.Error(), "SignatureDoesNotMatch")
+}
+
+func Test_Authentication_xml_signed_request_succeeds(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+	defer enableAuthentication()()
+
+	snsClient := sns.NewFromConfig(generateAuthenticatedConfig(server.URL, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1"))
+	_, err := snsClient.CreateTopic(context.TODO(), &sns.CreateTopicInput{
+		Name: aws.String("signed-topic"),
+	})
+
+	assert.Nil(t, err)
+}
+
+func Test_Authentication_xml_unknown_access_key_returns_invalid_client_token_id(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+	defer enableAuthentication()()
+
+	snsClient := sns.NewFromConfig(generateAuthenticatedConfig(server.URL, "UNKNOWN", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1"))
+	_, err := snsClient.ListTopics(context.TODO(), &sns.ListTopicsInput{})
+
+	assert.Contains(t, err.Error(), "403")
+	assert.Contains(t, err.Error(), "InvalidClientTokenId")
+}
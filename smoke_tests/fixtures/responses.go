@@ -43,11 +43,11 @@ var BASE_GET_QUEUE_ATTRIBUTES_RESPONSE = models.GetQueueAttributesResponse{
 		},
 		{
 			Name:  "CreatedTimestamp",
-			Value: "0000000000",
+			Value: "",
 		},
 		{
 			Name:  "LastModifiedTimestamp",
-			Value: "0000000000",
+			Value: "",
 		},
 		{
 			Name:  "QueueArn",
@@ -0,0 +1,112 @@
+package smoke_tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FifoTopic_delivers_to_fifo_queue_in_order(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	snsClient := sns.NewFromConfig(sdkConfig)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, err := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName:  aws.String("fifo-queue.fifo"),
+		Attributes: map[string]string{"FifoQueue": "true"},
+	})
+	assert.Nil(t, err)
+	queueAttributes, _ := sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       createQueueResponse.QueueUrl,
+		AttributeNames: []sqstypes.QueueAttributeName{"QueueArn"},
+	})
+
+	createTopicResponse, err := snsClient.CreateTopic(context.TODO(), &sns.CreateTopicInput{
+		Name:       aws.String("fifo-topic.fifo"),
+		Attributes: map[string]string{"FifoTopic": "true", "ContentBasedDeduplication": "true"},
+	})
+	assert.Nil(t, err)
+	_, err = snsClient.Subscribe(context.TODO(), &sns.SubscribeInput{
+		TopicArn:   createTopicResponse.TopicArn,
+		Protocol:   aws.String("sqs"),
+		Endpoint:   aws.String(queueAttributes.Attributes["QueueArn"]),
+		Attributes: map[string]string{"RawMessageDelivery": "true"},
+	})
+	assert.Nil(t, err)
+
+	messageIds := []string{}
+	sequenceNumbers := []string{}
+	for _, message := range []string{"message-1", "message-2", "message-1", "message-3"} {
+		publishResponse, err := snsClient.Publish(context.TODO(), &sns.PublishInput{
+			TopicArn:       createTopicResponse.TopicArn,
+			Message:        aws.String(message),
+			MessageGroupId: aws.String("group-1"),
+		})
+		assert.Nil(t, err)
+		messageIds = append(messageIds, *publishResponse.MessageId)
+		sequenceNumbers = append(sequenceNumbers, *publishResponse.SequenceNumber)
+	}
+	assert.True(t, sequenceNumbers[0] < sequenceNumbers[1])
+	assert.True(t, sequenceNumbers[1] < sequenceNumbers[3])
+	// The duplicate is answered with the original message.
+	assert.Equal(t, messageIds[0], messageIds[2])
+	assert.Equal(t, sequenceNumbers[0], sequenceNumbers[2])
+
+	receiveResponse, err := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:            createQueueResponse.QueueUrl,
+		MaxNumberOfMessages: 10,
+		AttributeNames:      []sqstypes.QueueAttributeName{"All"},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, receiveResponse.Messages, 3)
+	for i, body := range []string{"message-1", "message-2", "message-3"} {
+		assert.Equal(t, body, *receiveResponse.Messages[i].Body)
+		assert.Equal(t, "group-1", receiveResponse.Messages[i].Attributes["MessageGroupId"])
+	}
+}
+
+func Test_FifoTopic_rejects_standard_queue_subscription(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	snsClient := sns.NewFromConfig(sdkConfig)
+
+	createTopicResponse, err := snsClient.CreateTopic(context.TODO(), &sns.CreateTopicInput{
+		Name:       aws.String("fifo-topic.fifo"),
+		Attributes: map[string]string{"FifoTopic": "true"},
+	})
+	assert.Nil(t, err)
+
+	_, err = snsClient.Subscribe(context.TODO(), &sns.SubscribeInput{
+		TopicArn: createTopicResponse.TopicArn,
+		Protocol: aws.String("sqs"),
+		Endpoint: aws.String("arn:aws:sqs:us-east-1:100010001000:standard-queue"),
+	})
+	assert.Contains(t, err.Error(), "FIFO SNS Topics currently only support FIFO SQS Queues")
+
+	_, err = snsClient.Publish(context.TODO(), &sns.PublishInput{
+		TopicArn:               createTopicResponse.TopicArn,
+		Message:                aws.String("message"),
+		MessageDeduplicationId: aws.String("dedup-1"),
+	})
+	assert.Contains(t, err.Error(), "MessageGroupId parameter is required")
+}
@@ -89,9 +89,6 @@ func Test_List_Subscriptions_json_multiple_subscriptions(t *testing.T) {
 	assert.NotNil(t, subscribeResponse)
 	assert.NotNil(t, subscribeResponse2)
 
-	app.SyncTopics.Lock()
-	defer app.SyncTopics.Unlock()
-
 	// check listed subscriptions
 	sdkResponse, err := snsClient.ListSubscriptions(context.TODO(), &sns.ListSubscriptionsInput{})
 	assert.Nil(t, err)
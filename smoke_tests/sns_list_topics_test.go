@@ -2,6 +2,7 @@ package smoke_tests
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"testing"
 
@@ -127,3 +128,39 @@ func Test_ListTopics_xml_multiple_topics(t *testing.T) {
 	assert.Len(t, listTopicsResponseObject.Result.Topics.Member, 2)
 	assert.NotEqual(t, listTopicsResponseObject.Result.Topics.Member[0].TopicArn, listTopicsResponseObject.Result.Topics.Member[1].TopicArn)
 }
+
+func Test_List_Topics_json_paginates_in_arn_order(t *testing.T) {
+	server := generateServer()
+
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	snsClient := sns.NewFromConfig(sdkConfig)
+
+	for i := 0; i < 120; i++ {
+		snsClient.CreateTopic(context.TODO(), &sns.CreateTopicInput{
+			Name: aws.String(fmt.Sprintf("topic-%03d", i)),
+		})
+	}
+
+	pages := 0
+	topicArns := make([]string, 0)
+	paginator := sns.NewListTopicsPaginator(snsClient, &sns.ListTopicsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		assert.Nil(t, err)
+		pages++
+		for _, topic := range page.Topics {
+			topicArns = append(topicArns, *topic.TopicArn)
+		}
+	}
+
+	assert.Equal(t, 2, pages)
+	assert.Len(t, topicArns, 120)
+	assert.Contains(t, topicArns[0], "topic-000")
+	assert.Contains(t, topicArns[119], "topic-119")
+}
@@ -0,0 +1,134 @@
+package smoke_tests
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/conf"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PublishBatch_sqs_json_raw(t *testing.T) {
+	server := generateServer()
+	defaultEnv := app.CurrentEnvironment
+	conf.LoadYamlConfig("../app/conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		server.Close()
+		test.ResetResources()
+		app.CurrentEnvironment = defaultEnv
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	snsClient := sns.NewFromConfig(sdkConfig)
+
+	topicArn := app.SyncTopics.Topics["unit-topic1"].Arn
+	response, err := snsClient.PublishBatch(context.TODO(), &sns.PublishBatchInput{
+		TopicArn: &topicArn,
+		PublishBatchRequestEntries: []types.PublishBatchRequestEntry{
+			{
+				Id:      aws.String("first"),
+				Message: aws.String("message-1"),
+				MessageAttributes: map[string]types.MessageAttributeValue{
+					"trace": {DataType: aws.String("String"), StringValue: aws.String("abc")},
+				},
+			},
+			{
+				Id:               aws.String("second"),
+				Message:          aws.String(`{"default":"message-2"}`),
+				MessageStructure: aws.String("json"),
+			},
+			{
+				Id:               aws.String("third"),
+				Message:          aws.String(`{"sqs":"message-3"}`),
+				MessageStructure: aws.String("json"),
+			},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, response.Successful, 2)
+	assert.Equal(t, "first", *response.Successful[0].Id)
+	assert.NotEmpty(t, *response.Successful[0].MessageId)
+	assert.Equal(t, "second", *response.Successful[1].Id)
+	assert.Len(t, response.Failed, 1)
+	assert.Equal(t, "third", *response.Failed[0].Id)
+	assert.Equal(t, "InvalidParameter", *response.Failed[0].Code)
+	assert.True(t, response.Failed[0].SenderFault)
+
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "message-1", string(messages[0].MessageBody))
+	assert.Equal(t, "abc", messages[0].MessageAttributes["trace"].StringValue)
+	assert.Equal(t, "message-2", string(messages[1].MessageBody))
+}
+
+func Test_PublishBatch_json_ids_not_distinct(t *testing.T) {
+	server := generateServer()
+	defaultEnv := app.CurrentEnvironment
+	conf.LoadYamlConfig("../app/conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		server.Close()
+		test.ResetResources()
+		app.CurrentEnvironment = defaultEnv
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	snsClient := sns.NewFromConfig(sdkConfig)
+
+	topicArn := app.SyncTopics.Topics["unit-topic1"].Arn
+	_, err := snsClient.PublishBatch(context.TODO(), &sns.PublishBatchInput{
+		TopicArn: &topicArn,
+		PublishBatchRequestEntries: []types.PublishBatchRequestEntry{
+			{Id: aws.String("same"), Message: aws.String("message-1")},
+			{Id: aws.String("same"), Message: aws.String("message-2")},
+		},
+	})
+
+	assert.Contains(t, err.Error(), "BatchEntryIdsNotDistinct")
+	assert.Empty(t, app.SyncQueues.Queues["subscribed-queue1"].Messages.All())
+}
+
+func Test_PublishBatch_xml_success(t *testing.T) {
+	server := generateServer()
+	defaultEnv := app.CurrentEnvironment
+	conf.LoadYamlConfig("../app/conf/mock-data/mock-config.yaml", "BaseUnitTests")
+	defer func() {
+		server.Close()
+		test.ResetResources()
+		app.CurrentEnvironment = defaultEnv
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	r := e.POST("/").
+		WithFormField("Action", "PublishBatch").
+		WithFormField("TopicArn", app.SyncTopics.Topics["unit-topic1"].Arn).
+		WithFormField("PublishBatchRequestEntries.member.1.Id", "first").
+		WithFormField("PublishBatchRequestEntries.member.1.Message", "message-1").
+		WithFormField("PublishBatchRequestEntries.member.2.Id", "second").
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	response := models.PublishBatchResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, []models.PublishBatchResultEntry{{Id: "first", MessageId: response.Result.Successful[0].MessageId}}, response.Result.Successful)
+	assert.Len(t, response.Result.Failed, 1)
+	assert.Equal(t, "second", response.Result.Failed[0].Id)
+
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "message-1", string(messages[0].MessageBody))
+}
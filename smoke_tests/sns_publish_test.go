@@ -47,7 +47,7 @@ func Test_Publish_sqs_json_raw(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, response)
 
-	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
 	assert.Len(t, messages, 1)
 	assert.Equal(t, message, string(messages[0].MessageBody))
 }
@@ -78,7 +78,7 @@ func Test_Publish_sqs_json_not_raw(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, response)
 
-	messages := app.SyncQueues.Queues["subscribed-queue3"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue3"].Messages.All()
 	assert.Len(t, messages, 1)
 
 	body := string(messages[0].MessageBody)
@@ -277,7 +277,7 @@ func Test_Publish_sqs_xml_raw(t *testing.T) {
 		Status(http.StatusOK).
 		Body().Raw()
 
-	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue1"].Messages.All()
 	assert.Len(t, messages, 1)
 	assert.Equal(t, message, string(messages[0].MessageBody))
 }
@@ -316,7 +316,7 @@ func Test_Publish_sqs_xml_not_raw(t *testing.T) {
 		Status(http.StatusOK).
 		Body().Raw()
 
-	messages := app.SyncQueues.Queues["subscribed-queue3"].Messages
+	messages := app.SyncQueues.Queues["subscribed-queue3"].Messages.All()
 	assert.Len(t, messages, 1)
 
 	body := string(messages[0].MessageBody)
@@ -0,0 +1,147 @@
+package smoke_tests
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/gavv/httpexpect/v2"
+	"github.com/stretchr/testify/assert"
+
+	af "github.com/Admiral-Piett/goaws/app/fixtures"
+)
+
+func Test_GetTopicAttributes_json_create_set_get(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	snsClient := sns.NewFromConfig(sdkConfig)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createTopicResponse, err := snsClient.CreateTopic(context.TODO(), &sns.CreateTopicInput{
+		Name:       aws.String("new-topic-1"),
+		Attributes: map[string]string{"DisplayName": "New Topic"},
+	})
+	assert.Nil(t, err)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+	subscribeResponse, _ := snsClient.Subscribe(context.TODO(), &sns.SubscribeInput{
+		Protocol: aws.String("sqs"),
+		TopicArn: createTopicResponse.TopicArn,
+		Endpoint: createQueueResponse.QueueUrl,
+	})
+	snsClient.Subscribe(context.TODO(), &sns.SubscribeInput{
+		Protocol: aws.String("sqs"),
+		TopicArn: createTopicResponse.TopicArn,
+		Endpoint: aws.String("arn:aws:sqs:us-east-1:100010001000:other-queue"),
+	})
+	snsClient.Unsubscribe(context.TODO(), &sns.UnsubscribeInput{
+		SubscriptionArn: subscribeResponse.SubscriptionArn,
+	})
+
+	_, err = snsClient.SetTopicAttributes(context.TODO(), &sns.SetTopicAttributesInput{
+		TopicArn:       createTopicResponse.TopicArn,
+		AttributeName:  aws.String("DeliveryPolicy"),
+		AttributeValue: aws.String(`{"http":{}}`),
+	})
+	assert.Nil(t, err)
+
+	attributesResponse, err := snsClient.GetTopicAttributes(context.TODO(), &sns.GetTopicAttributesInput{
+		TopicArn: createTopicResponse.TopicArn,
+	})
+	assert.Nil(t, err)
+
+	attributes := attributesResponse.Attributes
+	assert.Equal(t, *createTopicResponse.TopicArn, attributes["TopicArn"])
+	assert.Equal(t, "New Topic", attributes["DisplayName"])
+	assert.Equal(t, "1", attributes["SubscriptionsConfirmed"])
+	assert.Equal(t, "0", attributes["SubscriptionsPending"])
+	assert.Equal(t, "1", attributes["SubscriptionsDeleted"])
+	assert.Equal(t, `{"http":{}}`, attributes["DeliveryPolicy"])
+	assert.Equal(t, `{"http":{}}`, attributes["EffectiveDeliveryPolicy"])
+	assert.Contains(t, attributes["Policy"], *createTopicResponse.TopicArn)
+
+	_, err = snsClient.SetTopicAttributes(context.TODO(), &sns.SetTopicAttributesInput{
+		TopicArn:       createTopicResponse.TopicArn,
+		AttributeName:  aws.String("FifoTopic"),
+		AttributeValue: aws.String("true"),
+	})
+	assert.Contains(t, err.Error(), "400")
+}
+
+func Test_GetTopicAttributes_xml_topic_not_found(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	r := e.POST("/").
+		WithFormField("Action", "GetTopicAttributes").
+		WithFormField("TopicArn", "arn:aws:sns:us-east-1:100010001000:not-a-topic").
+		Expect().
+		Status(http.StatusBadRequest).
+		Body().Raw()
+
+	response := models.ErrorResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, models.SnsErrors["TopicNotFound"].Response(), response.Result)
+}
+
+func Test_SetTopicAttributes_xml_success(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	snsClient := sns.NewFromConfig(sdkConfig)
+
+	createTopicResponse, _ := snsClient.CreateTopic(context.TODO(), &sns.CreateTopicInput{
+		Name: aws.String("new-topic-1"),
+	})
+
+	r := e.POST("/").
+		WithFormField("Action", "SetTopicAttributes").
+		WithFormField("TopicArn", *createTopicResponse.TopicArn).
+		WithFormField("AttributeName", "DisplayName").
+		WithFormField("AttributeValue", "Renamed").
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	response := models.SetTopicAttributesResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, models.BASE_XMLNS, response.Xmlns)
+
+	r = e.POST("/").
+		WithFormField("Action", "GetTopicAttributes").
+		WithFormField("TopicArn", *createTopicResponse.TopicArn).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	attributesResponse := models.GetTopicAttributesResponse{}
+	xml.Unmarshal([]byte(r), &attributesResponse)
+	assert.Contains(t, attributesResponse.Result.Attributes.Entries, models.TopicAttributeEntry{Key: "DisplayName", Value: "Renamed"})
+}
@@ -0,0 +1,116 @@
+package smoke_tests
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app/test"
+
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/gavv/httpexpect/v2"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/stretchr/testify/assert"
+
+	af "github.com/Admiral-Piett/goaws/app/fixtures"
+)
+
+func Test_ChangeMessageVisibilityBatchV1_json(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+	for _, body := range []string{"message-1", "message-2"} {
+		sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+			QueueUrl:    createQueueResponse.QueueUrl,
+			MessageBody: aws.String(body),
+		})
+	}
+	receiveResponse, _ := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:            createQueueResponse.QueueUrl,
+		MaxNumberOfMessages: 10,
+	})
+	assert.Len(t, receiveResponse.Messages, 2)
+
+	sdkResponse, err := sqsClient.ChangeMessageVisibilityBatch(context.TODO(), &sqs.ChangeMessageVisibilityBatchInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+		Entries: []types.ChangeMessageVisibilityBatchRequestEntry{
+			{Id: aws.String("release-1"), ReceiptHandle: receiveResponse.Messages[0].ReceiptHandle, VisibilityTimeout: 0},
+			{Id: aws.String("release-2"), ReceiptHandle: receiveResponse.Messages[1].ReceiptHandle, VisibilityTimeout: 0},
+			{Id: aws.String("unknown"), ReceiptHandle: aws.String("garbage"), VisibilityTimeout: 30},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, sdkResponse.Successful, 2)
+	assert.Equal(t, "release-1", *sdkResponse.Successful[0].Id)
+	assert.Equal(t, "release-2", *sdkResponse.Successful[1].Id)
+	assert.Len(t, sdkResponse.Failed, 1)
+	assert.Equal(t, "unknown", *sdkResponse.Failed[0].Id)
+	assert.True(t, sdkResponse.Failed[0].SenderFault)
+
+	receiveResponse, _ = sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:            createQueueResponse.QueueUrl,
+		MaxNumberOfMessages: 10,
+	})
+	assert.Len(t, receiveResponse.Messages, 2)
+}
+
+func Test_ChangeMessageVisibilityBatchV1_xml(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+	sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:    createQueueResponse.QueueUrl,
+		MessageBody: aws.String("message-1"),
+	})
+	receiveResponse, _ := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+	})
+
+	r := e.POST("/").
+		WithFormField("Action", "ChangeMessageVisibilityBatch").
+		WithFormField("QueueUrl", *createQueueResponse.QueueUrl).
+		WithFormField("Entries.0.Id", "extend").
+		WithFormField("Entries.0.ReceiptHandle", *receiveResponse.Messages[0].ReceiptHandle).
+		WithFormField("Entries.0.VisibilityTimeout", "600").
+		WithFormField("Entries.1.Id", "unknown").
+		WithFormField("Entries.1.ReceiptHandle", "garbage").
+		WithFormField("Entries.1.VisibilityTimeout", "600").
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	response := models.ChangeMessageVisibilityBatchResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, []models.ChangeMessageVisibilityBatchResultEntry{{Id: "extend"}}, response.Result.Successful)
+	assert.Len(t, response.Result.Failed, 1)
+	assert.Equal(t, "unknown", response.Result.Failed[0].Id)
+}
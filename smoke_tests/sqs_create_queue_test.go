@@ -68,6 +68,7 @@ func Test_CreateQueueV1_json_no_attributes(t *testing.T) {
 
 	r3 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r3)
+	blankQueueTimestampAttributes(t, r3.Result.Attrs)
 	assert.Equal(t, sf.BASE_GET_QUEUE_ATTRIBUTES_RESPONSE, r3)
 }
 
@@ -93,14 +94,14 @@ func Test_CreateQueueV1_json_with_attributes(t *testing.T) {
 	sdkResponse, err := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
 		QueueName: &af.QueueName,
 		Attributes: map[string]string{
-			"DelaySeconds":           "1",
-			"MaximumMessageSize":     "2",
-			"MessageRetentionPeriod": "3",
-			//"Policy":                        "{\"this-is\": \"the-policy\"}",
+			"DelaySeconds":                  "1",
+			"MaximumMessageSize":            "2",
+			"MessageRetentionPeriod":        "60",
+			"Policy":                        af.QueuePolicy,
 			"ReceiveMessageWaitTimeSeconds": "4",
 			"VisibilityTimeout":             "5",
 			"RedrivePolicy":                 fmt.Sprintf(`{"maxReceiveCount":"100","deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue),
-			//"RedriveAllowPolicy":            "{\"this-is\": \"the-redrive-allow-policy\"}",
+			"RedriveAllowPolicy":            af.QueueRedriveAllowPolicy,
 		},
 	})
 
@@ -132,16 +133,23 @@ func Test_CreateQueueV1_json_with_attributes(t *testing.T) {
 	exp3, _ := dupe.(models.GetQueueAttributesResponse)
 	exp3.Result.Attrs[0].Value = "1"
 	exp3.Result.Attrs[1].Value = "2"
-	exp3.Result.Attrs[2].Value = "3"
+	exp3.Result.Attrs[2].Value = "60"
 	exp3.Result.Attrs[3].Value = "4"
 	exp3.Result.Attrs[4].Value = "5"
 	exp3.Result.Attrs[9].Value = fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, af.QueueName)
 	exp3.Result.Attrs = append(exp3.Result.Attrs, models.Attribute{
+		Name:  "Policy",
+		Value: af.QueuePolicy,
+	}, models.Attribute{
+		Name:  "RedriveAllowPolicy",
+		Value: af.QueueRedriveAllowPolicy,
+	}, models.Attribute{
 		Name:  "RedrivePolicy",
 		Value: fmt.Sprintf(`{"maxReceiveCount":"100", "deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue),
 	})
 	r3 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r3)
+	blankQueueTimestampAttributes(t, r3.Result.Attrs)
 	assert.Equal(t, exp3, r3)
 }
 
@@ -195,13 +203,21 @@ func Test_CreateQueueV1_json_with_attributes_as_ints(t *testing.T) {
 	exp3, _ := dupe.(models.GetQueueAttributesResponse)
 	exp3.Result.Attrs[0].Value = "1"
 	exp3.Result.Attrs[1].Value = "2"
-	exp3.Result.Attrs[2].Value = "3"
+	exp3.Result.Attrs[2].Value = "60"
 	exp3.Result.Attrs[3].Value = "4"
 	exp3.Result.Attrs[4].Value = "5"
 	exp3.Result.Attrs[9].Value = fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, af.QueueName)
+	exp3.Result.Attrs = append(exp3.Result.Attrs, models.Attribute{
+		Name:  "Policy",
+		Value: af.QueuePolicy,
+	}, models.Attribute{
+		Name:  "RedriveAllowPolicy",
+		Value: af.QueueRedriveAllowPolicy,
+	})
 
 	r3 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r3)
+	blankQueueTimestampAttributes(t, r3.Result.Attrs)
 	assert.Equal(t, exp3, r3)
 }
 
@@ -255,7 +271,7 @@ func Test_CreateQueueV1_json_with_attributes_ints_as_strings(t *testing.T) {
 		}{
 			DelaySeconds:           "1",
 			MaximumMessageSize:     "2",
-			MessageRetentionPeriod: "3",
+			MessageRetentionPeriod: "60",
 			//Policy:                        "",
 			ReceiveMessageWaitTimeSeconds: "0",
 			RedrivePolicy: struct {
@@ -302,7 +318,7 @@ func Test_CreateQueueV1_json_with_attributes_ints_as_strings(t *testing.T) {
 	exp3, _ := dupe.(models.GetQueueAttributesResponse)
 	exp3.Result.Attrs[0].Value = "1"
 	exp3.Result.Attrs[1].Value = "2"
-	exp3.Result.Attrs[2].Value = "3"
+	exp3.Result.Attrs[2].Value = "60"
 	exp3.Result.Attrs[3].Value = "0"
 	exp3.Result.Attrs[4].Value = "30"
 	exp3.Result.Attrs[9].Value = fmt.Sprintf("%s:new-string-queue", af.BASE_SQS_ARN)
@@ -312,6 +328,7 @@ func Test_CreateQueueV1_json_with_attributes_ints_as_strings(t *testing.T) {
 	})
 	r3 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r3)
+	blankQueueTimestampAttributes(t, r3.Result.Attrs)
 	assert.Equal(t, exp3, r3)
 }
 
@@ -363,6 +380,7 @@ func Test_CreateQueueV1_xml_no_attributes(t *testing.T) {
 
 	r3 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r3)
+	blankQueueTimestampAttributes(t, r3.Result.Attrs)
 	assert.Equal(t, sf.BASE_GET_QUEUE_ATTRIBUTES_RESPONSE, r3)
 }
 
@@ -402,15 +420,15 @@ func Test_CreateQueueV1_xml_with_attributes(t *testing.T) {
 		WithFormField("Attribute.3.Name", "DelaySeconds").
 		WithFormField("Attribute.3.Value", "1").
 		WithFormField("Attribute.4.Name", "MessageRetentionPeriod").
-		WithFormField("Attribute.4.Value", "3").
+		WithFormField("Attribute.4.Value", "60").
 		WithFormField("Attribute.5.Name", "Policy").
-		WithFormField("Attribute.5.Value", "{\"this-is\": \"the-policy\"}").
+		WithFormField("Attribute.5.Value", af.QueuePolicy).
 		WithFormField("Attribute.6.Name", "ReceiveMessageWaitTimeSeconds").
 		WithFormField("Attribute.6.Value", "4").
 		WithFormField("Attribute.7.Name", "RedrivePolicy").
 		WithFormField("Attribute.7.Value", fmt.Sprintf("{\"maxReceiveCount\": 100, \"deadLetterTargetArn\":\"%s:new-queue-1\"}", af.BASE_SQS_ARN)).
 		WithFormField("Attribute.8.Name", "RedriveAllowPolicy").
-		WithFormField("Attribute.8.Value", "{\"this-is\": \"the-redrive-allow-policy\"}").
+		WithFormField("Attribute.8.Value", af.QueueRedriveAllowPolicy).
 		Expect().
 		Status(http.StatusOK).
 		Body().Raw()
@@ -444,15 +462,80 @@ func Test_CreateQueueV1_xml_with_attributes(t *testing.T) {
 	exp3, _ := dupe.(models.GetQueueAttributesResponse)
 	exp3.Result.Attrs[0].Value = "1"
 	exp3.Result.Attrs[1].Value = "2"
-	exp3.Result.Attrs[2].Value = "3"
+	exp3.Result.Attrs[2].Value = "60"
 	exp3.Result.Attrs[3].Value = "4"
 	exp3.Result.Attrs[4].Value = "5"
 	exp3.Result.Attrs[9].Value = fmt.Sprintf("%s:new-queue-2", af.BASE_SQS_ARN)
 	exp3.Result.Attrs = append(exp3.Result.Attrs, models.Attribute{
+		Name:  "Policy",
+		Value: af.QueuePolicy,
+	}, models.Attribute{
+		Name:  "RedriveAllowPolicy",
+		Value: af.QueueRedriveAllowPolicy,
+	}, models.Attribute{
 		Name:  "RedrivePolicy",
 		Value: fmt.Sprintf(`{"maxReceiveCount":"100", "deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, af.QueueName),
 	})
 	r3 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r3)
+	blankQueueTimestampAttributes(t, r3.Result.Attrs)
 	assert.Equal(t, exp3, r3)
 }
+
+func Test_CreateQueueV1_json_existing_queue_with_different_attributes(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueInput := &sqs.CreateQueueInput{
+		QueueName:  &af.QueueName,
+		Attributes: map[string]string{"VisibilityTimeout": "45"},
+	}
+	sdkResponse, err := sqsClient.CreateQueue(context.TODO(), createQueueInput)
+	assert.Nil(t, err)
+
+	sameResponse, err := sqsClient.CreateQueue(context.TODO(), createQueueInput)
+	assert.Nil(t, err)
+	assert.Equal(t, *sdkResponse.QueueUrl, *sameResponse.QueueUrl)
+
+	_, err = sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName:  &af.QueueName,
+		Attributes: map[string]string{"VisibilityTimeout": "60"},
+	})
+	assert.Contains(t, err.Error(), "QueueAlreadyExists")
+}
+
+func Test_CreateQueueV1_xml_existing_queue_with_different_attributes(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	e.POST("/").
+		WithFormField("Action", "CreateQueue").
+		WithFormField("QueueName", af.QueueName).
+		WithFormField("Attribute.1.Name", "DelaySeconds").
+		WithFormField("Attribute.1.Value", "1").
+		Expect().
+		Status(http.StatusOK)
+
+	r := e.POST("/").
+		WithFormField("Action", "CreateQueue").
+		WithFormField("QueueName", af.QueueName).
+		WithFormField("Attribute.1.Name", "DelaySeconds").
+		WithFormField("Attribute.1.Value", "2").
+		Expect().
+		Status(http.StatusBadRequest).
+		Body().Raw()
+
+	assert.Contains(t, r, "QueueAlreadyExists")
+}
@@ -0,0 +1,241 @@
+package smoke_tests
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/gavv/httpexpect/v2"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FifoQueue_json_content_based_deduplication_and_group_order(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, err := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: aws.String("fifo-queue.fifo"),
+		Attributes: map[string]string{
+			"FifoQueue":                 "true",
+			"ContentBasedDeduplication": "true",
+		},
+	})
+	assert.Nil(t, err)
+
+	sequenceNumbers := []string{}
+	messageIds := []string{}
+	for _, body := range []string{"message-1", "message-2", "message-1", "message-3"} {
+		sendResponse, err := sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+			QueueUrl:       createQueueResponse.QueueUrl,
+			MessageBody:    aws.String(body),
+			MessageGroupId: aws.String("group-1"),
+		})
+		assert.Nil(t, err)
+		sequenceNumbers = append(sequenceNumbers, *sendResponse.SequenceNumber)
+		messageIds = append(messageIds, *sendResponse.MessageId)
+	}
+	// The duplicate gets the original's ID and sequence number back.
+	assert.Equal(t, messageIds[0], messageIds[2])
+	assert.Equal(t, sequenceNumbers[0], sequenceNumbers[2])
+	sequenceNumbers = append(sequenceNumbers[:2], sequenceNumbers[3])
+	assert.True(t, sequenceNumbers[0] < sequenceNumbers[1])
+	assert.True(t, sequenceNumbers[1] < sequenceNumbers[2])
+
+	receiveResponse, err := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:            createQueueResponse.QueueUrl,
+		MaxNumberOfMessages: 10,
+		AttributeNames:      []types.QueueAttributeName{"All"},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, receiveResponse.Messages, 3)
+	for i, body := range []string{"message-1", "message-2", "message-3"} {
+		assert.Equal(t, body, *receiveResponse.Messages[i].Body)
+		assert.Equal(t, sequenceNumbers[i], receiveResponse.Messages[i].Attributes["SequenceNumber"])
+		assert.Equal(t, "group-1", receiveResponse.Messages[i].Attributes["MessageGroupId"])
+	}
+
+	// The group stays locked while its messages are in flight.
+	sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:       createQueueResponse.QueueUrl,
+		MessageBody:    aws.String("message-4"),
+		MessageGroupId: aws.String("group-1"),
+	})
+	receiveResponse, err = sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:            createQueueResponse.QueueUrl,
+		MaxNumberOfMessages: 10,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, receiveResponse.Messages, 0)
+
+	attributesResponse, err := sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       createQueueResponse.QueueUrl,
+		AttributeNames: []types.QueueAttributeName{"FifoQueue", "ContentBasedDeduplication"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"FifoQueue": "true", "ContentBasedDeduplication": "true"}, attributesResponse.Attributes)
+}
+
+func Test_FifoQueue_json_send_without_message_group_id_fails(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: aws.String("fifo-queue.fifo"),
+	})
+
+	_, err := sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:               createQueueResponse.QueueUrl,
+		MessageBody:            aws.String("message-1"),
+		MessageDeduplicationId: aws.String("dedup-1"),
+	})
+
+	assert.Contains(t, err.Error(), "MissingParameter")
+}
+
+func Test_FifoQueue_xml_fifo_attribute_on_standard_queue_fails(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	r := e.POST("/").
+		WithFormField("Action", "CreateQueue").
+		WithFormField("QueueName", "standard-queue").
+		WithFormField("Attribute.1.Name", "FifoQueue").
+		WithFormField("Attribute.1.Value", "true").
+		Expect().
+		Status(http.StatusBadRequest).
+		Body().Raw()
+
+	assert.Contains(t, r, "InvalidParameterValue")
+	assert.Contains(t, r, "must end with .fifo suffix")
+}
+
+func Test_FifoQueue_json_deduplication_scope_message_group(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, err := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: aws.String("fifo-queue.fifo"),
+		Attributes: map[string]string{
+			"FifoQueue":           "true",
+			"DeduplicationScope":  "messageGroup",
+			"FifoThroughputLimit": "perMessageGroupId",
+		},
+	})
+	assert.Nil(t, err)
+
+	// The same deduplication ID in another group isn't a duplicate.
+	for _, group := range []string{"group-1", "group-2", "group-1"} {
+		_, err := sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+			QueueUrl:               createQueueResponse.QueueUrl,
+			MessageBody:            aws.String("message-1"),
+			MessageGroupId:         aws.String(group),
+			MessageDeduplicationId: aws.String("dedup-1"),
+		})
+		assert.Nil(t, err)
+	}
+
+	receiveResponse, err := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:            createQueueResponse.QueueUrl,
+		MaxNumberOfMessages: 10,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, receiveResponse.Messages, 2)
+
+	attributesResponse, err := sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       createQueueResponse.QueueUrl,
+		AttributeNames: []types.QueueAttributeName{"DeduplicationScope", "FifoThroughputLimit"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"DeduplicationScope": "messageGroup", "FifoThroughputLimit": "perMessageGroupId"}, attributesResponse.Attributes)
+
+	_, err = sqsClient.SetQueueAttributes(context.TODO(), &sqs.SetQueueAttributesInput{
+		QueueUrl:   createQueueResponse.QueueUrl,
+		Attributes: map[string]string{"DeduplicationScope": "queue"},
+	})
+	assert.Contains(t, err.Error(), "InvalidAttributeValue")
+}
+
+func Test_FifoQueue_json_retried_receive_request_attempt(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName:  aws.String("fifo-queue.fifo"),
+		Attributes: map[string]string{"ContentBasedDeduplication": "true", "VisibilityTimeout": "30"},
+	})
+	for _, body := range []string{"message-1", "message-2"} {
+		sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+			QueueUrl:       createQueueResponse.QueueUrl,
+			MessageBody:    aws.String(body),
+			MessageGroupId: aws.String("group-1"),
+		})
+	}
+
+	receiveInput := &sqs.ReceiveMessageInput{
+		QueueUrl:                createQueueResponse.QueueUrl,
+		MaxNumberOfMessages:     10,
+		ReceiveRequestAttemptId: aws.String("attempt-1"),
+	}
+	firstResponse, err := sqsClient.ReceiveMessage(context.TODO(), receiveInput)
+	assert.Nil(t, err)
+	assert.Len(t, firstResponse.Messages, 2)
+
+	retriedResponse, err := sqsClient.ReceiveMessage(context.TODO(), receiveInput)
+	assert.Nil(t, err)
+	assert.Len(t, retriedResponse.Messages, 2)
+	for i, msg := range firstResponse.Messages {
+		assert.Equal(t, *msg.MessageId, *retriedResponse.Messages[i].MessageId)
+		assert.Equal(t, *msg.ReceiptHandle, *retriedResponse.Messages[i].ReceiptHandle)
+	}
+
+	// Once one of them has had its visibility changed the retry is a new receive, the group is still locked.
+	_, err = sqsClient.ChangeMessageVisibility(context.TODO(), &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          createQueueResponse.QueueUrl,
+		ReceiptHandle:     firstResponse.Messages[1].ReceiptHandle,
+		VisibilityTimeout: 60,
+	})
+	assert.Nil(t, err)
+	retriedResponse, err = sqsClient.ReceiveMessage(context.TODO(), receiveInput)
+	assert.Nil(t, err)
+	assert.Len(t, retriedResponse.Messages, 0)
+}
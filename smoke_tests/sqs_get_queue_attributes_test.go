@@ -43,7 +43,7 @@ func Test_GetQueueAttributes_json_all(t *testing.T) {
 	attributes := map[string]string{
 		"DelaySeconds":           "1",
 		"MaximumMessageSize":     "2",
-		"MessageRetentionPeriod": "3",
+		"MessageRetentionPeriod": "60",
 		//"Policy":                        "{\"this-is\": \"the-policy\"}",
 		"ReceiveMessageWaitTimeSeconds": "4",
 		"VisibilityTimeout":             "5",
@@ -65,10 +65,11 @@ func Test_GetQueueAttributes_json_all(t *testing.T) {
 	expectedAttributes["RedrivePolicy"] = fmt.Sprintf(`{"maxReceiveCount":"100", "deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue)
 	expectedAttributes["ApproximateNumberOfMessages"] = "0"
 	expectedAttributes["ApproximateNumberOfMessagesNotVisible"] = "0"
-	expectedAttributes["CreatedTimestamp"] = "0000000000"
-	expectedAttributes["LastModifiedTimestamp"] = "0000000000"
+	expectedAttributes["CreatedTimestamp"] = ""
+	expectedAttributes["LastModifiedTimestamp"] = ""
 	expectedAttributes["QueueArn"] = fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, af.QueueName)
 	assert.Nil(t, err)
+	blankQueueTimestamps(t, sdkResponse.Attributes)
 	assert.Equal(t, expectedAttributes, sdkResponse.Attributes)
 }
 
@@ -88,7 +89,7 @@ func Test_GetQueueAttributes_json_specific_attributes(t *testing.T) {
 		Attributes: map[string]string{
 			"DelaySeconds":           "1",
 			"MaximumMessageSize":     "2",
-			"MessageRetentionPeriod": "3",
+			"MessageRetentionPeriod": "60",
 		},
 	})
 
@@ -102,6 +103,7 @@ func Test_GetQueueAttributes_json_specific_attributes(t *testing.T) {
 	}
 
 	assert.Nil(t, err)
+	blankQueueTimestamps(t, sdkResponse.Attributes)
 	assert.Equal(t, expectedAttributes, sdkResponse.Attributes)
 }
 
@@ -123,7 +125,7 @@ func Test_GetQueueAttributes_json_missing_attribute_name_returns_all(t *testing.
 	attributes := map[string]string{
 		"DelaySeconds":           "1",
 		"MaximumMessageSize":     "2",
-		"MessageRetentionPeriod": "3",
+		"MessageRetentionPeriod": "60",
 		//"Policy":                        "{\"this-is\": \"the-policy\"}",
 		"ReceiveMessageWaitTimeSeconds": "4",
 		"VisibilityTimeout":             "5",
@@ -144,10 +146,11 @@ func Test_GetQueueAttributes_json_missing_attribute_name_returns_all(t *testing.
 	expectedAttributes["RedrivePolicy"] = fmt.Sprintf(`{"maxReceiveCount":"100", "deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue)
 	expectedAttributes["ApproximateNumberOfMessages"] = "0"
 	expectedAttributes["ApproximateNumberOfMessagesNotVisible"] = "0"
-	expectedAttributes["CreatedTimestamp"] = "0000000000"
-	expectedAttributes["LastModifiedTimestamp"] = "0000000000"
+	expectedAttributes["CreatedTimestamp"] = ""
+	expectedAttributes["LastModifiedTimestamp"] = ""
 	expectedAttributes["QueueArn"] = fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, af.QueueName)
 	assert.Nil(t, err)
+	blankQueueTimestamps(t, sdkResponse.Attributes)
 	assert.Equal(t, expectedAttributes, sdkResponse.Attributes)
 }
 
@@ -171,7 +174,7 @@ func Test_GetQueueAttributes_xml_all(t *testing.T) {
 	attributes := map[string]string{
 		"DelaySeconds":                  "1",
 		"MaximumMessageSize":            "2",
-		"MessageRetentionPeriod":        "3",
+		"MessageRetentionPeriod":        "60",
 		"ReceiveMessageWaitTimeSeconds": "4",
 		"VisibilityTimeout":             "5",
 		"RedrivePolicy":                 fmt.Sprintf(`{"maxReceiveCount":"100","deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue),
@@ -192,7 +195,7 @@ func Test_GetQueueAttributes_xml_all(t *testing.T) {
 	expectedResponse, _ := dupe.(models.GetQueueAttributesResponse)
 	expectedResponse.Result.Attrs[0].Value = "1"
 	expectedResponse.Result.Attrs[1].Value = "2"
-	expectedResponse.Result.Attrs[2].Value = "3"
+	expectedResponse.Result.Attrs[2].Value = "60"
 	expectedResponse.Result.Attrs[3].Value = "4"
 	expectedResponse.Result.Attrs[4].Value = "5"
 	expectedResponse.Result.Attrs = append(expectedResponse.Result.Attrs, models.Attribute{
@@ -202,6 +205,7 @@ func Test_GetQueueAttributes_xml_all(t *testing.T) {
 
 	r1 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r1)
+	blankQueueTimestampAttributes(t, r1.Result.Attrs)
 	assert.Equal(t, expectedResponse, r1)
 }
 
@@ -221,7 +225,7 @@ func Test_GetQueueAttributes_xml_select_attributes(t *testing.T) {
 	attributes := map[string]string{
 		"DelaySeconds":           "1",
 		"MaximumMessageSize":     "2",
-		"MessageRetentionPeriod": "3",
+		"MessageRetentionPeriod": "60",
 		//"Policy":                        "{\"this-is\": \"the-policy\"}",
 		"ReceiveMessageWaitTimeSeconds": "4",
 		"VisibilityTimeout":             "5",
@@ -264,6 +268,7 @@ func Test_GetQueueAttributes_xml_select_attributes(t *testing.T) {
 
 	r1 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r1)
+	blankQueueTimestampAttributes(t, r1.Result.Attrs)
 	assert.Equal(t, expectedResponse, r1)
 }
 
@@ -287,7 +292,7 @@ func Test_GetQueueAttributes_xml_missing_attribute_name_returns_all(t *testing.T
 	attributes := map[string]string{
 		"DelaySeconds":           "1",
 		"MaximumMessageSize":     "2",
-		"MessageRetentionPeriod": "3",
+		"MessageRetentionPeriod": "60",
 		//"Policy":                        "{\"this-is\": \"the-policy\"}",
 		"ReceiveMessageWaitTimeSeconds": "4",
 		"VisibilityTimeout":             "5",
@@ -318,7 +323,7 @@ func Test_GetQueueAttributes_xml_missing_attribute_name_returns_all(t *testing.T
 	expectedResponse, _ := dupe.(models.GetQueueAttributesResponse)
 	expectedResponse.Result.Attrs[0].Value = "1"
 	expectedResponse.Result.Attrs[1].Value = "2"
-	expectedResponse.Result.Attrs[2].Value = "3"
+	expectedResponse.Result.Attrs[2].Value = "60"
 	expectedResponse.Result.Attrs[3].Value = "4"
 	expectedResponse.Result.Attrs[4].Value = "5"
 	expectedResponse.Result.Attrs = append(expectedResponse.Result.Attrs, models.Attribute{
@@ -328,5 +333,6 @@ func Test_GetQueueAttributes_xml_missing_attribute_name_returns_all(t *testing.T
 
 	r1 := models.GetQueueAttributesResponse{}
 	xml.Unmarshal([]byte(r), &r1)
+	blankQueueTimestampAttributes(t, r1.Result.Attrs)
 	assert.Equal(t, expectedResponse, r1)
 }
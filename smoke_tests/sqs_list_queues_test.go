@@ -219,3 +219,108 @@ func Test_ListQueues_xml_multiple_queues_with_queue_name_prefix(t *testing.T) {
 	xml.Unmarshal([]byte(r), &response)
 	assert.Equal(t, expected, response)
 }
+
+func Test_ListQueues_json_paginates_in_name_order(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	expected := make([]string, 0)
+	for i := 5; i > 0; i-- {
+		queueName := fmt.Sprintf("paged-queue-%d", i)
+		sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+			QueueName: &queueName,
+		})
+		expected = append([]string{fmt.Sprintf("%s/%s", af.BASE_URL, queueName)}, expected...)
+	}
+
+	pages := 0
+	queueUrls := make([]string, 0)
+	paginator := sqs.NewListQueuesPaginator(sqsClient, &sqs.ListQueuesInput{MaxResults: aws.Int32(2)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.TODO())
+		assert.Nil(t, err)
+		pages++
+		queueUrls = append(queueUrls, page.QueueUrls...)
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, expected, queueUrls)
+}
+
+func Test_ListQueues_json_invalid_max_results(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	sdkResponse, err := sqsClient.ListQueues(context.TODO(), &sqs.ListQueuesInput{MaxResults: aws.Int32(1001)})
+
+	assert.Nil(t, sdkResponse)
+	assert.Contains(t, err.Error(), "MaxResults")
+}
+
+func Test_ListQueues_xml_paginates_in_name_order(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	for _, queueName := range []string{"queue-c", "queue-a", "queue-b"} {
+		name := queueName
+		sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+			QueueName: &name,
+		})
+	}
+
+	body := struct {
+		Action     string `xml:"Action"`
+		Version    string `xml:"Version"`
+		MaxResults int    `xml:"MaxResults"`
+		NextToken  string `xml:"NextToken"`
+	}{
+		Action:     "ListQueues",
+		Version:    "2012-11-05",
+		MaxResults: 2,
+	}
+	r := e.POST("/").
+		WithForm(body).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	response := models.ListQueuesResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, []string{fmt.Sprintf("%s/queue-a", af.BASE_URL), fmt.Sprintf("%s/queue-b", af.BASE_URL)}, response.Result.QueueUrls)
+	assert.NotEmpty(t, response.Result.NextToken)
+
+	body.NextToken = response.Result.NextToken
+	r = e.POST("/").
+		WithForm(body).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	response = models.ListQueuesResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, []string{fmt.Sprintf("%s/queue-c", af.BASE_URL)}, response.Result.QueueUrls)
+	assert.Empty(t, response.Result.NextToken)
+}
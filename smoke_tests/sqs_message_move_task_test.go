@@ -0,0 +1,76 @@
+package smoke_tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app/test"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MessageMoveTask_json_dead_letter_sources_and_tasks(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	dlq, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: aws.String("move-task-dlq"),
+	})
+	dlqAttributes, _ := sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       dlq.QueueUrl,
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	dlqArn := dlqAttributes.Attributes["QueueArn"]
+	source, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: aws.String("move-task-source"),
+		Attributes: map[string]string{
+			"RedrivePolicy": fmt.Sprintf(`{"maxReceiveCount": 1, "deadLetterTargetArn":"%s"}`, dlqArn),
+		},
+	})
+
+	sourcesResponse, err := sqsClient.ListDeadLetterSourceQueues(context.TODO(), &sqs.ListDeadLetterSourceQueuesInput{
+		QueueUrl: dlq.QueueUrl,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{*source.QueueUrl}, sourcesResponse.QueueUrls)
+
+	startResponse, err := sqsClient.StartMessageMoveTask(context.TODO(), &sqs.StartMessageMoveTaskInput{
+		SourceArn:                    &dlqArn,
+		MaxNumberOfMessagesPerSecond: aws.Int32(10),
+	})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, *startResponse.TaskHandle)
+
+	listResponse, err := sqsClient.ListMessageMoveTasks(context.TODO(), &sqs.ListMessageMoveTasksInput{
+		SourceArn: &dlqArn,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, listResponse.Results, 1)
+	assert.Equal(t, "RUNNING", *listResponse.Results[0].Status)
+	assert.Equal(t, *startResponse.TaskHandle, *listResponse.Results[0].TaskHandle)
+	assert.Equal(t, int32(10), *listResponse.Results[0].MaxNumberOfMessagesPerSecond)
+
+	cancelResponse, err := sqsClient.CancelMessageMoveTask(context.TODO(), &sqs.CancelMessageMoveTaskInput{
+		TaskHandle: startResponse.TaskHandle,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), cancelResponse.ApproximateNumberOfMessagesMoved)
+
+	_, err = sqsClient.StartMessageMoveTask(context.TODO(), &sqs.StartMessageMoveTaskInput{
+		SourceArn: source.QueueUrl,
+	})
+	assert.NotNil(t, err)
+}
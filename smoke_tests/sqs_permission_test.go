@@ -0,0 +1,178 @@
+package smoke_tests
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app"
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/Admiral-Piett/goaws/app/test"
+	"github.com/gavv/httpexpect/v2"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/stretchr/testify/assert"
+
+	af "github.com/Admiral-Piett/goaws/app/fixtures"
+)
+
+func Test_AddPermissionV1_json_add_get_remove(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+
+	_, err := sqsClient.AddPermission(context.TODO(), &sqs.AddPermissionInput{
+		QueueUrl:      createQueueResponse.QueueUrl,
+		Label:         aws.String("send"),
+		AWSAccountIds: []string{"200020002000"},
+		Actions:       []string{"SendMessage"},
+	})
+	assert.Nil(t, err)
+
+	_, err = sqsClient.AddPermission(context.TODO(), &sqs.AddPermissionInput{
+		QueueUrl:      createQueueResponse.QueueUrl,
+		Label:         aws.String("send"),
+		AWSAccountIds: []string{"300030003000"},
+		Actions:       []string{"SendMessage"},
+	})
+	assert.Contains(t, err.Error(), "400")
+
+	attributesResponse, err := sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       createQueueResponse.QueueUrl,
+		AttributeNames: []types.QueueAttributeName{"Policy"},
+	})
+	assert.Nil(t, err)
+
+	policy := app.PolicyDocument{}
+	err = json.Unmarshal([]byte(attributesResponse.Attributes["Policy"]), &policy)
+	assert.Nil(t, err)
+	assert.Len(t, policy.Statement, 1)
+	assert.Equal(t, "send", policy.Statement[0].Sid)
+	assert.Equal(t, app.PolicyValues{"arn:aws:iam::200020002000:root"}, policy.Statement[0].Principal.AWS)
+	assert.Equal(t, app.PolicyValues{"SQS:SendMessage"}, policy.Statement[0].Action)
+
+	_, err = sqsClient.RemovePermission(context.TODO(), &sqs.RemovePermissionInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+		Label:    aws.String("send"),
+	})
+	assert.Nil(t, err)
+
+	attributesResponse, _ = sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       createQueueResponse.QueueUrl,
+		AttributeNames: []types.QueueAttributeName{"Policy"},
+	})
+	assert.Equal(t, map[string]string{}, attributesResponse.Attributes)
+
+	_, err = sqsClient.RemovePermission(context.TODO(), &sqs.RemovePermissionInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+		Label:    aws.String("send"),
+	})
+	assert.Contains(t, err.Error(), "400")
+}
+
+func Test_AddPermissionV1_xml_add_remove(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+
+	r := e.POST("/").
+		WithFormField("Action", "AddPermission").
+		WithFormField("QueueUrl", *createQueueResponse.QueueUrl).
+		WithFormField("Label", "receive").
+		WithFormField("AWSAccountId.1", "200020002000").
+		WithFormField("AWSAccountId.2", "300030003000").
+		WithFormField("ActionName.1", "ReceiveMessage").
+		WithFormField("ActionName.2", "DeleteMessage").
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	response := models.AddPermissionResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, models.BASE_RESPONSE_METADATA, response.Metadata)
+
+	queue := app.SyncQueues.Queues[af.QueueName]
+	assert.True(t, queue.Allows(app.PolicyRequest{Action: "sqs:DeleteMessage", AccountId: "300030003000"}))
+	assert.False(t, queue.Allows(app.PolicyRequest{Action: "sqs:SendMessage", AccountId: "300030003000"}))
+
+	e.POST("/").
+		WithFormField("Action", "RemovePermission").
+		WithFormField("QueueUrl", *createQueueResponse.QueueUrl).
+		WithFormField("Label", "receive").
+		Expect().
+		Status(http.StatusOK)
+
+	assert.Equal(t, "", queue.Policy)
+}
+
+func Test_PolicyEvaluation_json_denies_other_accounts(t *testing.T) {
+	server := generateServer()
+	previous := app.CurrentEnvironment.PolicyEvaluation
+	defer func() {
+		server.Close()
+		test.ResetResources()
+		app.CurrentEnvironment.PolicyEvaluation = previous
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+	sqsClient.AddPermission(context.TODO(), &sqs.AddPermissionInput{
+		QueueUrl:      createQueueResponse.QueueUrl,
+		Label:         aws.String("send"),
+		AWSAccountIds: []string{"200020002000"},
+		Actions:       []string{"SendMessage"},
+	})
+
+	app.CurrentEnvironment.PolicyEvaluation = app.EnvPolicyEvaluation{Enabled: true, CallerAccountId: "200020002000"}
+
+	_, err := sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:    createQueueResponse.QueueUrl,
+		MessageBody: aws.String("allowed"),
+	})
+	assert.Nil(t, err)
+
+	_, err = sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+	})
+	assert.Contains(t, err.Error(), "AccessDenied")
+
+	app.CurrentEnvironment.PolicyEvaluation.CallerAccountId = "300030003000"
+	_, err = sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:    createQueueResponse.QueueUrl,
+		MessageBody: aws.String("denied"),
+	})
+	assert.Contains(t, err.Error(), "AccessDenied")
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
-	"time"
 
 	"github.com/Admiral-Piett/goaws/app/test"
 
@@ -25,9 +24,7 @@ import (
 
 func Test_PurgeQueueV1_json(t *testing.T) {
 	defaultEnvironment := app.CurrentEnvironment
-	app.CurrentEnvironment = app.Environment{
-		EnableDuplicates: true,
-	}
+	app.CurrentEnvironment = app.Environment{}
 	server := generateServer()
 	defer func() {
 		server.Close()
@@ -46,10 +43,12 @@ func Test_PurgeQueueV1_json(t *testing.T) {
 
 	messageBody := "test-message"
 	dedupeId := "dedupe-id"
+	groupId := "group-id"
 	sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
 		QueueUrl:               &qName,
 		MessageBody:            &messageBody,
 		MessageDeduplicationId: &dedupeId,
+		MessageGroupId:         &groupId,
 	})
 
 	sdkResponse, err := sqsClient.PurgeQueue(context.TODO(), &sqs.PurgeQueueInput{
@@ -62,15 +61,13 @@ func Test_PurgeQueueV1_json(t *testing.T) {
 	app.SyncQueues.Lock()
 	defer app.SyncQueues.Unlock()
 	targetQueue := app.SyncQueues.Queues[qName]
-	assert.Nil(t, targetQueue.Messages)
-	assert.Equal(t, map[string]time.Time{}, targetQueue.Duplicates)
+	assert.Equal(t, 0, targetQueue.Messages.Len())
+	assert.Equal(t, map[string]app.Deduplicated{}, targetQueue.Duplicates)
 }
 
 func Test_PurgeQueueV1_xml(t *testing.T) {
 	defaultEnvironment := app.CurrentEnvironment
-	app.CurrentEnvironment = app.Environment{
-		EnableDuplicates: true,
-	}
+	app.CurrentEnvironment = app.Environment{}
 	server := generateServer()
 	defer func() {
 		server.Close()
@@ -91,10 +88,12 @@ func Test_PurgeQueueV1_xml(t *testing.T) {
 
 	messageBody := "test-message"
 	dedupeId := "dedupe-id"
+	groupId := "group-id"
 	sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
 		QueueUrl:               &qName,
 		MessageBody:            &messageBody,
 		MessageDeduplicationId: &dedupeId,
+		MessageGroupId:         &groupId,
 	})
 
 	r := e.POST("/").
@@ -120,6 +119,6 @@ func Test_PurgeQueueV1_xml(t *testing.T) {
 	app.SyncQueues.Lock()
 	defer app.SyncQueues.Unlock()
 	targetQueue := app.SyncQueues.Queues[qName]
-	assert.Nil(t, targetQueue.Messages)
-	assert.Equal(t, map[string]time.Time{}, targetQueue.Duplicates)
+	assert.Equal(t, 0, targetQueue.Messages.Len())
+	assert.Equal(t, map[string]app.Deduplicated{}, targetQueue.Duplicates)
 }
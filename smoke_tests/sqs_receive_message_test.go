@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/Admiral-Piett/goaws/app"
 	"github.com/Admiral-Piett/goaws/app/test"
 
 	af "github.com/Admiral-Piett/goaws/app/fixtures"
@@ -16,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/gavv/httpexpect/v2"
 	"github.com/stretchr/testify/assert"
 )
@@ -129,3 +132,50 @@ func Test_ReceiveMessageV1_xml(t *testing.T) {
 	assert.Equal(t, 1, len(r1.Result.Messages))
 	assert.Equal(t, sf.SendMessageRequestBodyXML.MessageBody, string(r1.Result.Messages[0].Body))
 }
+
+func Test_ReceiveMessageV1_json_filters_attributes_and_overrides_visibility(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+	sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:    createQueueResponse.QueueUrl,
+		MessageBody: aws.String("message-1"),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"trace.id":   {DataType: aws.String("String"), StringValue: aws.String("abc")},
+			"trace.span": {DataType: aws.String("Number"), StringValue: aws.String("1")},
+			"other":      {DataType: aws.String("String"), StringValue: aws.String("value")},
+		},
+	})
+
+	receiveResponse, err := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:              createQueueResponse.QueueUrl,
+		VisibilityTimeout:     1,
+		AttributeNames:        []types.QueueAttributeName{"SentTimestamp"},
+		MessageAttributeNames: []string{"trace.*"},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, receiveResponse.Messages, 1)
+	message := receiveResponse.Messages[0]
+	assert.Len(t, message.Attributes, 1)
+	assert.Contains(t, message.Attributes, "SentTimestamp")
+	assert.Len(t, message.MessageAttributes, 2)
+	assert.Contains(t, message.MessageAttributes, "trace.id")
+	assert.Contains(t, message.MessageAttributes, "trace.span")
+
+	queue, _ := app.SyncQueues.Get(af.QueueName)
+	queue.RLock()
+	visibleAgainAt := queue.Messages.All()[0].VisibilityTimeout
+	queue.RUnlock()
+	assert.True(t, visibleAgainAt.Before(time.Now().Add(2*time.Second)))
+}
@@ -294,8 +294,9 @@ func TestSendMessageBatchV1_Json_Success(t *testing.T) {
 	assert.Equal(t, "2", getQueueAttributeOutput.Attributes["ApproximateNumberOfMessages"])
 
 	receiveMessageOutput, _ := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-		QueueUrl:            &queueUrl,
-		MaxNumberOfMessages: 10,
+		QueueUrl:              &queueUrl,
+		MaxNumberOfMessages:   10,
+		MessageAttributeNames: []string{"All"},
 	})
 
 	receivedMessage1 := receiveMessageOutput.Messages[0]
@@ -330,7 +331,7 @@ func TestSendMessageBatchV1_Json_Success(t *testing.T) {
 	}
 	assert.Equal(t, binaryAttribute, attr1.Name)
 	assert.Equal(t, binaryType, attr1.Value.DataType)
-	assert.Equal(t, "YmFzZTY0LWVuY29kZWQtdmFsdWU=", attr1.Value.BinaryValue) // base64 encoded value
+	assert.Equal(t, "base64-encoded-value", attr1.Value.BinaryValue) // decoded by the SDK
 
 	assert.Equal(t, stringAttribute, attr2.Name)
 	assert.Equal(t, stringType, attr2.Value.DataType)
@@ -408,7 +409,7 @@ func TestSendMessageBatchV1_Xml_Success(t *testing.T) {
 
 	xml.Unmarshal([]byte(r), &response)
 
-	assert.NotNil(t, response.Result.Entry[0].MessageId)
+	assert.NotNil(t, response.Result.Successful[0].MessageId)
 
 	// Assert 1 message in the queue
 	getQueueAttributeOutput, _ := sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
@@ -417,8 +418,9 @@ func TestSendMessageBatchV1_Xml_Success(t *testing.T) {
 	assert.Equal(t, "2", getQueueAttributeOutput.Attributes["ApproximateNumberOfMessages"])
 
 	receiveMessageOutput, _ := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-		QueueUrl:            &af.QueueUrl,
-		MaxNumberOfMessages: 10,
+		QueueUrl:              &af.QueueUrl,
+		MaxNumberOfMessages:   10,
+		MessageAttributeNames: []string{"All"},
 	})
 
 	receivedMessage1 := receiveMessageOutput.Messages[0]
@@ -453,7 +455,7 @@ func TestSendMessageBatchV1_Xml_Success(t *testing.T) {
 	}
 	assert.Equal(t, binaryAttribute, attr1.Name)
 	assert.Equal(t, binaryType, attr1.Value.DataType)
-	assert.Equal(t, "YmFzZTY0LWVuY29kZWQtdmFsdWU=", attr1.Value.BinaryValue) // base64 encoded value
+	assert.Equal(t, "base64-encoded-value", attr1.Value.BinaryValue) // decoded by the SDK
 
 	assert.Equal(t, stringAttribute, attr2.Name)
 	assert.Equal(t, stringType, attr2.Value.DataType)
@@ -464,3 +466,61 @@ func TestSendMessageBatchV1_Xml_Success(t *testing.T) {
 	assert.Equal(t, numberValue, attr3.Value.StringValue)
 
 }
+
+func TestSendMessageBatchV1_Json_entries_delay_trace_header_and_failures(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+
+	sendMessageBatchOutput, err := sqsClient.SendMessageBatch(context.TODO(), &sqs.SendMessageBatchInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+		Entries: []types.SendMessageBatchRequestEntry{
+			{
+				Id:          aws.String("test_msg_001"),
+				MessageBody: aws.String("message-1"),
+				MessageSystemAttributes: map[string]types.MessageSystemAttributeValue{
+					"AWSTraceHeader": {DataType: aws.String("String"), StringValue: aws.String("Root=1-5759e988-bd862e3fe1be46a994272793")},
+				},
+			},
+			{
+				Id:           aws.String("test_msg_002"),
+				MessageBody:  aws.String("message-2"),
+				DelaySeconds: 60,
+			},
+			{
+				Id:           aws.String("test_msg_003"),
+				MessageBody:  aws.String("message-3"),
+				DelaySeconds: 901,
+			},
+		},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, sendMessageBatchOutput.Successful, 2)
+	assert.Equal(t, "test_msg_001", *sendMessageBatchOutput.Successful[0].Id)
+	assert.Equal(t, "test_msg_002", *sendMessageBatchOutput.Successful[1].Id)
+	assert.Len(t, sendMessageBatchOutput.Failed, 1)
+	assert.Equal(t, "test_msg_003", *sendMessageBatchOutput.Failed[0].Id)
+	assert.Equal(t, "InvalidParameterValue", *sendMessageBatchOutput.Failed[0].Code)
+	assert.True(t, sendMessageBatchOutput.Failed[0].SenderFault)
+
+	// Only the first is visible, the second is delayed.
+	receiveMessageOutput, err := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:            createQueueResponse.QueueUrl,
+		MaxNumberOfMessages: 10,
+		AttributeNames:      []types.QueueAttributeName{"AWSTraceHeader"},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, receiveMessageOutput.Messages, 1)
+	assert.Equal(t, "message-1", *receiveMessageOutput.Messages[0].Body)
+	assert.Equal(t, "Root=1-5759e988-bd862e3fe1be46a994272793", receiveMessageOutput.Messages[0].Attributes["AWSTraceHeader"])
+}
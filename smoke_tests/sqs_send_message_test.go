@@ -118,7 +118,8 @@ func Test_SendMessageV1_json_with_attributes(t *testing.T) {
 
 	// Receive message and check attribute
 	r3, _ := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
-		QueueUrl: targetQueueUrl,
+		QueueUrl:              targetQueueUrl,
+		MessageAttributeNames: []string{"All"},
 	})
 	message := r3.Messages[0]
 	assert.Equal(t, targetMessageBody, string(*message.Body))
@@ -143,7 +144,6 @@ func Test_SendMessageV1_json_with_attributes(t *testing.T) {
 			attr3.Name = k
 			attr3.Value = &models.ResultMessageAttributeValue{
 				DataType:    *attr.DataType,
-				StringValue: *attr.StringValue,
 				BinaryValue: string(attr.BinaryValue),
 			}
 		}
@@ -156,7 +156,8 @@ func Test_SendMessageV1_json_with_attributes(t *testing.T) {
 	assert.Equal(t, "2", attr2.Value.StringValue)
 	assert.Equal(t, "attr3", attr3.Name)
 	assert.Equal(t, "Binary", attr3.Value.DataType)
-	assert.Equal(t, "YXR0cjNfdmFsdWU=", attr3.Value.BinaryValue) // base64 encoded "attr3_value"
+	assert.Equal(t, "attr3_value", attr3.Value.BinaryValue)
+	assert.Nil(t, message.MessageAttributes["attr3"].StringValue)
 }
 
 func Test_SendMessageV1_json_MaximumMessageSize_TooBig(t *testing.T) {
@@ -330,6 +331,7 @@ func Test_SendMessageV1_xml_with_attributes(t *testing.T) {
 	}
 	r = e.POST("/").
 		WithForm(receiveMessageBodyXML).
+		WithFormField("MessageAttributeName.1", "All").
 		Expect().
 		Status(http.StatusOK).
 		Body().Raw()
@@ -358,3 +360,116 @@ func Test_SendMessageV1_xml_with_attributes(t *testing.T) {
 	assert.Equal(t, "Binary", attr3.Value.DataType)
 	assert.Equal(t, "YXR0cjNfdmFsdWU=", attr3.Value.BinaryValue) // base64 encoded "attr3_value"
 }
+
+func Test_SendMessageV1_json_with_custom_type_and_list_attributes(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+	sdkResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+
+	// The SDK checks the MD5 of the attributes against its own on both the send and the receive.
+	attributes := map[string]sqstypes.MessageAttributeValue{
+		"json": {
+			DataType:    aws.String("String.json"),
+			StringValue: aws.String(`{"key": "value"}`),
+		},
+		"float": {
+			DataType:    aws.String("Number.float"),
+			StringValue: aws.String("1.5"),
+		},
+		"gif": {
+			DataType:    aws.String("Binary.gif"),
+			BinaryValue: []byte{0x47, 0x49, 0x46, 0x00, 0xff},
+		},
+		"strings": {
+			DataType:         aws.String("String"),
+			StringListValues: []string{"a", "b"},
+		},
+		"binaries": {
+			DataType:         aws.String("Binary"),
+			BinaryListValues: [][]byte{{0x00}, {0x01, 0x02}},
+		},
+	}
+	sendMessageOutput, err := sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:          sdkResponse.QueueUrl,
+		MessageBody:       aws.String("Test_SendMessageV1_json_with_custom_type_and_list_attributes"),
+		MessageAttributes: attributes,
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, sendMessageOutput.MD5OfMessageAttributes)
+
+	receiveMessageOutput, err := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl:              sdkResponse.QueueUrl,
+		MessageAttributeNames: []string{"All"},
+	})
+	assert.Nil(t, err)
+	message := receiveMessageOutput.Messages[0]
+	assert.Equal(t, *sendMessageOutput.MD5OfMessageAttributes, *message.MD5OfMessageAttributes)
+	assert.Len(t, message.MessageAttributes, 5)
+	for name, attr := range attributes {
+		received := message.MessageAttributes[name]
+		assert.Equal(t, *attr.DataType, *received.DataType)
+		assert.Equal(t, attr.StringValue, received.StringValue)
+		assert.Equal(t, attr.BinaryValue, received.BinaryValue)
+		assert.Equal(t, attr.StringListValues, received.StringListValues)
+		assert.Equal(t, attr.BinaryListValues, received.BinaryListValues)
+	}
+}
+
+func Test_SendMessageV1_json_aws_limits(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+	sdkResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+
+	_, err := sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:    sdkResponse.QueueUrl,
+		MessageBody: aws.String("bell \x07"),
+	})
+	assert.Contains(t, err.Error(), "InvalidMessageContents")
+
+	_, err = sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:     sdkResponse.QueueUrl,
+		MessageBody:  aws.String("late"),
+		DelaySeconds: 901,
+	})
+	assert.Contains(t, err.Error(), "InvalidParameterValue")
+
+	attributes := map[string]sqstypes.MessageAttributeValue{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"} {
+		attributes[name] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(name)}
+	}
+	_, err = sqsClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:          sdkResponse.QueueUrl,
+		MessageBody:       aws.String("crowded"),
+		MessageAttributes: attributes,
+	})
+	assert.Contains(t, err.Error(), "InvalidParameterValue")
+
+	_, err = sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: aws.String("not a valid name"),
+	})
+	assert.Contains(t, err.Error(), "InvalidParameterValue")
+
+	receiveMessageOutput, err := sqsClient.ReceiveMessage(context.TODO(), &sqs.ReceiveMessageInput{
+		QueueUrl: sdkResponse.QueueUrl,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, receiveMessageOutput.Messages, 0)
+}
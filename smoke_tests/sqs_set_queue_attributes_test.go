@@ -43,14 +43,14 @@ func Test_SetQueueAttributes_json_multiple(t *testing.T) {
 		QueueName: &queueName,
 	})
 	attributes := map[string]string{
-		"DelaySeconds":           "1",
-		"MaximumMessageSize":     "2",
-		"MessageRetentionPeriod": "3",
-		//"Policy":                        "{\"this-is\": \"the-policy\"}",
+		"DelaySeconds":                  "1",
+		"MaximumMessageSize":            "2",
+		"MessageRetentionPeriod":        "60",
+		"Policy":                        af.QueuePolicy,
 		"ReceiveMessageWaitTimeSeconds": "4",
 		"VisibilityTimeout":             "5",
 		"RedrivePolicy":                 fmt.Sprintf(`{"maxReceiveCount":"100","deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue),
-		//"RedriveAllowPolicy":            "{\"this-is\": \"the-redrive-allow-policy\"}",
+		"RedriveAllowPolicy":            af.QueueRedriveAllowPolicy,
 	}
 
 	queueUrl := fmt.Sprintf("%s/%s", af.BASE_URL, queueName)
@@ -71,10 +71,11 @@ func Test_SetQueueAttributes_json_multiple(t *testing.T) {
 	expectedAttributes["RedrivePolicy"] = fmt.Sprintf(`{"maxReceiveCount":"100", "deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue)
 	expectedAttributes["ApproximateNumberOfMessages"] = "0"
 	expectedAttributes["ApproximateNumberOfMessagesNotVisible"] = "0"
-	expectedAttributes["CreatedTimestamp"] = "0000000000"
-	expectedAttributes["LastModifiedTimestamp"] = "0000000000"
+	expectedAttributes["CreatedTimestamp"] = ""
+	expectedAttributes["LastModifiedTimestamp"] = ""
 	expectedAttributes["QueueArn"] = fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, queueName)
 	assert.Nil(t, err)
+	blankQueueTimestamps(t, sdkResponse.Attributes)
 	assert.Equal(t, expectedAttributes, sdkResponse.Attributes)
 }
 
@@ -118,10 +119,11 @@ func Test_SetQueueAttributes_json_single(t *testing.T) {
 	expectedAttributes["MessageRetentionPeriod"] = "0"
 	expectedAttributes["ApproximateNumberOfMessages"] = "0"
 	expectedAttributes["ApproximateNumberOfMessagesNotVisible"] = "0"
-	expectedAttributes["CreatedTimestamp"] = "0000000000"
-	expectedAttributes["LastModifiedTimestamp"] = "0000000000"
+	expectedAttributes["CreatedTimestamp"] = ""
+	expectedAttributes["LastModifiedTimestamp"] = ""
 	expectedAttributes["QueueArn"] = fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, queueName)
 	assert.Nil(t, err)
+	blankQueueTimestamps(t, sdkResponse.Attributes)
 	assert.Equal(t, expectedAttributes, sdkResponse.Attributes)
 }
 
@@ -155,15 +157,15 @@ func Test_SetQueueAttributes_xml_all(t *testing.T) {
 		WithFormField("Attribute.3.Name", "DelaySeconds").
 		WithFormField("Attribute.3.Value", "1").
 		WithFormField("Attribute.4.Name", "MessageRetentionPeriod").
-		WithFormField("Attribute.4.Value", "3").
+		WithFormField("Attribute.4.Value", "60").
 		WithFormField("Attribute.5.Name", "Policy").
-		WithFormField("Attribute.5.Value", "{\"this-is\": \"the-policy\"}").
+		WithFormField("Attribute.5.Value", af.QueuePolicy).
 		WithFormField("Attribute.6.Name", "ReceiveMessageWaitTimeSeconds").
 		WithFormField("Attribute.6.Value", "4").
 		WithFormField("Attribute.7.Name", "RedrivePolicy").
 		WithFormField("Attribute.7.Value", fmt.Sprintf("{\"maxReceiveCount\": 100, \"deadLetterTargetArn\":\"%s:%s\"}", af.BASE_SQS_ARN, redriveQueue)).
 		WithFormField("Attribute.8.Name", "RedriveAllowPolicy").
-		WithFormField("Attribute.8.Value", "{\"this-is\": \"the-redrive-allow-policy\"}").
+		WithFormField("Attribute.8.Value", af.QueueRedriveAllowPolicy).
 		Expect().
 		Status(http.StatusOK).
 		Body().Raw()
@@ -174,21 +176,22 @@ func Test_SetQueueAttributes_xml_all(t *testing.T) {
 	})
 
 	expectedAttributes := map[string]string{
-		"DelaySeconds":           "1",
-		"MaximumMessageSize":     "2",
-		"MessageRetentionPeriod": "3",
-		//"Policy":                        "{\"this-is\": \"the-policy\"}",
-		"ReceiveMessageWaitTimeSeconds": "4",
-		"VisibilityTimeout":             "5",
-		"RedrivePolicy":                 fmt.Sprintf(`{"maxReceiveCount":"100", "deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue),
-		//"RedriveAllowPolicy":            "{\"this-is\": \"the-redrive-allow-policy\"}",
+		"DelaySeconds":                          "1",
+		"MaximumMessageSize":                    "2",
+		"MessageRetentionPeriod":                "60",
+		"Policy":                                af.QueuePolicy,
+		"ReceiveMessageWaitTimeSeconds":         "4",
+		"VisibilityTimeout":                     "5",
+		"RedrivePolicy":                         fmt.Sprintf(`{"maxReceiveCount":"100", "deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, redriveQueue),
+		"RedriveAllowPolicy":                    af.QueueRedriveAllowPolicy,
 		"ApproximateNumberOfMessages":           "0",
 		"ApproximateNumberOfMessagesNotVisible": "0",
-		"CreatedTimestamp":                      "0000000000",
-		"LastModifiedTimestamp":                 "0000000000",
+		"CreatedTimestamp":                      "",
+		"LastModifiedTimestamp":                 "",
 		"QueueArn":                              fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, af.QueueName),
 	}
 	assert.Nil(t, err)
+	blankQueueTimestamps(t, sdkResponse.Attributes)
 	assert.Equal(t, expectedAttributes, sdkResponse.Attributes)
 }
 
@@ -234,10 +237,71 @@ func Test_SetQueueAttributes_xml_single(t *testing.T) {
 		"VisibilityTimeout":                     "5",
 		"ApproximateNumberOfMessages":           "0",
 		"ApproximateNumberOfMessagesNotVisible": "0",
-		"CreatedTimestamp":                      "0000000000",
-		"LastModifiedTimestamp":                 "0000000000",
+		"CreatedTimestamp":                      "",
+		"LastModifiedTimestamp":                 "",
 		"QueueArn":                              fmt.Sprintf("%s:%s", af.BASE_SQS_ARN, af.QueueName),
 	}
 	assert.Nil(t, err)
+	blankQueueTimestamps(t, sdkResponse.Attributes)
 	assert.Equal(t, expectedAttributes, sdkResponse.Attributes)
 }
+
+func Test_SetQueueAttributes_json_redrive_allow_policy(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	dlqName := "dead-letters"
+	dlq, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &dlqName,
+		Attributes: map[string]string{
+			"RedriveAllowPolicy": fmt.Sprintf(`{"redrivePermission":"byQueue","sourceQueueArns":["%s:%s"]}`, af.BASE_SQS_ARN, af.QueueName),
+		},
+	})
+	redrivePolicy := fmt.Sprintf(`{"maxReceiveCount":"3","deadLetterTargetArn":"%s:%s"}`, af.BASE_SQS_ARN, dlqName)
+
+	otherQueueName := "other-queue"
+	_, err := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName:  &otherQueueName,
+		Attributes: map[string]string{"RedrivePolicy": redrivePolicy},
+	})
+	assert.Contains(t, err.Error(), "RedriveAllowPolicy")
+
+	fifoQueueName := "fifo-queue.fifo"
+	_, err = sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName:  &fifoQueueName,
+		Attributes: map[string]string{"FifoQueue": "true", "RedrivePolicy": redrivePolicy},
+	})
+	assert.Contains(t, err.Error(), "same type of queue")
+
+	_, err = sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName:  &af.QueueName,
+		Attributes: map[string]string{"RedrivePolicy": redrivePolicy},
+	})
+	assert.Nil(t, err)
+
+	_, err = sqsClient.SetQueueAttributes(context.TODO(), &sqs.SetQueueAttributesInput{
+		QueueUrl:   dlq.QueueUrl,
+		Attributes: map[string]string{"RedriveAllowPolicy": `{"redrivePermission":"byQueue"}`},
+	})
+	assert.Contains(t, err.Error(), "InvalidAttributeValue")
+
+	_, err = sqsClient.SetQueueAttributes(context.TODO(), &sqs.SetQueueAttributesInput{
+		QueueUrl:   dlq.QueueUrl,
+		Attributes: map[string]string{"RedriveAllowPolicy": `{"redrivePermission":"denyAll"}`},
+	})
+	assert.Nil(t, err)
+
+	sdkResponse, err := sqsClient.GetQueueAttributes(context.TODO(), &sqs.GetQueueAttributesInput{
+		QueueUrl:       dlq.QueueUrl,
+		AttributeNames: []types.QueueAttributeName{"RedriveAllowPolicy"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"RedriveAllowPolicy": `{"redrivePermission":"denyAll"}`}, sdkResponse.Attributes)
+}
@@ -0,0 +1,135 @@
+package smoke_tests
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Admiral-Piett/goaws/app/test"
+
+	"github.com/Admiral-Piett/goaws/app/models"
+	"github.com/gavv/httpexpect/v2"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"github.com/stretchr/testify/assert"
+
+	af "github.com/Admiral-Piett/goaws/app/fixtures"
+)
+
+func Test_TagQueueV1_json_tag_list_untag(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+		Tags:      map[string]string{"team": "platform"},
+	})
+
+	_, err := sqsClient.TagQueue(context.TODO(), &sqs.TagQueueInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+		Tags:     map[string]string{"env": "dev"},
+	})
+	assert.Nil(t, err)
+
+	listResponse, err := sqsClient.ListQueueTags(context.TODO(), &sqs.ListQueueTagsInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"team": "platform", "env": "dev"}, listResponse.Tags)
+
+	_, err = sqsClient.UntagQueue(context.TODO(), &sqs.UntagQueueInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+		TagKeys:  []string{"team"},
+	})
+	assert.Nil(t, err)
+
+	listResponse, _ = sqsClient.ListQueueTags(context.TODO(), &sqs.ListQueueTagsInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+	})
+	assert.Equal(t, map[string]string{"env": "dev"}, listResponse.Tags)
+}
+
+func Test_TagQueueV1_xml_tag_list_untag(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	e := httpexpect.Default(t, server.URL)
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+
+	e.POST("/").
+		WithFormField("Action", "TagQueue").
+		WithFormField("QueueUrl", *createQueueResponse.QueueUrl).
+		WithFormField("Tag.1.Key", "team").
+		WithFormField("Tag.1.Value", "platform").
+		WithFormField("Tag.2.Key", "env").
+		WithFormField("Tag.2.Value", "dev").
+		Expect().
+		Status(http.StatusOK)
+
+	e.POST("/").
+		WithFormField("Action", "UntagQueue").
+		WithFormField("QueueUrl", *createQueueResponse.QueueUrl).
+		WithFormField("TagKey.1", "team").
+		Expect().
+		Status(http.StatusOK)
+
+	r := e.POST("/").
+		WithFormField("Action", "ListQueueTags").
+		WithFormField("QueueUrl", *createQueueResponse.QueueUrl).
+		Expect().
+		Status(http.StatusOK).
+		Body().Raw()
+
+	response := models.ListQueueTagsResponse{}
+	xml.Unmarshal([]byte(r), &response)
+	assert.Equal(t, []models.QueueTag{{Key: "env", Value: "dev"}}, response.Result.Tags)
+}
+
+func Test_TagQueueV1_json_too_many_tags(t *testing.T) {
+	server := generateServer()
+	defer func() {
+		server.Close()
+		test.ResetResources()
+	}()
+
+	sdkConfig, _ := config.LoadDefaultConfig(context.TODO())
+	sdkConfig.BaseEndpoint = aws.String(server.URL)
+	sqsClient := sqs.NewFromConfig(sdkConfig)
+
+	createQueueResponse, _ := sqsClient.CreateQueue(context.TODO(), &sqs.CreateQueueInput{
+		QueueName: &af.QueueName,
+	})
+
+	tags := map[string]string{}
+	for i := 0; i < 51; i++ {
+		tags[fmt.Sprintf("key-%d", i)] = "value"
+	}
+	_, err := sqsClient.TagQueue(context.TODO(), &sqs.TagQueueInput{
+		QueueUrl: createQueueResponse.QueueUrl,
+		Tags:     tags,
+	})
+
+	assert.Contains(t, err.Error(), "400")
+}
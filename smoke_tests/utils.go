@@ -7,11 +7,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	urlLib "net/url"
+	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/Admiral-Piett/goaws/app/models"
 	"github.com/Admiral-Piett/goaws/app/router"
+	"github.com/Admiral-Piett/goaws/app/test"
 )
 
 func generateServer() *httptest.Server {
@@ -40,3 +44,24 @@ func GenerateLocalProxyConfig(proxyPort int) aws.Config {
 	)
 	return sdkConfig
 }
+
+// blankQueueTimestamps checks the queue timestamps among the attributes are from the moment the test ran,
+// then blanks them so that the rest can be compared with the fixtures.
+func blankQueueTimestamps(t *testing.T, attributes map[string]string) {
+	for _, name := range []string{"CreatedTimestamp", "LastModifiedTimestamp"} {
+		if value, ok := attributes[name]; ok {
+			assert.True(t, test.RecentTimestamp(value), name)
+			attributes[name] = ""
+		}
+	}
+}
+
+// blankQueueTimestampAttributes is blankQueueTimestamps for the attributes of an XML response.
+func blankQueueTimestampAttributes(t *testing.T, attributes []models.Attribute) {
+	for i, attr := range attributes {
+		if attr.Name == "CreatedTimestamp" || attr.Name == "LastModifiedTimestamp" {
+			assert.True(t, test.RecentTimestamp(attr.Value), attr.Name)
+			attributes[i].Value = ""
+		}
+	}
+}